@@ -0,0 +1,72 @@
+package main
+
+import (
+	"fmt"
+	"path"
+	"strconv"
+
+	"filesystemsimulator/filesystem_logic"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// Dialog Blocks: menampilkan struktur alokasi entri terpilih (rantai FAT, daftar extent, atau blok indeks beserta
+// pointer langsung dan tidak langsungnya) sebagai pohon yang bisa dibuka-tutup.
+func (p *diskPane) showAllocationDialog() {
+	setActivePane(p)
+	targetPath, ok := p.selectedPath()
+	if !ok {
+		dialog.ShowInformation("Info", "Select a file or folder first", myWindow)
+		return
+	}
+	root, err := p.fs.AllocationOf(targetPath)
+	if err != nil {
+		dialog.ShowError(err, myWindow)
+		return
+	}
+
+	// ID simpul pohon adalah urutan indeks anak dari akar, misalnya "0/2/5"; akar pohon adalah ""
+	nodes := map[widget.TreeNodeID]filesystem_logic.AllocationNode{"": {Children: []filesystem_logic.AllocationNode{root}}}
+	var index func(id widget.TreeNodeID, node filesystem_logic.AllocationNode)
+	index = func(id widget.TreeNodeID, node filesystem_logic.AllocationNode) {
+		nodes[id] = node
+		for i, child := range node.Children {
+			index(childID(id, i), child)
+		}
+	}
+	index("0", root)
+
+	tree := widget.NewTree(
+		func(id widget.TreeNodeID) []widget.TreeNodeID {
+			var ids []widget.TreeNodeID
+			for i := range nodes[id].Children {
+				ids = append(ids, childID(id, i))
+			}
+			return ids
+		},
+		func(id widget.TreeNodeID) bool { return len(nodes[id].Children) > 0 },
+		func(branch bool) fyne.CanvasObject { return widget.NewLabel("Template") },
+		func(id widget.TreeNodeID, branch bool, item fyne.CanvasObject) {
+			item.(*widget.Label).SetText(nodes[id].Label)
+		},
+	)
+	tree.OpenBranch("0")
+
+	method := p.fs.Geometry.Allocation.String()
+	summary := widget.NewLabel(fmt.Sprintf("%s • allocation: %s", targetPath, method))
+	content := container.NewBorder(summary, nil, nil, nil, tree)
+	d := dialog.NewCustom("Blocks: "+path.Base(targetPath), "Close", content, myWindow)
+	d.Resize(fyne.NewSize(520, 460))
+	d.Show()
+}
+
+// ID anak ke-i dari simpul id di pohon alokasi
+func childID(id widget.TreeNodeID, i int) widget.TreeNodeID {
+	if id == "" {
+		return strconv.Itoa(i)
+	}
+	return id + "/" + strconv.Itoa(i)
+}
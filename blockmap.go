@@ -0,0 +1,294 @@
+package main
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+
+	"filesystemsimulator/filesystem_logic"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/widget"
+)
+
+// Jumlah kolom grid peta blok (256 blok bawaan = 8 baris)
+const blockMapColumns = 32
+
+// Warna setiap jenis blok di peta blok
+var blockKindColors = map[filesystem_logic.BlockKind]color.Color{
+	filesystem_logic.BLOCK_FREE:       color.NRGBA{R: 220, G: 220, B: 225, A: 255},
+	filesystem_logic.BLOCK_SUPERBLOCK: color.NRGBA{R: 175, G: 82, B: 222, A: 255},
+	filesystem_logic.BLOCK_RESERVED:   color.NRGBA{R: 120, G: 120, B: 128, A: 255},
+	filesystem_logic.BLOCK_DIRECTORY:  color.NRGBA{R: 0, G: 122, B: 255, A: 255},
+	filesystem_logic.BLOCK_FILE:       color.NRGBA{R: 52, G: 199, B: 89, A: 255},
+	filesystem_logic.BLOCK_META:       color.NRGBA{R: 255, G: 149, B: 0, A: 255},
+	filesystem_logic.BLOCK_ORPHAN:     color.NRGBA{R: 255, G: 59, B: 48, A: 255},
+}
+
+var (
+	blockHighlightColor = color.NRGBA{R: 28, G: 28, B: 30, A: 255}
+	blockArrowColor     = color.NRGBA{R: 255, G: 45, B: 85, A: 255}
+)
+
+// blockMapWidget: Grid semua blok disk, satu kotak per blok berwarna sesuai jenisnya. Blok milik entri yang
+// dipilih diberi garis tepi, dan panah mengikuti urutan blok datanya (rantai FAT pada alokasi berantai).
+type blockMapWidget struct {
+	widget.BaseWidget
+	blocks   []filesystem_logic.BlockInfo
+	selected filesystem_logic.BlockID // StartBlock entri yang disorot, FAT_EOF jika tidak ada
+	cellSize float32                  // Ukuran kotak hasil Layout terakhir, dipakai untuk hover
+	onHover  func(block int)          // Dipanggil dengan nomor blok di bawah kursor, -1 jika kursor keluar
+	onTap    func(block int)          // Dipanggil dengan nomor blok yang diklik
+}
+
+func newBlockMapWidget(onHover, onTap func(block int)) *blockMapWidget {
+	m := &blockMapWidget{selected: filesystem_logic.FAT_EOF, onHover: onHover, onTap: onTap}
+	m.ExtendBaseWidget(m)
+	return m
+}
+
+// highlighted: true jika blok i milik entri yang sedang dipilih
+func (m *blockMapWidget) highlighted(i int) bool {
+	return m.selected >= 0 && m.blocks[i].Start == m.selected && m.blocks[i].Kind != filesystem_logic.BLOCK_FREE
+}
+
+func (m *blockMapWidget) CreateRenderer() fyne.WidgetRenderer {
+	return &blockMapRenderer{m: m}
+}
+
+// blockAt: Nomor blok di posisi pos, dihitung dari ukuran kotak terakhir; -1 jika di luar grid
+func (m *blockMapWidget) blockAt(pos fyne.Position) int {
+	if m.cellSize <= 0 {
+		return -1
+	}
+	col, row := int(pos.X/m.cellSize), int(pos.Y/m.cellSize)
+	block := row*blockMapColumns + col
+	if col >= blockMapColumns || block >= len(m.blocks) {
+		return -1
+	}
+	return block
+}
+
+func (m *blockMapWidget) MouseIn(ev *desktop.MouseEvent) { m.MouseMoved(ev) }
+
+func (m *blockMapWidget) MouseMoved(ev *desktop.MouseEvent) {
+	if m.cellSize > 0 && m.onHover != nil {
+		m.onHover(m.blockAt(ev.Position))
+	}
+}
+
+func (m *blockMapWidget) MouseOut() {
+	if m.onHover != nil {
+		m.onHover(-1)
+	}
+}
+
+func (m *blockMapWidget) Tapped(ev *fyne.PointEvent) {
+	if block := m.blockAt(ev.Position); block >= 0 && m.onTap != nil {
+		m.onTap(block)
+	}
+}
+
+var (
+	_ desktop.Hoverable = (*blockMapWidget)(nil)
+	_ fyne.Tappable     = (*blockMapWidget)(nil)
+)
+
+// blockMapRenderer: Kotak untuk setiap blok dan panah (garis dengan dua sayap di ujungnya) di antara blok yang disorot
+type blockMapRenderer struct {
+	m      *blockMapWidget
+	cells  []*canvas.Rectangle
+	arrows [][3]*canvas.Line // Badan panah, sayap kiri, sayap kanan
+	links  [][2]int          // Pasangan blok (dari, ke) untuk setiap panah
+}
+
+func (r *blockMapRenderer) rows() int {
+	return (len(r.m.blocks) + blockMapColumns - 1) / blockMapColumns
+}
+
+// center: Titik tengah kotak blok i
+func (r *blockMapRenderer) center(i int, cell float32) fyne.Position {
+	return fyne.NewPos((float32(i%blockMapColumns)+0.5)*cell, (float32(i/blockMapColumns)+0.5)*cell)
+}
+
+func (r *blockMapRenderer) Layout(size fyne.Size) {
+	rows := r.rows()
+	if rows == 0 {
+		return
+	}
+	cell := float32(math.Min(float64(size.Width/blockMapColumns), float64(size.Height/float32(rows))))
+	r.m.cellSize = cell
+	for i, rect := range r.cells {
+		rect.Move(fyne.NewPos(float32(i%blockMapColumns)*cell+1, float32(i/blockMapColumns)*cell+1))
+		rect.Resize(fyne.NewSize(cell-2, cell-2))
+	}
+	for k, link := range r.links {
+		from, to := r.center(link[0], cell), r.center(link[1], cell)
+		body, left, right := r.arrows[k][0], r.arrows[k][1], r.arrows[k][2]
+		body.Position1, body.Position2 = from, to
+		// Sayap panah di ujung tujuan, membentuk sudut 30 derajat dengan badan panah
+		angle := math.Atan2(float64(to.Y-from.Y), float64(to.X-from.X))
+		wing := float64(cell) * 0.4
+		for side, line := range []*canvas.Line{left, right} {
+			a := angle + math.Pi - math.Pi/6 + float64(side)*math.Pi/3
+			line.Position1 = to
+			line.Position2 = fyne.NewPos(to.X+float32(wing*math.Cos(a)), to.Y+float32(wing*math.Sin(a)))
+		}
+	}
+}
+
+func (r *blockMapRenderer) MinSize() fyne.Size {
+	return fyne.NewSize(blockMapColumns*12, float32(r.rows())*12)
+}
+
+func (r *blockMapRenderer) Refresh() {
+	m := r.m
+	for len(r.cells) < len(m.blocks) {
+		r.cells = append(r.cells, canvas.NewRectangle(color.Transparent))
+	}
+	r.cells = r.cells[:len(m.blocks)]
+	r.links = r.links[:0]
+	for i, info := range m.blocks {
+		rect := r.cells[i]
+		rect.FillColor = blockKindColors[info.Kind]
+		rect.StrokeWidth = 0
+		if m.highlighted(i) {
+			rect.StrokeColor, rect.StrokeWidth = blockHighlightColor, 2
+			if info.Next >= 0 && int(info.Next) < len(m.blocks) {
+				r.links = append(r.links, [2]int{i, int(info.Next)})
+			}
+		}
+		rect.Refresh()
+	}
+	for len(r.arrows) < len(r.links) {
+		var arrow [3]*canvas.Line
+		for j := range arrow {
+			arrow[j] = canvas.NewLine(blockArrowColor)
+			arrow[j].StrokeWidth = 2
+		}
+		r.arrows = append(r.arrows, arrow)
+	}
+	r.arrows = r.arrows[:len(r.links)]
+	r.Layout(m.Size())
+	for _, arrow := range r.arrows {
+		for _, line := range arrow {
+			line.Refresh()
+		}
+	}
+}
+
+func (r *blockMapRenderer) Objects() []fyne.CanvasObject {
+	objects := make([]fyne.CanvasObject, 0, len(r.cells)+3*len(r.arrows))
+	for _, rect := range r.cells {
+		objects = append(objects, rect)
+	}
+	for _, arrow := range r.arrows {
+		for _, line := range arrow {
+			objects = append(objects, line)
+		}
+	}
+	return objects
+}
+
+func (r *blockMapRenderer) Destroy() {}
+
+// Jendela View > Block Map: peta semua blok disk di panel aktif. Jendela diperbarui setiap kali refreshUI
+// dipanggil (setelah membuat, menulis, atau menghapus file) dan saat entri lain dipilih di daftar file.
+func (p *diskPane) showBlockMapWindow() {
+	if p.blockMapWindow != nil {
+		p.blockMapWindow.RequestFocus()
+		return
+	}
+
+	w := fyne.CurrentApp().NewWindow("Block Map - " + p.title)
+	w.Resize(fyne.NewSize(720, 420))
+	p.blockMapWindow = w
+
+	hoverLabel := widget.NewLabel("Hover over a block to see its owner and FAT entry, click it to inspect its bytes")
+	selectionLabel := widget.NewLabel("")
+	problemLabel := widget.NewLabel("") // Kerusakan yang ditemukan BlockMap; disembunyikan jika disk konsisten
+	problemLabel.Importance = widget.DangerImportance
+	problemLabel.Wrapping = fyne.TextWrapWord
+	problemLabel.Hide()
+	var grid *blockMapWidget
+	grid = newBlockMapWidget(func(block int) {
+		if block < 0 {
+			hoverLabel.SetText("")
+			return
+		}
+		info := grid.blocks[block]
+		owner := info.Owner
+		if owner == "" {
+			owner = "-"
+		}
+		hoverLabel.SetText(fmt.Sprintf("Block %d • %s • %s • FAT[%d] = %s", block, info.Kind, owner, block, fatValueText(info.FAT)))
+	}, func(block int) {
+		p.showBlockInspectorWindow(filesystem_logic.BlockID(block)) // Klik blok: lihat isinya di Block Inspector
+	})
+
+	legend := container.NewHBox()
+	for _, kind := range filesystem_logic.BlockKinds {
+		swatch := canvas.NewRectangle(blockKindColors[kind])
+		swatch.SetMinSize(fyne.NewSize(14, 14))
+		legend.Add(container.NewCenter(swatch))
+		legend.Add(widget.NewLabel(kind.String()))
+	}
+
+	refresh := func() {
+		blocks, err := p.fs.BlockMap()
+		if err != nil {
+			// Disk rusak (misalnya setelah menulis byte di Block Inspector): peta tetap ditampilkan dan kerusakannya
+			// ditulis di label, bukan dialog yang muncul lagi di setiap refreshUI
+			problemLabel.SetText(err.Error())
+			problemLabel.Show()
+		} else {
+			problemLabel.Hide()
+		}
+		grid.blocks = blocks
+		grid.selected = filesystem_logic.FAT_EOF
+		selectionLabel.SetText("Select a file or folder in the list to highlight its blocks")
+		if targetPath, ok := p.selectedPath(); ok {
+			if entry, errStat := p.fs.Lstat(targetPath); errStat == nil {
+				grid.selected = entry.StartBlock
+				count := 0
+				for i := range blocks {
+					if grid.highlighted(i) {
+						count++
+					}
+				}
+				selectionLabel.SetText(fmt.Sprintf("Selected: %s (%d blocks, allocation: %s)", targetPath, count, p.fs.Geometry.Allocation))
+			}
+		}
+		grid.Refresh()
+	}
+	p.refreshBlockMap = refresh
+	refresh()
+
+	w.SetOnClosed(func() {
+		p.blockMapWindow = nil
+		p.refreshBlockMap = nil
+	})
+	w.SetContent(container.NewBorder(
+		container.NewVBox(legend, selectionLabel, problemLabel),
+		hoverLabel,
+		nil, nil,
+		container.NewPadded(grid),
+	))
+	w.Show()
+}
+
+// Nilai entri FAT dalam bentuk yang mudah dibaca
+func fatValueText(value filesystem_logic.BlockID) string {
+	switch value {
+	case filesystem_logic.FAT_FREE:
+		return "FREE"
+	case filesystem_logic.FAT_EOF:
+		return "EOF"
+	case filesystem_logic.FAT_RESERVED:
+		return "RESERVED"
+	}
+	return fmt.Sprint(value)
+}
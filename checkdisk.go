@@ -0,0 +1,97 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"filesystemsimulator/filesystem_logic"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// Dialog File > Check Disk: memeriksa konsistensi FAT dan pohon direktori disk di panel aktif
+// (filesystem_logic.Check) lalu menampilkan laporannya
+func checkDiskDialog() {
+	p := activePane
+	report, err := filesystem_logic.Check(p.fs, filesystem_logic.CheckOptions{})
+	if err != nil {
+		dialog.ShowError(err, myWindow)
+		return
+	}
+	p.showCheckReport(report)
+}
+
+// Laporan Check Disk. Memilih masalah di daftar membuka blok pertamanya di Block Inspector. Jika ada rantai yang
+// hilang, tombol Repair memindahkannya ke /LOST.DIR lalu disk diperiksa ulang.
+func (p *diskPane) showCheckReport(report filesystem_logic.CheckReport) {
+	summary := fmt.Sprintf("Checked %d directories, %d files, %d used blocks.", report.Directories, report.Files, report.UsedBlocks)
+	if report.Clean() {
+		summary += "\nNo problems found."
+	} else {
+		var counts []string
+		for _, kind := range filesystem_logic.ProblemKinds {
+			if n := report.Count(kind); n > 0 {
+				counts = append(counts, fmt.Sprintf("%d %s", n, kind))
+			}
+		}
+		summary += fmt.Sprintf("\n%d problem(s): %s.", len(report.Problems), strings.Join(counts, ", "))
+	}
+	if len(report.Recovered) > 0 {
+		summary += fmt.Sprintf("\nRecovered %d lost chain(s) into /%s.", len(report.Recovered), filesystem_logic.LOST_DIR_NAME)
+	}
+
+	problemList := widget.NewList(
+		func() int { return len(report.Problems) },
+		func() fyne.CanvasObject { return widget.NewLabel("Template") },
+		func(id widget.ListItemID, item fyne.CanvasObject) {
+			problem := report.Problems[id]
+			item.(*widget.Label).SetText(fmt.Sprintf("[%s] %s", problem.Kind, problem.Message))
+		},
+	)
+	problemList.OnSelected = func(id widget.ListItemID) {
+		if blocks := report.Problems[id].Blocks; len(blocks) > 0 && blocks[0] >= 0 {
+			p.showBlockInspectorWindow(blocks[0])
+		}
+	}
+
+	var content fyne.CanvasObject = widget.NewLabel(summary)
+	if !report.Clean() {
+		content = container.NewBorder(content, nil, nil, nil, problemList)
+	}
+
+	title := "Check Disk (" + p.title + ")"
+	var d dialog.Dialog
+	if report.Count(filesystem_logic.PROBLEM_LOST_CHAIN) > 0 {
+		d = dialog.NewCustomConfirm(title, "Repair", "Close", content, func(repair bool) {
+			if repair {
+				p.repairDisk()
+			}
+		}, myWindow)
+	} else {
+		d = dialog.NewCustom(title, "Close", content, myWindow)
+	}
+	if !report.Clean() {
+		d.Resize(fyne.NewSize(760, 420))
+	}
+	d.Show()
+}
+
+// Mode perbaikan Check, lalu pemeriksaan ulang agar laporan berikutnya hanya berisi masalah yang tersisa
+func (p *diskPane) repairDisk() {
+	repaired, err := filesystem_logic.Check(p.fs, filesystem_logic.CheckOptions{Repair: true})
+	p.refreshUI()
+	if err != nil {
+		dialog.ShowError(err, myWindow)
+		return
+	}
+	report, err := filesystem_logic.Check(p.fs, filesystem_logic.CheckOptions{})
+	if err != nil {
+		dialog.ShowError(err, myWindow)
+		return
+	}
+	report.Recovered = repaired.Recovered
+	p.showCheckReport(report)
+}
@@ -0,0 +1,208 @@
+// alloc.go
+package filesystem_logic
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// Metode alokasi blok data file, dipilih saat format lewat Geometry.Allocation dan disimpan di superblock.
+// Semua operasi isi file (ReadFromFile, WriteToFile, File, Truncate, DeleteEntry) memakai Allocator milik disk,
+// sehingga metode yang berbeda bisa dibandingkan di disk yang sama. Blok direktori selalu dirantai lewat FAT.
+//
+// Apa pun metodenya, FAT tetap menjadi peta alokasi: blok yang dipakai file tidak pernah bernilai FAT_FREE.
+type AllocationMethod int
+
+const (
+	ALLOC_LINKED     AllocationMethod = iota // Bawaan: rantai blok lewat FAT
+	ALLOC_INDEXED                            // Blok indeks dengan pointer langsung dan tidak langsung (lihat indexed.go)
+	ALLOC_CONTIGUOUS                         // Extent berisi blok-blok berurutan (lihat contiguous.go)
+)
+
+func (m AllocationMethod) String() string {
+	switch m {
+	case ALLOC_INDEXED:
+		return "indexed"
+	case ALLOC_CONTIGUOUS:
+		return "contiguous"
+	default:
+		return "linked (FAT)"
+	}
+}
+
+// AllocationMethods: Semua metode alokasi, untuk pilihan di GUI.
+var AllocationMethods = []AllocationMethod{ALLOC_LINKED, ALLOC_INDEXED, ALLOC_CONTIGUOUS}
+
+// Allocator: Cara blok data sebuah file dicatat di disk. entry.StartBlock adalah titik masuknya
+// (blok pertama rantai, blok indeks, atau blok extent), FAT_EOF jika file belum punya blok.
+type Allocator interface {
+	// Method: Metode alokasi yang diimplementasikan.
+	Method() AllocationMethod
+	// Blocks: Blok data file secara berurutan (blok ke-i berisi byte i*BlockSize sampai (i+1)*BlockSize).
+	Blocks(entry DirectoryEntry) ([]BlockID, error)
+	// MetaBlocks: Blok yang dipakai file untuk mencatat alokasinya sendiri (misalnya blok indeks), bukan data.
+	MetaBlocks(entry DirectoryEntry) ([]BlockID, error)
+	// Resize: Mengubah jumlah blok data file menjadi total. Blok baru diisi nol, blok yang tidak terpakai lagi
+	// dibebaskan, dan entry.StartBlock diperbarui. Jika disk penuh, file dikembalikan ke panjang semula.
+	Resize(entry *DirectoryEntry, total int) ([]BlockID, error)
+	// BlocksNeeded: Jumlah blok yang dipakai file baru dengan dataBlocks blok data, termasuk blok metadata
+	// alokasinya (MetaBlocks). Dipakai untuk memeriksa ruang kosong sebelum menyalin (lihat CopyTo).
+	BlocksNeeded(dataBlocks int) int
+	// Describe: Struktur alokasi file untuk ditampilkan di GUI.
+	Describe(entry DirectoryEntry) (AllocationNode, error)
+}
+
+// AllocationNode: Satu simpul struktur alokasi file (blok data, blok indeks, atau pointer di dalamnya).
+type AllocationNode struct {
+	Label    string
+	Block    BlockID // FAT_EOF jika simpul bukan blok (misalnya pointer kosong)
+	Children []AllocationNode
+}
+
+// allocator: Allocator untuk metode alokasi disk ini.
+func (fs *FileSystem) allocator() Allocator {
+	switch fs.Geometry.Allocation {
+	case ALLOC_INDEXED:
+		return indexedAllocator{fs}
+	case ALLOC_CONTIGUOUS:
+		return contiguousAllocator{fs}
+	default:
+		return linkedAllocator{fs}
+	}
+}
+
+// newZeroBlock: Mengambil blok kosong, menandainya terpakai (FAT_EOF), dan mengisinya dengan nol.
+func (fs *FileSystem) newZeroBlock() (BlockID, error) {
+	block, err := fs.findFreeBlock()
+	if err != nil {
+		return block, err
+	}
+	fs.setFAT(block, FAT_EOF) // Ditandai terpakai dulu: free list dkk. menyimpan pointer di blok kosong (lihat freespace.go)
+	for i := range fs.Disk[block] {
+		fs.Disk[block][i] = 0
+	}
+	return block, nil
+}
+
+// blockPointer: Pointer ke-i di block (int32 little endian, sama seperti entri FAT). Dipakai blok indeks
+// (indexed.go) dan blok extent (contiguous.go).
+func (fs *FileSystem) blockPointer(block BlockID, i int) BlockID {
+	return BlockID(int32(binary.LittleEndian.Uint32(fs.Disk[block][i*FAT_ENTRY_SIZE:])))
+}
+
+func (fs *FileSystem) setBlockPointer(block BlockID, i int, value BlockID) {
+	binary.LittleEndian.PutUint32(fs.Disk[block][i*FAT_ENTRY_SIZE:], uint32(value))
+}
+
+// newPointerBlock: Blok baru untuk metadata alokasi dengan semua pointer kosong (FAT_EOF).
+func (fs *FileSystem) newPointerBlock() (BlockID, error) {
+	block, err := fs.newZeroBlock()
+	if err != nil {
+		return block, err
+	}
+	for i := 0; i < fs.Geometry.BlockSize/FAT_ENTRY_SIZE; i++ {
+		fs.setBlockPointer(block, i, FAT_EOF)
+	}
+	return block, nil
+}
+
+// freeFileBlocks: Membebaskan semua blok data (dan blok metadata alokasi) milik file.
+func (fs *FileSystem) freeFileBlocks(entry DirectoryEntry) error {
+	_, err := fs.allocator().Resize(&entry, 0)
+	return err
+}
+
+// AllocationOf: Struktur alokasi file di path (symlink tidak diikuti) untuk ditampilkan di GUI.
+func (fs *FileSystem) AllocationOf(path string) (AllocationNode, error) {
+	entry, err := fs.Lstat(path)
+	if err != nil {
+		return AllocationNode{}, err
+	}
+	if entry.Type == TYPE_DIRECTORY {
+		blocks, err := fs.chainBlocks(entry.StartBlock)
+		return chainNode("Directory chain (FAT)", blocks, fs.FAT), err
+	}
+	return fs.allocator().Describe(entry)
+}
+
+// chainNode: Simpul untuk rantai FAT, satu anak per blok beserta nilai FAT-nya.
+func chainNode(label string, blocks []BlockID, fat []BlockID) AllocationNode {
+	node := AllocationNode{Label: fmt.Sprintf("%s: %d block(s)", label, len(blocks)), Block: FAT_EOF}
+	for _, block := range blocks {
+		next := "EOF"
+		if fat[block] >= 0 {
+			next = fmt.Sprint(fat[block])
+		}
+		node.Children = append(node.Children, AllocationNode{Label: fmt.Sprintf("Block %d → %s", block, next), Block: block})
+	}
+	return node
+}
+
+// linkedAllocator: Alokasi berantai lewat FAT (seperti FAT12/16): FAT[b] berisi blok berikutnya setelah b.
+type linkedAllocator struct{ fs *FileSystem }
+
+func (a linkedAllocator) Method() AllocationMethod { return ALLOC_LINKED }
+
+func (a linkedAllocator) Blocks(entry DirectoryEntry) ([]BlockID, error) {
+	return a.fs.chainBlocks(entry.StartBlock)
+}
+
+func (a linkedAllocator) MetaBlocks(entry DirectoryEntry) ([]BlockID, error) {
+	return nil, nil // Penunjuk blok berikutnya ada di FAT, bukan di blok milik file
+}
+
+func (a linkedAllocator) BlocksNeeded(dataBlocks int) int {
+	return dataBlocks
+}
+
+func (a linkedAllocator) Resize(entry *DirectoryEntry, total int) ([]BlockID, error) {
+	blocks, err := a.Blocks(*entry)
+	if err != nil {
+		return blocks, err
+	}
+	if total <= len(blocks) {
+		a.truncate(entry, blocks, total)
+		return blocks[:total], nil
+	}
+	return a.extend(entry, blocks, total)
+}
+
+// extend: Menambah blok kosong (diisi nol) di ujung rantai file sampai panjangnya total blok.
+// Jika disk penuh di tengah jalan, blok yang sudah terlanjur ditambahkan dibebaskan lagi.
+func (a linkedAllocator) extend(entry *DirectoryEntry, blocks []BlockID, total int) ([]BlockID, error) {
+	originalLen := len(blocks)
+	for len(blocks) < total {
+		newBlock, err := a.fs.newZeroBlock()
+		if err != nil {
+			a.truncate(entry, blocks, originalLen)
+			return blocks[:originalLen], err
+		}
+		if len(blocks) == 0 {
+			entry.StartBlock = newBlock
+		} else {
+			a.fs.setFAT(blocks[len(blocks)-1], newBlock)
+		}
+		blocks = append(blocks, newBlock)
+	}
+	return blocks, nil
+}
+
+// truncate: Memotong rantai file menjadi keep blok pertama dan membebaskan sisanya.
+func (a linkedAllocator) truncate(entry *DirectoryEntry, blocks []BlockID, keep int) {
+	if keep >= len(blocks) {
+		return
+	}
+	for _, block := range blocks[keep:] {
+		a.fs.setFAT(block, FAT_FREE)
+	}
+	if keep == 0 {
+		entry.StartBlock = FAT_EOF
+	} else {
+		a.fs.setFAT(blocks[keep-1], FAT_EOF)
+	}
+}
+
+func (a linkedAllocator) Describe(entry DirectoryEntry) (AllocationNode, error) {
+	blocks, err := a.Blocks(entry)
+	return chainNode("FAT chain", blocks, a.fs.FAT), err
+}
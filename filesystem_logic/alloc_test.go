@@ -0,0 +1,141 @@
+package filesystem_logic
+
+import (
+	"fmt"
+	"testing"
+)
+
+// checkAllocation: Memastikan file entry punya tepat want blok data berbeda yang tidak bertanda FAT_FREE,
+// dan total blok yang dipakainya (data dan metadata) sama dengan Allocator.BlocksNeeded.
+func checkAllocation(t *testing.T, fs *FileSystem, entry DirectoryEntry, want int) []BlockID {
+	t.Helper()
+	a := fs.allocator()
+	blocks, err := a.Blocks(entry)
+	if err != nil {
+		t.Fatal(err)
+	}
+	meta, err := a.MetaBlocks(entry)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(blocks) != want {
+		t.Fatalf("%d blok data, seharusnya %d", len(blocks), want)
+	}
+	if got := len(blocks) + len(meta); got != a.BlocksNeeded(want) {
+		t.Fatalf("%d blok data + %d blok metadata, BlocksNeeded(%d) = %d", len(blocks), len(meta), want, a.BlocksNeeded(want))
+	}
+	seen := map[BlockID]bool{}
+	for _, block := range append(append([]BlockID{}, blocks...), meta...) {
+		if seen[block] {
+			t.Fatalf("blok %d dipakai dua kali", block)
+		}
+		seen[block] = true
+		if fs.FAT[block] == FAT_FREE {
+			t.Fatalf("blok %d milik file bertanda FAT_FREE", block)
+		}
+	}
+	if want == 0 && entry.StartBlock != FAT_EOF {
+		t.Fatalf("file kosong masih punya StartBlock %d", entry.StartBlock)
+	}
+	return blocks
+}
+
+// markBlocks: Menulis penanda di byte pertama setiap blok data agar isinya bisa dicek setelah Resize.
+func markBlocks(fs *FileSystem, blocks []BlockID) {
+	for i, block := range blocks {
+		fs.Disk[block][0] = byte(i + 1)
+	}
+}
+
+// checkMarks: Blok data yang sudah ada sebelum Resize tetap berisi penandanya, blok baru berisi nol.
+func checkMarks(t *testing.T, fs *FileSystem, blocks []BlockID, marked int) {
+	t.Helper()
+	for i, block := range blocks {
+		want := byte(0)
+		if i < marked {
+			want = byte(i + 1)
+		}
+		if fs.Disk[block][0] != want {
+			t.Fatalf("blok data ke-%d (blok %d) berisi %d, seharusnya %d", i, block, fs.Disk[block][0], want)
+		}
+	}
+}
+
+// Resize memperbesar dan memperkecil file melewati batas pointer langsung, single indirect, dan double
+// indirect (alokasi berindeks), tanpa kehilangan isi blok yang tetap dipakai.
+func TestAllocatorResize(t *testing.T) {
+	// Blok 256 byte: 64 pointer per blok, jadi double indirect mulai dari blok data ke-76
+	sizes := []int{1, 12, 13, 40, 76, 77, 150, 77, 76, 13, 12, 3, 0, 5, 0}
+	for _, alloc := range AllocationMethods {
+		t.Run(alloc.String(), func(t *testing.T) {
+			fs := newTestDisk(t, Geometry{TotalBlocks: 256, Allocation: alloc})
+			a := fs.allocator()
+			free := int(fs.countFreeBlocks())
+			entry := DirectoryEntry{StartBlock: FAT_EOF}
+			marked := 0
+			for _, n := range sizes {
+				blocks, err := a.Resize(&entry, n)
+				if err != nil {
+					t.Fatalf("Resize ke %d blok: %v", n, err)
+				}
+				checked := checkAllocation(t, fs, entry, n)
+				if fmt.Sprint(checked) != fmt.Sprint(blocks) {
+					t.Fatalf("Resize ke %d mengembalikan %v, Blocks %v", n, blocks, checked)
+				}
+				checkMarks(t, fs, blocks, min(marked, n))
+				if used := free - int(fs.countFreeBlocks()); used != a.BlocksNeeded(n) {
+					t.Fatalf("setelah Resize ke %d: %d blok terpakai, seharusnya %d", n, used, a.BlocksNeeded(n))
+				}
+				markBlocks(fs, blocks)
+				marked = n
+			}
+		})
+	}
+}
+
+// Jika disk penuh di tengah Resize, file kembali ke panjang semula dan semua blok yang terlanjur
+// dialokasikan (termasuk blok indeks dan blok extent baru) dibebaskan lagi.
+func TestAllocatorResizeRollback(t *testing.T) {
+	for _, alloc := range AllocationMethods {
+		for _, start := range []int{0, 5, 12} { // 12: blok berikutnya butuh blok single indirect pada alokasi berindeks
+			for free := 0; free <= 2; free++ {
+				t.Run(fmt.Sprintf("%s/start=%d/free=%d", alloc, start, free), func(t *testing.T) {
+					fs := newTestDisk(t, Geometry{TotalBlocks: 64, Allocation: alloc})
+					a := fs.allocator()
+					entry := DirectoryEntry{StartBlock: FAT_EOF}
+					blocks, err := a.Resize(&entry, start)
+					if err != nil {
+						t.Fatal(err)
+					}
+					markBlocks(fs, blocks)
+					startBlock := entry.StartBlock
+
+					// Habiskan blok kosong, lalu sisakan free blok
+					var taken []BlockID
+					for {
+						block, err := fs.newZeroBlock()
+						if err != nil {
+							break
+						}
+						taken = append(taken, block)
+					}
+					for _, block := range taken[:free] {
+						fs.setFAT(block, FAT_FREE)
+					}
+
+					if _, err := a.Resize(&entry, start+free+1); err == nil {
+						t.Fatalf("Resize ke %d blok dengan %d blok kosong seharusnya gagal", start+free+1, free)
+					}
+					if entry.StartBlock != startBlock {
+						t.Fatalf("StartBlock berubah dari %d menjadi %d", startBlock, entry.StartBlock)
+					}
+					blocks = checkAllocation(t, fs, entry, start)
+					checkMarks(t, fs, blocks, start)
+					if got := int(fs.countFreeBlocks()); got != free {
+						t.Fatalf("%d blok kosong setelah rollback, seharusnya %d", got, free)
+					}
+				})
+			}
+		}
+	}
+}
@@ -0,0 +1,153 @@
+// blockmap.go
+package filesystem_logic
+
+import (
+	"errors"
+	"fmt"
+	"path"
+)
+
+// Peta blok: pemilik dan jenis setiap blok di disk, untuk visualisasi FAT di GUI.
+
+// BlockKind: Jenis isi sebuah blok.
+type BlockKind int
+
+const (
+	BLOCK_FREE       BlockKind = iota // FAT_FREE
+	BLOCK_SUPERBLOCK                  // Blok 0
+	BLOCK_RESERVED                    // Blok reserved lain, area FAT, tabel inode, dan bitmap
+	BLOCK_DIRECTORY                   // Rantai blok direktori
+	BLOCK_FILE                        // Data file (termasuk path tujuan symlink)
+	BLOCK_META                        // Blok metadata alokasi file (blok indeks atau blok extent)
+	BLOCK_ORPHAN                      // Terpakai di FAT tetapi tidak dimiliki entri mana pun
+)
+
+func (k BlockKind) String() string {
+	switch k {
+	case BLOCK_SUPERBLOCK:
+		return "superblock"
+	case BLOCK_RESERVED:
+		return "reserved"
+	case BLOCK_DIRECTORY:
+		return "directory"
+	case BLOCK_FILE:
+		return "file data"
+	case BLOCK_META:
+		return "allocation metadata"
+	case BLOCK_ORPHAN:
+		return "unreferenced"
+	default:
+		return "free"
+	}
+}
+
+// BlockKinds: Semua jenis blok, untuk legenda di GUI.
+var BlockKinds = []BlockKind{BLOCK_FREE, BLOCK_SUPERBLOCK, BLOCK_RESERVED, BLOCK_DIRECTORY, BLOCK_FILE, BLOCK_META, BLOCK_ORPHAN}
+
+// BlockInfo: Satu blok di peta blok.
+type BlockInfo struct {
+	Kind  BlockKind
+	Owner string  // Path pemilik (hard link: path pertama yang ditemukan), atau nama area sistem
+	Start BlockID // StartBlock entri pemilik, untuk mengenali semua blok satu file; FAT_EOF jika tidak ada pemilik
+	FAT   BlockID // Nilai FAT[i]
+	Next  BlockID // Blok berikutnya milik pemilik yang sama sesuai urutan data (FAT_EOF jika terakhir atau bukan blok data)
+}
+
+// BlockMap: Jenis, pemilik, dan nilai FAT setiap blok di disk, berurutan dari blok 0.
+// Pohon direktori ditelusuri dari root tanpa memeriksa izin dan tanpa memperbarui atime.
+//
+// Seperti Check, kerusakan tidak menghentikan penelusuran: blok yang masih terbaca dari rantai atau pointer
+// alokasi yang rusak tetap diklaim, lalu entri berikutnya ditelusuri. Semua kerusakan dikembalikan sebagai satu
+// error (errors.Join) bersama peta yang tetap lengkap; blok yang tidak bisa dicapai muncul sebagai BLOCK_ORPHAN.
+func (fs *FileSystem) BlockMap() ([]BlockInfo, error) {
+	blocks := make([]BlockInfo, fs.Geometry.TotalBlocks)
+	for i := range blocks {
+		blocks[i] = BlockInfo{Kind: BLOCK_FREE, Start: FAT_EOF, FAT: fs.FAT[i], Next: FAT_EOF}
+		if fs.FAT[i] != FAT_FREE {
+			blocks[i].Kind = BLOCK_ORPHAN
+		}
+	}
+
+	// 1. Area sistem
+	system := func(from BlockID, count int, kind BlockKind, owner string) {
+		for b := from; b < from+BlockID(count); b++ {
+			blocks[b].Kind, blocks[b].Owner = kind, owner
+		}
+	}
+	geo := fs.Geometry
+	system(SUPERBLOCK_BLOCK, 1, BLOCK_SUPERBLOCK, "superblock")
+	system(SUPERBLOCK_BLOCK+1, geo.ReservedBlocks-1, BLOCK_RESERVED, "reserved")
+	for c := 0; c < geo.FATCopies; c++ {
+		system(geo.FATStart()+BlockID(c*geo.FATBlocksPerCopy()), geo.FATBlocksPerCopy(), BLOCK_RESERVED, fmt.Sprintf("FAT copy %d", c+1))
+	}
+	system(geo.InodeStart(), geo.InodeBlocks(), BLOCK_RESERVED, "inode table")
+	system(geo.BitmapStart(), geo.BitmapBlocks(), BLOCK_RESERVED, "free-space bitmap")
+
+	// 2. Blok milik file dan direktori
+	claim := func(owner string, start BlockID, kind BlockKind, list []BlockID, chained bool) {
+		for i, b := range list {
+			if !fs.validBlock(b) {
+				continue
+			}
+			blocks[b].Kind, blocks[b].Owner, blocks[b].Start = kind, owner, start
+			if chained && i+1 < len(list) {
+				blocks[b].Next = list[i+1]
+			}
+		}
+	}
+	var problems []error // Kerusakan yang ditemukan; penelusuran tetap dilanjutkan (lihat BlockMap)
+	claimFile := func(owner string, entry DirectoryEntry) {
+		data, errData := fs.allocator().Blocks(entry)
+		meta, errMeta := fs.allocator().MetaBlocks(entry)
+		claim(owner, entry.StartBlock, BLOCK_META, meta, false) // Blok yang masih terbaca tetap diklaim
+		claim(owner, entry.StartBlock, BLOCK_FILE, data, true)
+		if errData == nil {
+			errData = errMeta
+		}
+		if errData != nil {
+			problems = append(problems, fmt.Errorf("file '%s': %w", owner, errData))
+		}
+	}
+
+	seen := map[BlockID]bool{} // Direktori dan grup hard link hanya diklaim sekali
+	var walk func(dirPath string, dirBlock BlockID)
+	walk = func(dirPath string, dirBlock BlockID) {
+		chain, err := fs.chainBlocks(dirBlock)
+		claim(dirPath, dirBlock, BLOCK_DIRECTORY, chain, true)
+		if err != nil {
+			problems = append(problems, fmt.Errorf("direktori '%s': %w", dirPath, err))
+		}
+		entries, _ := fs.listEntries(dirBlock) // Error-nya sama dengan error rantai di atas; entri dari blok yang terbaca tetap dikembalikan
+		for _, entry := range entries {
+			name := entry.NameString()
+			if name == "." || name == ".." || (entry.StartBlock >= 0 && seen[entry.StartBlock]) {
+				continue
+			}
+			if entry.StartBlock >= 0 {
+				seen[entry.StartBlock] = true
+			}
+			childPath := path.Join(dirPath, name)
+			if entry.Type == TYPE_DIRECTORY {
+				walk(childPath, entry.StartBlock)
+			} else {
+				claimFile(childPath, entry)
+			}
+		}
+	}
+	seen[fs.RootDirBlock] = true
+	walk("/", fs.RootDirBlock)
+
+	// 3. File yang sudah dihapus tetapi masih terbuka: bloknya baru dibebaskan saat ditutup
+	if fs.FileTable != nil {
+		for _, f := range fs.FileTable.files {
+			if f.node.unlinked && !seen[f.node.entry.StartBlock] {
+				seen[f.node.entry.StartBlock] = true
+				claimFile(f.path+" (deleted, still open)", f.node.entry)
+			}
+		}
+	}
+	if len(problems) > 0 {
+		return blocks, fmt.Errorf("kerusakan saat membuat peta blok: %w", errors.Join(problems...))
+	}
+	return blocks, nil
+}
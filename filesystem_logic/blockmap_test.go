@@ -0,0 +1,51 @@
+package filesystem_logic
+
+import (
+	"encoding/binary"
+	"strings"
+	"testing"
+)
+
+// Alokasi file yang rusak tidak menghentikan BlockMap: file dan direktori sesudahnya tetap punya pemilik,
+// dan kerusakannya dilaporkan lewat error.
+func TestBlockMapContinuesPastCorruption(t *testing.T) {
+	for _, alloc := range AllocationMethods {
+		t.Run(alloc.String(), func(t *testing.T) {
+			fs := newTestDisk(t, Geometry{TotalBlocks: 64, Allocation: alloc})
+			blockSize := fs.Geometry.BlockSize
+			for _, name := range []string{"/a", "/b"} {
+				if err := fs.WriteFile(name, make([]byte, 2*blockSize)); err != nil {
+					t.Fatal(err)
+				}
+			}
+			fs.Mkdir("/d")
+			if err := fs.WriteFile("/d/c", []byte("c")); err != nil {
+				t.Fatal(err)
+			}
+
+			// Rusakkan pointer blok kedua /a: di FAT untuk rantai, di blok indeks atau blok extent untuk yang lain
+			a, _ := fs.Lstat("/a")
+			bad := make([]byte, FAT_ENTRY_SIZE)
+			binary.LittleEndian.PutUint32(bad, 9999)
+			if alloc == ALLOC_LINKED {
+				fs.FAT[a.StartBlock] = 9999
+			} else {
+				copy(fs.Disk[a.StartBlock][FAT_ENTRY_SIZE:], bad)
+			}
+
+			blocks, err := fs.BlockMap()
+			if err == nil || !strings.Contains(err.Error(), "'/a'") {
+				t.Fatalf("kerusakan /a seharusnya dilaporkan, dapat: %v", err)
+			}
+			owned := map[string]int{}
+			for _, info := range blocks {
+				if info.Kind == BLOCK_FILE {
+					owned[info.Owner]++
+				}
+			}
+			if owned["/b"] != 2 || owned["/d/c"] != 1 {
+				t.Fatalf("blok data per pemilik setelah kerusakan /a: %v", owned)
+			}
+		})
+	}
+}
@@ -0,0 +1,523 @@
+// check.go
+package filesystem_logic
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+)
+
+// Pemeriksa konsistensi disk gaya fsck/CHKDSK: mencocokkan FAT dengan pohon direktori. Setiap blok yang terpakai
+// di FAT harus dimiliki tepat satu entri (atau area sistem), dan setiap blok yang dipakai entri harus terpakai di FAT.
+
+// LOST_DIR_NAME: Direktori di root tempat mode perbaikan menaruh rantai yang hilang, seperti FOUND.000 di CHKDSK.
+const LOST_DIR_NAME = "LOST.DIR"
+
+// ProblemKind: Jenis masalah yang ditemukan Check.
+type ProblemKind int
+
+const (
+	PROBLEM_LOST_CHAIN    ProblemKind = iota // Blok terpakai di FAT tetapi tidak dimiliki entri mana pun
+	PROBLEM_CROSS_LINK                       // Blok yang sama dipakai lebih dari satu pemilik
+	PROBLEM_CYCLE                            // Rantai FAT kembali ke blok yang sudah dilewati
+	PROBLEM_SIZE                             // Size entri tidak cocok dengan jumlah blok datanya
+	PROBLEM_DOT_ENTRY                        // Entri "." atau ".." hilang atau menunjuk blok yang salah
+	PROBLEM_DUPLICATE                        // Dua entri dengan nama yang sama di satu direktori
+	PROBLEM_INVALID_BLOCK                    // Nomor blok di luar disk, atau blok milik entri yang bertanda kosong di FAT
+)
+
+func (k ProblemKind) String() string {
+	switch k {
+	case PROBLEM_LOST_CHAIN:
+		return "lost chain"
+	case PROBLEM_CROSS_LINK:
+		return "cross-linked blocks"
+	case PROBLEM_CYCLE:
+		return "cycle"
+	case PROBLEM_SIZE:
+		return "size mismatch"
+	case PROBLEM_DOT_ENTRY:
+		return "bad . or .. entry"
+	case PROBLEM_DUPLICATE:
+		return "duplicate name"
+	default:
+		return "invalid block"
+	}
+}
+
+// ProblemKinds: Semua jenis masalah, untuk ringkasan di GUI.
+var ProblemKinds = []ProblemKind{PROBLEM_LOST_CHAIN, PROBLEM_CROSS_LINK, PROBLEM_CYCLE, PROBLEM_SIZE,
+	PROBLEM_DOT_ENTRY, PROBLEM_DUPLICATE, PROBLEM_INVALID_BLOCK}
+
+// CheckProblem: Satu masalah yang ditemukan Check.
+type CheckProblem struct {
+	Kind    ProblemKind
+	Path    string    // Entri atau direktori yang bermasalah, kosong untuk rantai yang hilang
+	Blocks  []BlockID // Blok yang terlibat (bisa kosong)
+	Message string
+}
+
+// CheckOptions: Pilihan untuk Check.
+type CheckOptions struct {
+	Repair bool // Pindahkan rantai yang hilang ke /LOST.DIR sebagai file FILEnnnn.CHK
+}
+
+// CheckReport: Hasil Check. Problems berisi masalah sebelum perbaikan; Recovered berisi path file yang dibuat
+// mode perbaikan dari rantai yang hilang.
+type CheckReport struct {
+	Directories int
+	Files       int
+	UsedBlocks  int // Blok terpakai di FAT di luar area sistem
+	Problems    []CheckProblem
+	Recovered   []string
+}
+
+// Clean: true jika tidak ada masalah.
+func (r CheckReport) Clean() bool {
+	return len(r.Problems) == 0
+}
+
+// Count: Jumlah masalah berjenis kind.
+func (r CheckReport) Count(kind ProblemKind) int {
+	n := 0
+	for _, problem := range r.Problems {
+		if problem.Kind == kind {
+			n++
+		}
+	}
+	return n
+}
+
+// checker: State satu kali pemeriksaan.
+type checker struct {
+	fs       *FileSystem
+	report   *CheckReport
+	owners   []int            // Nomor pemilik setiap blok (indeks names, 0 jika belum diklaim)
+	names    []string         // Nama setiap pemilik; dua entri dengan path sama tetap pemilik yang berbeda
+	system   []bool           // Blok area sistem (superblock, reserved, FAT, tabel inode, bitmap)
+	linkSeen map[BlockID]bool // StartBlock grup hard link yang sudah diperiksa
+}
+
+func (c *checker) problem(kind ProblemKind, p string, blocks []BlockID, format string, args ...interface{}) {
+	c.report.Problems = append(c.report.Problems, CheckProblem{Kind: kind, Path: p, Blocks: blocks, Message: fmt.Sprintf(format, args...)})
+}
+
+// claim: Mencatat pemilik baru bernama name untuk blocks. Blok yang sudah punya pemilik dilaporkan sebagai
+// cross-link, satu masalah untuk setiap pemilik sebelumnya.
+func (c *checker) claim(name string, blocks []BlockID) {
+	owner := len(c.names)
+	c.names = append(c.names, name)
+	shared := map[int][]BlockID{}
+	var order []int
+	for _, b := range blocks {
+		if prev := c.owners[b]; prev != 0 {
+			if _, ok := shared[prev]; !ok {
+				order = append(order, prev)
+			}
+			shared[prev] = append(shared[prev], b)
+			continue
+		}
+		c.owners[b] = owner
+	}
+	for _, prev := range order {
+		if prev == owner {
+			c.problem(PROBLEM_CROSS_LINK, name, shared[prev], "'%s' memakai blok yang sama lebih dari sekali: %s", name, blockList(shared[prev]))
+		} else {
+			c.problem(PROBLEM_CROSS_LINK, name, shared[prev], "'%s' berbagi %d blok dengan '%s': %s", name, len(shared[prev]), c.names[prev], blockList(shared[prev]))
+		}
+	}
+}
+
+// walkChain: Mengikuti rantai FAT dari start seperti chainBlocks, tetapi melaporkan masalahnya atas nama owner:
+// nomor blok di luar disk, blok yang bertanda kosong di FAT, dan siklus. Blok sebelum masalah tetap dikembalikan.
+func (c *checker) walkChain(owner string, start BlockID) []BlockID {
+	fs := c.fs
+	var blocks []BlockID
+	visited := map[BlockID]bool{}
+	for current := start; current != FAT_EOF; {
+		if !fs.validBlock(current) {
+			c.problem(PROBLEM_INVALID_BLOCK, owner, nil, "rantai '%s' menunjuk nomor blok tidak valid %d", owner, current)
+			break
+		}
+		if visited[current] {
+			c.problem(PROBLEM_CYCLE, owner, []BlockID{current}, "rantai '%s' kembali ke blok %d setelah %d blok", owner, current, len(blocks))
+			break
+		}
+		visited[current] = true
+		blocks = append(blocks, current)
+		if fs.FAT[current] == FAT_FREE {
+			c.problem(PROBLEM_INVALID_BLOCK, owner, []BlockID{current}, "blok %d milik '%s' bertanda kosong di FAT", current, owner)
+			break
+		}
+		current = fs.FAT[current]
+	}
+	return blocks
+}
+
+// checkFile: Blok data dan metadata alokasi sebuah file (atau symlink) lewat Allocator disk, lalu ukurannya.
+func (c *checker) checkFile(p string, entry DirectoryEntry) {
+	fs := c.fs
+	c.report.Files++
+	if entry.hasLinks() && entry.StartBlock >= 0 {
+		if c.linkSeen[entry.StartBlock] {
+			return // Hard link lain ke rantai yang sama sudah diperiksa
+		}
+		c.linkSeen[entry.StartBlock] = true
+	}
+	if entry.StartBlock != FAT_EOF && !fs.validBlock(entry.StartBlock) {
+		c.problem(PROBLEM_INVALID_BLOCK, p, nil, "StartBlock '%s' tidak valid: %d", p, entry.StartBlock)
+		return
+	}
+
+	var data, meta []BlockID
+	if fs.Geometry.Allocation == ALLOC_LINKED {
+		data = c.walkChain(p, entry.StartBlock)
+	} else {
+		var err error
+		data, err = fs.allocator().Blocks(entry)
+		if err != nil {
+			c.problem(PROBLEM_INVALID_BLOCK, p, nil, "alokasi '%s' rusak: %v", p, err)
+		}
+		meta, _ = fs.allocator().MetaBlocks(entry)
+		if entry.StartBlock >= 0 && !containsBlock(meta, entry.StartBlock) {
+			meta = append(meta, entry.StartBlock) // Blok extent tetap milik file meskipun isinya rusak
+		}
+		for _, b := range append(append([]BlockID{}, meta...), data...) {
+			if fs.FAT[b] == FAT_FREE {
+				c.problem(PROBLEM_INVALID_BLOCK, p, []BlockID{b}, "blok %d milik '%s' bertanda kosong di FAT", b, p)
+			}
+		}
+	}
+	c.claim(p, append(meta, data...))
+
+	// Jumlah blok harus pas dengan Size, kecuali file kosong yang baru dibuat (satu blok, Size 0)
+	expected := int64(0)
+	if entry.Size > 0 {
+		expected = (entry.Size + int64(fs.Geometry.BlockSize) - 1) / int64(fs.Geometry.BlockSize)
+	}
+	if entry.Size < 0 || (int64(len(data)) != expected && !(entry.Size == 0 && len(data) == 1)) {
+		c.problem(PROBLEM_SIZE, p, nil, "Size '%s' %d byte butuh %d blok, tetapi file punya %d blok", p, entry.Size, expected, len(data))
+	}
+}
+
+// checkDirectory: Rantai dan isi direktori di dirBlock, lalu semua isinya secara rekursif.
+func (c *checker) checkDirectory(p string, dirBlock, parentBlock BlockID) {
+	fs := c.fs
+	c.report.Directories++
+	chain := c.walkChain(p, dirBlock)
+	c.claim(p, chain)
+	if len(chain) == 0 {
+		return
+	}
+
+	// Hanya blok yang sudah lolos walkChain yang dibaca, agar rantai dengan siklus tidak dibaca berulang
+	var entries []DirectoryEntry
+	fs.scanBlocks(chain, func(rec dirRecord) bool {
+		entries = append(entries, rec.Entry)
+		return true
+	})
+
+	// 1. Entri "." dan ".."
+	dots := map[string]BlockID{".": dirBlock, "..": parentBlock}
+	for _, name := range []string{".", ".."} {
+		found := false
+		for _, entry := range entries {
+			if entry.ShortName() == name {
+				found = true
+				if entry.StartBlock != dots[name] {
+					c.problem(PROBLEM_DOT_ENTRY, p, []BlockID{entry.StartBlock}, "'%s' di '%s' menunjuk blok %d, seharusnya %d", name, p, entry.StartBlock, dots[name])
+				}
+				break
+			}
+		}
+		if !found {
+			c.problem(PROBLEM_DOT_ENTRY, p, nil, "direktori '%s' tidak punya entri '%s'", p, name)
+		}
+	}
+
+	// 2. Nama ganda (nama panjang dan alias pendek sama-sama dihitung)
+	names := map[string]bool{}
+	for _, entry := range entries {
+		candidates := []string{entry.NameString()}
+		if entry.LongName != "" {
+			candidates = append(candidates, entry.ShortName())
+		}
+		for _, name := range candidates {
+			if names[name] {
+				c.problem(PROBLEM_DUPLICATE, path.Join(p, name), nil, "nama '%s' muncul lebih dari sekali di '%s'", name, p)
+			}
+			names[name] = true
+		}
+	}
+
+	// 3. Isi direktori
+	for _, entry := range entries {
+		name := entry.NameString()
+		if name == "." || name == ".." {
+			continue
+		}
+		childPath := path.Join(p, name)
+		switch entry.Type {
+		case TYPE_DIRECTORY:
+			if !fs.validBlock(entry.StartBlock) {
+				c.problem(PROBLEM_INVALID_BLOCK, childPath, nil, "StartBlock direktori '%s' tidak valid: %d", childPath, entry.StartBlock)
+				continue
+			}
+			if owner := c.owners[entry.StartBlock]; owner != 0 {
+				// Direktori yang sudah diklaim tidak ditelusuri lagi, agar pohon yang membentuk siklus tetap berhenti
+				c.problem(PROBLEM_CROSS_LINK, childPath, []BlockID{entry.StartBlock}, "direktori '%s' memakai blok %d milik '%s'", childPath, entry.StartBlock, c.names[owner])
+				continue
+			}
+			c.checkDirectory(childPath, entry.StartBlock, dirBlock)
+		case TYPE_FILE, TYPE_SYMLINK:
+			c.checkFile(childPath, entry)
+		default:
+			c.problem(PROBLEM_INVALID_BLOCK, childPath, nil, "'%s' bertipe tidak dikenal (%d)", childPath, entry.Type)
+		}
+	}
+}
+
+// lostChains: Blok terpakai di luar area sistem yang tidak diklaim siapa pun, dikelompokkan menjadi rantai FAT.
+// Rantai dimulai dari blok yang tidak ditunjuk blok hilang lain; sisanya (siklus murni) dimulai dari blok terkecil.
+// Pada alokasi berindeks dan berurutan FAT tidak mencatat urutan blok data, jadi blok hilang yang bersebelahan
+// dikelompokkan menjadi satu rantai.
+func (c *checker) lostChains() [][]BlockID {
+	fs := c.fs
+	lost := make([]bool, len(fs.FAT))
+	pointed := make([]bool, len(fs.FAT))
+	for b, next := range fs.FAT {
+		lost[b] = next != FAT_FREE && !c.system[b] && c.owners[b] == 0
+	}
+	if fs.Geometry.Allocation != ALLOC_LINKED {
+		var chains [][]BlockID
+		for b := range lost {
+			if !lost[b] {
+				continue
+			}
+			if b > 0 && lost[b-1] {
+				chains[len(chains)-1] = append(chains[len(chains)-1], BlockID(b))
+			} else {
+				chains = append(chains, []BlockID{BlockID(b)})
+			}
+		}
+		return chains
+	}
+	for b, next := range fs.FAT {
+		if lost[b] && fs.validBlock(next) && lost[next] {
+			pointed[next] = true
+		}
+	}
+	visited := make([]bool, len(fs.FAT))
+	var chains [][]BlockID
+	follow := func(head int) {
+		var chain []BlockID
+		for b := BlockID(head); fs.validBlock(b) && lost[b] && !visited[b]; b = fs.FAT[b] {
+			visited[b] = true
+			chain = append(chain, b)
+		}
+		chains = append(chains, chain)
+	}
+	for b := range lost {
+		if lost[b] && !pointed[b] {
+			follow(b)
+		}
+	}
+	for b := range lost {
+		if lost[b] && !visited[b] {
+			follow(b)
+		}
+	}
+	return chains
+}
+
+// Check: Memeriksa konsistensi FAT dan pohon direktori fs. Pohon ditelusuri dari root tanpa memeriksa izin dan
+// tanpa memperbarui atime. Dengan opts.Repair, setiap rantai yang hilang dijadikan file di /LOST.DIR (lihat
+// recoverChain), lalu struktur ruang kosong dan jumlah blok kosong di superblock dihitung ulang dari FAT.
+// Masalah lain hanya dilaporkan.
+func Check(fs *FileSystem, opts CheckOptions) (CheckReport, error) {
+	report := CheckReport{}
+	if fs.Disk == nil || len(fs.FAT) != fs.Geometry.TotalBlocks {
+		return report, errors.New("disk belum diformat")
+	}
+	c := &checker{fs: fs, report: &report, owners: make([]int, len(fs.FAT)), names: []string{""},
+		system: make([]bool, len(fs.FAT)), linkSeen: map[BlockID]bool{}}
+
+	// 1. Area sistem dan nilai FAT
+	geo := fs.Geometry
+	var systemArea []BlockID
+	for b := BlockID(0); b < geo.RootBlock() && fs.validBlock(b); b++ {
+		c.system[b] = true
+		systemArea = append(systemArea, b)
+	}
+	c.claim("(system area)", systemArea)
+	for b, next := range fs.FAT {
+		if next != FAT_FREE && next != FAT_EOF && next != FAT_RESERVED && !fs.validBlock(next) {
+			c.problem(PROBLEM_INVALID_BLOCK, "", []BlockID{BlockID(b)}, "FAT[%d] berisi nomor blok tidak valid %d", b, next)
+		}
+		if next != FAT_FREE && !c.system[b] {
+			report.UsedBlocks++
+		}
+	}
+
+	// 2. Pohon direktori dari root, lalu file yang sudah dihapus tetapi masih terbuka (bloknya masih terpakai)
+	c.checkDirectory("/", fs.RootDirBlock, fs.RootDirBlock)
+	if fs.FileTable != nil {
+		checked := map[*vnode]bool{} // Beberapa handle bisa membuka file yang sama
+		for _, f := range fs.FileTable.files {
+			if f.node.unlinked && !checked[f.node] {
+				checked[f.node] = true
+				c.checkFile(f.path+" (deleted, still open)", f.node.entry)
+			}
+		}
+	}
+
+	// 3. Rantai yang hilang
+	chains := c.lostChains()
+	for _, chain := range chains {
+		c.problem(PROBLEM_LOST_CHAIN, "", chain, "%d blok terpakai tanpa pemilik mulai blok %d: %s", len(chain), chain[0], blockList(chain))
+	}
+	if !opts.Repair || len(chains) == 0 {
+		return report, nil
+	}
+
+	// 4. Perbaikan
+	lostDir, err := fs.lostDirectory()
+	if err != nil {
+		return report, fmt.Errorf("gagal menyiapkan /%s: %w", LOST_DIR_NAME, err)
+	}
+	for _, chain := range chains {
+		name, err := fs.recoverChain(lostDir, chain)
+		if err != nil {
+			return report, fmt.Errorf("gagal memulihkan rantai mulai blok %d: %w", chain[0], err)
+		}
+		report.Recovered = append(report.Recovered, "/"+LOST_DIR_NAME+"/"+name)
+	}
+	fs.touchDirectory(lostDir)
+	fs.rebuildFreeSpace()
+	if err := fs.SyncSuperblock(); err != nil {
+		return report, err
+	}
+	fmt.Printf("Check: %d rantai yang hilang dipindahkan ke /%s.\n", len(chains), LOST_DIR_NAME)
+	return report, nil
+}
+
+// lostDirectory: Blok awal /LOST.DIR, dibuat dulu jika belum ada.
+func (fs *FileSystem) lostDirectory() (BlockID, error) {
+	entry, err := fs.findEntry(fs.RootDirBlock, LOST_DIR_NAME)
+	if errors.Is(err, ErrNotExist) {
+		if err := fs.CreateDirectory(fs.RootDirBlock, LOST_DIR_NAME); err != nil {
+			return FAT_EOF, err
+		}
+		entry, err = fs.findEntry(fs.RootDirBlock, LOST_DIR_NAME)
+	}
+	if err != nil {
+		return FAT_EOF, err
+	}
+	if entry.Type != TYPE_DIRECTORY {
+		return FAT_EOF, fmt.Errorf("'/%s' sudah ada dan bukan direktori", LOST_DIR_NAME)
+	}
+	return entry.StartBlock, nil
+}
+
+// recoverChain: Menjadikan rantai yang hilang file FILEnnnn.CHK di lostDir dengan Size kelipatan ukuran blok.
+// Pada alokasi berantai, rantai dipasang apa adanya (ujungnya ditutup FAT_EOF agar siklus atau sambungan ke
+// rantai lain terputus). Pada metode lain, isi blok disalin ke file baru lewat Allocator, yang boleh memakai ulang
+// blok aslinya. Jika gagal, rantai dibiarkan hilang seperti semula (tanda FAT dan isinya tidak berubah).
+func (fs *FileSystem) recoverChain(lostDir BlockID, chain []BlockID) (string, error) {
+	var name string
+	for n := 1; ; n++ {
+		name = fmt.Sprintf("FILE%04d.CHK", n)
+		if _, err := fs.findEntry(lostDir, name); errors.Is(err, ErrNotExist) {
+			break
+		} else if err != nil {
+			return name, err
+		}
+	}
+
+	entry := DirectoryEntry{Type: TYPE_FILE, Size: int64(len(chain)) * int64(fs.Geometry.BlockSize)}
+	copy(entry.Name[:], name)
+	stampNew(&entry, time.Now().UnixNano())
+	fs.newEntryOwner(&entry, DEFAULT_FILE_MODE)
+
+	// Blok rantai dibebaskan lebih dulu agar file baru bisa memakainya lagi walaupun disk penuh. Jika file baru
+	// gagal dibuat, undo mengembalikan tanda FAT dan isi blok lama persis seperti semula: isinya mungkin sudah
+	// ditimpa pointer free list/grouping/counting atau oleh Resize sebelum alokasi yang gagal dibatalkan.
+	marks := make([]BlockID, len(chain))
+	data := make([][]byte, len(chain))
+	for i, b := range chain {
+		marks[i], data[i] = fs.FAT[b], append([]byte(nil), fs.Disk[b]...)
+	}
+	undo := func() {
+		for i, b := range chain {
+			fs.setFAT(b, marks[i])
+		}
+		for i, b := range chain {
+			copy(fs.Disk[b], data[i])
+		}
+	}
+
+	if fs.Geometry.Allocation == ALLOC_LINKED {
+		last := chain[len(chain)-1]
+		if fs.FAT[last] != FAT_EOF {
+			fs.setFAT(last, FAT_EOF)
+		}
+		entry.StartBlock = chain[0]
+	} else {
+		for _, b := range chain {
+			fs.setFAT(b, FAT_FREE)
+		}
+		entry.StartBlock = FAT_EOF
+		blocks, err := fs.allocator().Resize(&entry, len(chain))
+		if err != nil {
+			undo() // Resize sudah membebaskan lagi blok yang sempat dialokasikannya
+			return name, err
+		}
+		for i, b := range blocks {
+			copy(fs.Disk[b], data[i])
+		}
+		restore := undo
+		undo = func() {
+			if err := fs.freeFileBlocks(entry); err != nil {
+				fmt.Printf("Warning: Gagal membebaskan blok '%s': %v\n", name, err)
+			}
+			restore()
+		}
+	}
+
+	if err := fs.allocInode(&entry); err != nil {
+		undo()
+		return name, err
+	}
+	if err := fs.addEntryToDirectory(lostDir, entry); err != nil {
+		fs.freeInode(entry.Inode)
+		undo()
+		return name, err
+	}
+	return name, nil
+}
+
+// containsBlock: true jika block ada di blocks.
+func containsBlock(blocks []BlockID, block BlockID) bool {
+	for _, b := range blocks {
+		if b == block {
+			return true
+		}
+	}
+	return false
+}
+
+// blockList: Daftar nomor blok untuk pesan, dipotong jika terlalu panjang.
+func blockList(blocks []BlockID) string {
+	const limit = 8
+	parts := make([]string, 0, limit+1)
+	for i, b := range blocks {
+		if i == limit {
+			parts = append(parts, fmt.Sprintf("... (%d lagi)", len(blocks)-limit))
+			break
+		}
+		parts = append(parts, fmt.Sprint(b))
+	}
+	return strings.Join(parts, ", ")
+}
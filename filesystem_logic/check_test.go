@@ -0,0 +1,329 @@
+package filesystem_logic
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"path"
+	"sort"
+	"testing"
+)
+
+// patchFAT: Mengubah FAT[block] lewat PatchBlock, seperti mode expert di Block Inspector.
+func patchFAT(t *testing.T, fs *FileSystem, block, value BlockID) {
+	t.Helper()
+	perBlock := fs.Geometry.BlockSize / FAT_ENTRY_SIZE
+	raw := make([]byte, FAT_ENTRY_SIZE)
+	binary.LittleEndian.PutUint32(raw, uint32(value))
+	if err := fs.PatchBlock(BlockID(fs.superblock.FATStart)+block/BlockID(perBlock), int(block)%perBlock*FAT_ENTRY_SIZE, raw); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// orphan: Mengosongkan slot entri p lewat PatchBlock tanpa membebaskan bloknya, sehingga bloknya menjadi rantai
+// yang hilang. Mengembalikan semua blok file itu (data dan metadata alokasi).
+func orphan(t *testing.T, fs *FileSystem, p string) []BlockID {
+	t.Helper()
+	entry, err := fs.Lstat(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parent, err := fs.Lstat(path.Dir(p))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec, err := fs.findRecord(parent.StartBlock, path.Base(p))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := fs.PatchBlock(rec.Slot.Block, rec.Slot.Offset, []byte{0}); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := fs.allocator().Blocks(entry)
+	meta, _ := fs.allocator().MetaBlocks(entry)
+	blocks := append(meta, data...)
+	sort.Slice(blocks, func(i, j int) bool { return blocks[i] < blocks[j] })
+	return blocks
+}
+
+// pattern: Isi file yang bisa dikenali lagi setelah dipulihkan.
+func pattern(n int, seed byte) []byte {
+	data := make([]byte, n)
+	for i := range data {
+		data[i] = seed + byte(i%251)
+	}
+	return data
+}
+
+// forEachLayout: Menjalankan test untuk setiap metode alokasi, dengan dan tanpa tabel inode.
+func forEachLayout(t *testing.T, test func(t *testing.T, g Geometry)) {
+	for _, alloc := range AllocationMethods {
+		for _, inodes := range []int{0, 32} {
+			t.Run(fmt.Sprintf("%s/inodes=%d", alloc, inodes), func(t *testing.T) {
+				test(t, Geometry{Allocation: alloc, Inodes: inodes})
+			})
+		}
+	}
+}
+
+// Disk yang dipakai normal (nama panjang, symlink, hard link, file terhapus yang masih terbuka) harus bersih.
+func TestCheckCleanDisk(t *testing.T) {
+	forEachLayout(t, func(t *testing.T, g Geometry) {
+		fs := newTestDisk(t, g)
+		fs.Mkdir("/d")
+		for i := 0; i < 4; i++ {
+			if err := fs.WriteFile(fmt.Sprintf("/d/file %d with a long name.txt", i), pattern(300*i, byte(i))); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := fs.Symlink("/d/file 1 with a long name.txt", "/s"); err != nil {
+			t.Fatal(err)
+		}
+		if err := fs.Link("/d/file 2 with a long name.txt", "/h"); err != nil {
+			t.Fatal(err)
+		}
+		fs.WriteFile("/open", pattern(700, 9))
+		f, err := fs.Open("/open", O_RDONLY)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+		fs.Remove("/open")
+
+		report, err := Check(fs, CheckOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !report.Clean() {
+			t.Fatalf("disk bersih dilaporkan bermasalah: %+v", report.Problems)
+		}
+		if report.Directories != 2 || report.Files != 7 { // Hard link dihitung sekali
+			t.Fatalf("%d direktori, %d file; seharusnya 2 dan 7", report.Directories, report.Files)
+		}
+	})
+}
+
+// Kerusakan yang disuntikkan lewat PatchBlock (seperti dari Block Inspector) dikenali Check.
+func TestCheckDetectsPatchedCorruption(t *testing.T) {
+	forEachLayout(t, func(t *testing.T, g Geometry) {
+		setup := func(t *testing.T) *FileSystem {
+			fs := newTestDisk(t, g)
+			blockSize := fs.Geometry.BlockSize
+			fs.WriteFile("/a", pattern(3*blockSize, 1))
+			fs.WriteFile("/b", pattern(3*blockSize, 2))
+			fs.Mkdir("/d")
+			for i := 0; i < 4; i++ { // Cukup banyak entri agar /d memakai lebih dari satu blok
+				fs.WriteFile(fmt.Sprintf("/d/f%d", i), []byte("x"))
+			}
+			if report, err := Check(fs, CheckOptions{}); err != nil || !report.Clean() {
+				t.Fatalf("disk awal tidak bersih: %v %+v", err, report.Problems)
+			}
+			return fs
+		}
+		blocksOf := func(t *testing.T, fs *FileSystem, p string) []BlockID {
+			entry, _ := fs.Lstat(p)
+			blocks, err := fs.allocator().Blocks(entry)
+			if err != nil {
+				t.Fatal(err)
+			}
+			return blocks
+		}
+
+		t.Run("lost chain", func(t *testing.T) {
+			fs := setup(t)
+			lost := orphan(t, fs, "/a")
+			report, _ := Check(fs, CheckOptions{})
+			if report.Count(PROBLEM_LOST_CHAIN) == 0 || len(report.Problems) != report.Count(PROBLEM_LOST_CHAIN) {
+				t.Fatalf("seharusnya hanya rantai hilang: %+v", report.Problems)
+			}
+			var found []BlockID
+			for _, problem := range report.Problems {
+				found = append(found, problem.Blocks...)
+			}
+			sort.Slice(found, func(i, j int) bool { return found[i] < found[j] })
+			if fmt.Sprint(found) != fmt.Sprint(lost) {
+				t.Fatalf("blok rantai hilang %v, seharusnya %v", found, lost)
+			}
+		})
+
+		t.Run("cross-link", func(t *testing.T) {
+			fs := setup(t)
+			a, _ := fs.Lstat("/a")
+			shared := blocksOf(t, fs, "/b")[1]
+			if fs.Geometry.Allocation == ALLOC_LINKED {
+				patchFAT(t, fs, blocksOf(t, fs, "/a")[0], shared) // Rantai /a menyambung ke tengah rantai /b
+			} else {
+				// Pointer pertama blok indeks atau awal extent pertama /a menunjuk blok data /b
+				raw := make([]byte, FAT_ENTRY_SIZE)
+				binary.LittleEndian.PutUint32(raw, uint32(shared))
+				if err := fs.PatchBlock(a.StartBlock, 0, raw); err != nil {
+					t.Fatal(err)
+				}
+			}
+			report, _ := Check(fs, CheckOptions{})
+			for _, problem := range report.Problems {
+				if problem.Kind == PROBLEM_CROSS_LINK && containsBlock(problem.Blocks, shared) {
+					return
+				}
+			}
+			t.Fatalf("cross-link di blok %d tidak terdeteksi: %+v", shared, report.Problems)
+		})
+
+		t.Run("cycle", func(t *testing.T) {
+			fs := setup(t)
+			d, _ := fs.Lstat("/d")
+			chain, _ := fs.chainBlocks(d.StartBlock)
+			if len(chain) < 2 {
+				t.Fatalf("/d hanya %d blok", len(chain))
+			}
+			patchFAT(t, fs, chain[len(chain)-1], chain[0]) // Blok terakhir /d kembali ke blok pertamanya
+			report, _ := Check(fs, CheckOptions{})
+			if report.Count(PROBLEM_CYCLE) == 0 {
+				t.Fatalf("siklus tidak terdeteksi: %+v", report.Problems)
+			}
+		})
+	})
+}
+
+// recoveredBlocks: Isi semua file hasil pemulihan, dipotong per blok.
+func recoveredBlocks(t *testing.T, fs *FileSystem, names []string) map[string]bool {
+	t.Helper()
+	blocks := map[string]bool{}
+	for _, name := range names {
+		data, err := fs.ReadFile(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for off := 0; off < len(data); off += fs.Geometry.BlockSize {
+			blocks[string(data[off:min(off+fs.Geometry.BlockSize, len(data))])] = true
+		}
+	}
+	return blocks
+}
+
+// Perbaikan menjadikan setiap rantai yang hilang file FILEnnnn.CHK di /LOST.DIR, lalu disk bersih lagi dan struktur
+// ruang kosong sinkron dengan FAT. Pada alokasi berantai setiap file tepat berisi satu file yang hilang; pada metode
+// lain blok hilang yang bersebelahan menjadi satu file (termasuk blok indeks atau blok extent), jadi yang diperiksa
+// adalah setiap blok data file yang hilang ada di salah satu file hasil pemulihan.
+func TestCheckRepairToLostDir(t *testing.T) {
+	forEachLayout(t, func(t *testing.T, g Geometry) {
+		fs := newTestDisk(t, g)
+		blockSize := fs.Geometry.BlockSize
+		linked := fs.Geometry.Allocation == ALLOC_LINKED
+		a, b, c := pattern(2*blockSize, 1), pattern(blockSize, 2), pattern(3*blockSize, 3)
+		for _, file := range []struct {
+			name string
+			data []byte
+		}{{"/a", a}, {"/c", c}, {"/b", b}} { // /c di antara /a dan /b agar blok keduanya tidak bersebelahan
+			if err := fs.WriteFile(file.name, file.data); err != nil {
+				t.Fatal(err)
+			}
+		}
+		orphan(t, fs, "/a")
+		orphan(t, fs, "/b")
+		checkRecovered := func(report CheckReport, first int, lost ...[]byte) {
+			t.Helper()
+			if linked && len(report.Recovered) != len(lost) || len(report.Recovered) < len(lost) {
+				t.Fatalf("%d file hasil pemulihan untuk %d file yang hilang: %v", len(report.Recovered), len(lost), report.Recovered)
+			}
+			for i, name := range report.Recovered {
+				if want := fmt.Sprintf("/%s/FILE%04d.CHK", LOST_DIR_NAME, first+i); name != want {
+					t.Fatalf("file hasil pemulihan ke-%d: %s, seharusnya %s", i, name, want)
+				}
+			}
+			found := recoveredBlocks(t, fs, report.Recovered)
+			for i, data := range lost {
+				if linked {
+					got, _ := fs.ReadFile(report.Recovered[i]) // Rantai dipulihkan berurutan dari blok terkecil
+					if !bytes.Equal(got, data) {
+						t.Fatalf("isi %s bukan isi file yang hilang", report.Recovered[i])
+					}
+				}
+				for off := 0; off < len(data); off += blockSize {
+					if !found[string(data[off:off+blockSize])] {
+						t.Fatalf("blok data ke-%d file yang hilang tidak ditemukan di /%s", off/blockSize, LOST_DIR_NAME)
+					}
+				}
+			}
+		}
+
+		report, err := Check(fs, CheckOptions{Repair: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkRecovered(report, 1, a, b)
+		if data, _ := fs.ReadFile("/c"); !bytes.Equal(data, c) {
+			t.Fatal("isi /c berubah setelah perbaikan")
+		}
+		recovered := len(report.Recovered)
+
+		report, err = Check(fs, CheckOptions{})
+		if err != nil || !report.Clean() {
+			t.Fatalf("disk tidak bersih setelah perbaikan: %v %+v", err, report.Problems)
+		}
+		checkFreeSpaceSynced(t, fs, "perbaikan")
+
+		// Perbaikan berikutnya memakai /LOST.DIR yang sudah ada dan melanjutkan nomornya
+		orphan(t, fs, "/c")
+		report, err = Check(fs, CheckOptions{Repair: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkRecovered(report, recovered+1, c)
+	})
+}
+
+// Jika file pemulihan tidak bisa dibuat (disk penuh sehingga blok indeks atau blok extent tidak muat), rantai
+// yang hilang tetap utuh: tanda FAT dan isinya sama seperti sebelum pemulihan dicoba. Pada alokasi berantai
+// rantai dipasang di tempat, jadi pemulihan tetap berhasil.
+func TestRecoverChainFullDisk(t *testing.T) {
+	forEachLayout(t, func(t *testing.T, g Geometry) {
+		g.TotalBlocks = 64
+		fs := newTestDisk(t, g)
+		content := pattern(3*fs.Geometry.BlockSize, 7)
+		if err := fs.WriteFile("/a", content); err != nil {
+			t.Fatal(err)
+		}
+		if err := fs.Mkdir("/" + LOST_DIR_NAME); err != nil {
+			t.Fatal(err)
+		}
+		lostDir, _ := fs.Lstat("/" + LOST_DIR_NAME)
+		lost := orphan(t, fs, "/a")
+		for { // Habiskan semua blok kosong
+			if _, err := fs.newZeroBlock(); err != nil {
+				break
+			}
+		}
+		marks := make([]BlockID, len(lost))
+		data := make([][]byte, len(lost))
+		for i, b := range lost {
+			marks[i], data[i] = fs.FAT[b], append([]byte(nil), fs.Disk[b]...)
+		}
+
+		name, err := fs.recoverChain(lostDir.StartBlock, lost)
+		if fs.Geometry.Allocation == ALLOC_LINKED {
+			if err != nil {
+				t.Fatalf("pemulihan di tempat gagal: %v", err)
+			}
+			if got, _ := fs.ReadFile("/" + LOST_DIR_NAME + "/" + name); !bytes.Equal(got, content) {
+				t.Fatal("isi file pemulihan bukan isi /a")
+			}
+			return
+		}
+		if err == nil {
+			t.Fatalf("pemulihan di disk penuh seharusnya gagal, dapat %s", name)
+		}
+		for i, b := range lost {
+			if fs.FAT[b] != marks[i] || !bytes.Equal(fs.Disk[b], data[i]) {
+				t.Fatalf("blok %d berubah setelah pemulihan gagal (FAT %d, seharusnya %d)", b, fs.FAT[b], marks[i])
+			}
+		}
+		if _, err := fs.findEntry(lostDir.StartBlock, name); err == nil {
+			t.Fatalf("%s tertinggal setelah pemulihan gagal", name)
+		}
+		if free := fs.countFreeBlocks(); free != 0 {
+			t.Fatalf("%d blok kosong setelah pemulihan gagal, seharusnya 0", free)
+		}
+		checkFreeSpaceSynced(t, fs, "pemulihan gagal")
+	})
+}
@@ -0,0 +1,298 @@
+// contiguous.go
+package filesystem_logic
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Alokasi berurutan (Geometry.Allocation = ALLOC_CONTIGUOUS). Data file disimpan dalam satu atau beberapa
+// extent, yaitu deretan blok berurutan (Start, Length). entry.StartBlock menunjuk blok extent file yang
+// berisi pasangan pointer (start, length) seperti blok indeks di indexed.go; pasangan kosong bernilai FAT_EOF.
+//
+// File yang ditulis sekaligus (WriteToFile) selalu menjadi satu extent. Saat file diperpanjang, extent terakhir
+// dilebarkan di tempat jika blok di belakangnya masih kosong; jika tidak, extent baru dicari dengan strategi
+// fit di MountOptions.Fit. Jika tidak ada satu pun run blok kosong yang cukup panjang walaupun total blok kosong
+// mencukupi, alokasi gagal dengan *FragmentationError (external fragmentation).
+//
+// Strategi fit juga dipakai findFreeBlock untuk alokasi satu blok (blok direktori, blok indeks, rantai FAT).
+
+// Extent: Deretan Length blok berurutan mulai dari Start.
+type Extent struct {
+	Start  BlockID
+	Length int
+}
+
+// End: Blok pertama setelah extent.
+func (e Extent) End() BlockID { return e.Start + BlockID(e.Length) }
+
+// FitStrategy: Cara memilih run blok kosong saat alokasi (opsi mount, tidak disimpan di image).
+type FitStrategy int
+
+const (
+	FIT_FIRST FitStrategy = iota // Bawaan: run pertama yang cukup panjang (sama dengan pencarian linear lama)
+	FIT_BEST                     // Run terpendek yang masih cukup, menyisakan lubang sekecil mungkin
+	FIT_WORST                    // Run terpanjang, agar sisa lubangnya masih bisa dipakai
+)
+
+func (f FitStrategy) String() string {
+	switch f {
+	case FIT_BEST:
+		return "best fit"
+	case FIT_WORST:
+		return "worst fit"
+	default:
+		return "first fit"
+	}
+}
+
+// FitStrategies: Semua strategi fit, untuk pilihan di GUI.
+var FitStrategies = []FitStrategy{FIT_FIRST, FIT_BEST, FIT_WORST}
+
+// ErrFragmented: Dibungkus *FragmentationError; bisa dicek dengan errors.Is.
+var ErrFragmented = errors.New("external fragmentation")
+
+// FragmentationError: Alokasi berurutan gagal karena tidak ada run kosong yang cukup panjang,
+// padahal total blok kosong mencukupi.
+type FragmentationError struct {
+	Needed  int // Panjang run yang dibutuhkan
+	Largest int // Run kosong terpanjang
+	Free    int // Total blok kosong
+	Runs    int // Jumlah run kosong
+}
+
+func (e *FragmentationError) Error() string {
+	return fmt.Sprintf("tidak ada %d blok kosong yang berurutan: %d blok kosong tersebar di %d run, terpanjang %d blok (external fragmentation)",
+		e.Needed, e.Free, e.Runs, e.Largest)
+}
+
+func (e *FragmentationError) Unwrap() error { return ErrFragmented }
+
+// freeRuns: Semua run blok kosong (FAT_FREE) di disk, berurutan dari blok terkecil, langsung dari FAT
+// (tanpa FreeSpaceManager, jadi tidak dihitung sebagai biaya pencarian).
+func (fs *FileSystem) freeRuns() []Extent {
+	var runs []Extent
+	for i := 0; i < len(fs.FAT); i++ {
+		if fs.FAT[i] != FAT_FREE {
+			continue
+		}
+		run := Extent{Start: BlockID(i)}
+		for i < len(fs.FAT) && fs.FAT[i] == FAT_FREE {
+			run.Length++
+			i++
+		}
+		runs = append(runs, run)
+	}
+	return runs
+}
+
+// findFreeExtent: Mencari length blok kosong berurutan dengan strategi fs.Mount.Fit lewat FreeSpaceManager disk
+// (lihat freespace.go). Blok belum ditandai terpakai.
+func (fs *FileSystem) findFreeExtent(length int) (Extent, error) {
+	runs, found := fs.collectFreeRuns(length)
+	if found {
+		return Extent{Start: runs[len(runs)-1].Start, Length: length}, nil
+	}
+	var chosen *Extent
+	free, largest := 0, 0
+	for i := range runs {
+		run := &runs[i]
+		free += run.Length
+		largest = max(largest, run.Length)
+		if run.Length < length {
+			continue
+		}
+		switch {
+		case chosen == nil,
+			fs.Mount.Fit == FIT_BEST && run.Length < chosen.Length,
+			fs.Mount.Fit == FIT_WORST && run.Length > chosen.Length:
+			chosen = run
+		}
+	}
+	if chosen != nil {
+		return Extent{Start: chosen.Start, Length: length}, nil
+	}
+	if free < length {
+		if length == 1 {
+			return Extent{Start: -1}, errors.New("disk penuh, tidak ada blok kosong ditemukan")
+		}
+		return Extent{Start: -1}, fmt.Errorf("disk penuh: butuh %d blok, tersisa %d blok kosong", length, free)
+	}
+	return Extent{Start: -1}, &FragmentationError{Needed: length, Largest: largest, Free: free, Runs: len(runs)}
+}
+
+// claimExtent: Menandai blok-blok extent terpakai (FAT_EOF) dan mengisinya dengan nol.
+func (fs *FileSystem) claimExtent(ext Extent) {
+	for b := ext.Start; b < ext.End(); b++ {
+		fs.setFAT(b, FAT_EOF)
+		for i := range fs.Disk[b] {
+			fs.Disk[b][i] = 0
+		}
+	}
+}
+
+// releaseExtent: Membebaskan blok-blok extent.
+func (fs *FileSystem) releaseExtent(ext Extent) {
+	for b := ext.Start; b < ext.End(); b++ {
+		fs.setFAT(b, FAT_FREE)
+	}
+}
+
+type contiguousAllocator struct{ fs *FileSystem }
+
+func (a contiguousAllocator) Method() AllocationMethod { return ALLOC_CONTIGUOUS }
+
+// maxExtents: Jumlah pasangan (start, length) yang muat di satu blok extent.
+func (a contiguousAllocator) maxExtents() int {
+	return a.fs.Geometry.BlockSize / FAT_ENTRY_SIZE / 2
+}
+
+// extents: Membaca daftar extent file dari blok extent-nya.
+func (a contiguousAllocator) extents(entry DirectoryEntry) ([]Extent, error) {
+	if entry.StartBlock == FAT_EOF || entry.StartBlock == FAT_FREE {
+		return nil, nil
+	}
+	if entry.StartBlock < 0 || entry.StartBlock >= BlockID(a.fs.Geometry.TotalBlocks) {
+		return nil, fmt.Errorf("blok extent tidak valid (%d)", entry.StartBlock)
+	}
+	var exts []Extent
+	for i := 0; i < a.maxExtents(); i++ {
+		start := a.fs.blockPointer(entry.StartBlock, 2*i)
+		if start == FAT_EOF {
+			break
+		}
+		ext := Extent{Start: start, Length: int(a.fs.blockPointer(entry.StartBlock, 2*i+1))}
+		if start < 0 || ext.Length <= 0 || int(ext.End()) > a.fs.Geometry.TotalBlocks {
+			return exts, fmt.Errorf("extent tidak valid (%d, %d) di blok extent %d", start, ext.Length, entry.StartBlock)
+		}
+		exts = append(exts, ext)
+	}
+	return exts, nil
+}
+
+// writeExtents: Menulis daftar extent ke blok extent file; sisa pasangan dikosongkan.
+func (a contiguousAllocator) writeExtents(entry DirectoryEntry, exts []Extent) {
+	for i := 0; i < a.maxExtents(); i++ {
+		start, length := FAT_EOF, FAT_EOF
+		if i < len(exts) {
+			start, length = exts[i].Start, BlockID(exts[i].Length)
+		}
+		a.fs.setBlockPointer(entry.StartBlock, 2*i, start)
+		a.fs.setBlockPointer(entry.StartBlock, 2*i+1, length)
+	}
+}
+
+// expand: Semua blok data dari daftar extent, berurutan.
+func expand(exts []Extent) []BlockID {
+	var blocks []BlockID
+	for _, ext := range exts {
+		for b := ext.Start; b < ext.End(); b++ {
+			blocks = append(blocks, b)
+		}
+	}
+	return blocks
+}
+
+func (a contiguousAllocator) Blocks(entry DirectoryEntry) ([]BlockID, error) {
+	exts, err := a.extents(entry)
+	return expand(exts), err
+}
+
+func (a contiguousAllocator) MetaBlocks(entry DirectoryEntry) ([]BlockID, error) {
+	if _, err := a.extents(entry); err != nil || entry.StartBlock < 0 {
+		return nil, err
+	}
+	return []BlockID{entry.StartBlock}, nil
+}
+
+// freeAfter: Jumlah blok kosong berurutan mulai dari block (paling banyak limit).
+func (fs *FileSystem) freeAfter(block BlockID, limit int) int {
+	n := 0
+	for int(block)+n < fs.Geometry.TotalBlocks && n < limit && fs.FAT[int(block)+n] == FAT_FREE {
+		n++
+	}
+	return n
+}
+
+func (a contiguousAllocator) BlocksNeeded(dataBlocks int) int {
+	if dataBlocks <= 0 {
+		return 0
+	}
+	return dataBlocks + 1 // Ditambah blok extent
+}
+
+func (a contiguousAllocator) Resize(entry *DirectoryEntry, total int) ([]BlockID, error) {
+	exts, err := a.extents(*entry)
+	if err != nil {
+		return expand(exts), err
+	}
+	count := len(expand(exts))
+
+	// 1. Memperkecil: potong dari extent terakhir
+	if total <= count {
+		for count > total {
+			last := &exts[len(exts)-1]
+			drop := min(last.Length, count-total)
+			a.fs.releaseExtent(Extent{Start: last.End() - BlockID(drop), Length: drop})
+			last.Length -= drop
+			count -= drop
+			if last.Length == 0 {
+				exts = exts[:len(exts)-1]
+			}
+		}
+		if total == 0 && entry.StartBlock >= 0 {
+			a.fs.setFAT(entry.StartBlock, FAT_FREE)
+			entry.StartBlock = FAT_EOF
+		} else if entry.StartBlock >= 0 {
+			a.writeExtents(*entry, exts)
+		}
+		return expand(exts), nil
+	}
+
+	// 2. Memperbesar: lebarkan extent terakhir di tempat, atau tambahkan satu extent baru
+	need := total - count
+	created := false
+	if entry.StartBlock < 0 {
+		block, err := a.fs.newPointerBlock()
+		if err != nil {
+			return nil, err
+		}
+		entry.StartBlock, created = block, true
+	}
+	if len(exts) > 0 && a.fs.freeAfter(exts[len(exts)-1].End(), need) == need {
+		last := &exts[len(exts)-1]
+		a.fs.claimExtent(Extent{Start: last.End(), Length: need})
+		last.Length += need
+	} else {
+		ext, err := a.fs.findFreeExtent(need)
+		if err == nil && len(exts) >= a.maxExtents() {
+			err = fmt.Errorf("file '%s' sudah memakai %d extent (maksimum satu blok extent)", entry.NameString(), len(exts))
+		}
+		if err != nil {
+			if created {
+				a.fs.setFAT(entry.StartBlock, FAT_FREE)
+				entry.StartBlock = FAT_EOF
+			}
+			return expand(exts), err
+		}
+		a.fs.claimExtent(ext)
+		exts = append(exts, ext)
+	}
+	a.writeExtents(*entry, exts)
+	return expand(exts), nil
+}
+
+func (a contiguousAllocator) Describe(entry DirectoryEntry) (AllocationNode, error) {
+	exts, err := a.extents(entry)
+	if entry.StartBlock < 0 {
+		return AllocationNode{Label: "No blocks allocated", Block: FAT_EOF}, err
+	}
+	node := AllocationNode{Label: fmt.Sprintf("Extent block %d: %d extent(s)", entry.StartBlock, len(exts)), Block: entry.StartBlock}
+	for i, ext := range exts {
+		node.Children = append(node.Children, AllocationNode{
+			Label: fmt.Sprintf("[%d] blocks %d–%d (%d block(s))", i, ext.Start, ext.End()-1, ext.Length),
+			Block: ext.Start,
+		})
+	}
+	return node, err
+}
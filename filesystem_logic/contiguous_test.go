@@ -0,0 +1,112 @@
+package filesystem_logic
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// leaveHoles: Menandai semua blok kosong terpakai lalu membebaskan hanya holes, agar posisi run kosong pasti.
+func leaveHoles(fs *FileSystem, holes ...Extent) {
+	for b := range fs.FAT {
+		if fs.FAT[b] == FAT_FREE {
+			fs.setFAT(BlockID(b), FAT_EOF)
+		}
+	}
+	for _, hole := range holes {
+		fs.releaseExtent(hole)
+	}
+}
+
+// File diperpanjang di tempat selama blok di belakang extent terakhirnya kosong; jika tidak, extent baru
+// ditambahkan. Saat dipotong, extent terakhir yang dibuang lebih dulu.
+func TestContiguousExtents(t *testing.T) {
+	fs := newTestDisk(t, Geometry{TotalBlocks: 64, Allocation: ALLOC_CONTIGUOUS})
+	a := contiguousAllocator{fs}
+	extentsOf := func(entry DirectoryEntry) []Extent {
+		t.Helper()
+		exts, err := a.extents(entry)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return exts
+	}
+
+	file := DirectoryEntry{StartBlock: FAT_EOF}
+	if _, err := a.Resize(&file, 3); err != nil {
+		t.Fatal(err)
+	}
+	first := extentsOf(file)
+	if _, err := a.Resize(&file, 5); err != nil {
+		t.Fatal(err)
+	}
+	if exts := extentsOf(file); len(exts) != 1 || exts[0].Start != first[0].Start || exts[0].Length != 5 {
+		t.Fatalf("extent setelah diperpanjang di tempat: %v, seharusnya satu extent 5 blok mulai %d", exts, first[0].Start)
+	}
+
+	// File lain tepat di belakang extent, sehingga perpanjangan berikutnya butuh extent baru
+	other := DirectoryEntry{StartBlock: FAT_EOF}
+	if _, err := a.Resize(&other, 2); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := a.Resize(&file, 8); err != nil {
+		t.Fatal(err)
+	}
+	exts := extentsOf(file)
+	if len(exts) != 2 || exts[0].Length != 5 || exts[1].Length != 3 {
+		t.Fatalf("extent setelah diperpanjang: %v, seharusnya 5 blok + 3 blok", exts)
+	}
+	checkAllocation(t, fs, file, 8)
+	checkAllocation(t, fs, other, 2)
+
+	if _, err := a.Resize(&file, 4); err != nil {
+		t.Fatal(err)
+	}
+	if exts := extentsOf(file); len(exts) != 1 || exts[0].Length != 4 {
+		t.Fatalf("extent setelah dipotong: %v, seharusnya satu extent 4 blok", exts)
+	}
+	checkAllocation(t, fs, file, 4)
+}
+
+// Strategi fit memilih run kosong yang berbeda untuk permintaan yang sama.
+func TestContiguousFitStrategies(t *testing.T) {
+	holes := []Extent{{Start: 20, Length: 3}, {Start: 30, Length: 2}, {Start: 40, Length: 5}}
+	want := map[FitStrategy]BlockID{FIT_FIRST: 20, FIT_BEST: 30, FIT_WORST: 40}
+	for _, fit := range FitStrategies {
+		t.Run(fit.String(), func(t *testing.T) {
+			fs := newTestDisk(t, Geometry{TotalBlocks: 64, Allocation: ALLOC_CONTIGUOUS})
+			fs.Mount.Fit = fit
+			leaveHoles(fs, holes...)
+			ext, err := fs.findFreeExtent(2)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if ext.Start != want[fit] || ext.Length != 2 {
+				t.Fatalf("extent %+v, seharusnya 2 blok mulai %d", ext, want[fit])
+			}
+		})
+	}
+}
+
+// Jika blok kosong cukup tetapi tidak ada run yang cukup panjang, Resize gagal dengan *FragmentationError
+// dan blok extent yang terlanjur dialokasikan dibebaskan lagi.
+func TestContiguousFragmentation(t *testing.T) {
+	fs := newTestDisk(t, Geometry{TotalBlocks: 64, Allocation: ALLOC_CONTIGUOUS})
+	leaveHoles(fs, Extent{Start: 20, Length: 2}, Extent{Start: 30, Length: 2}, Extent{Start: 40, Length: 2})
+
+	file := DirectoryEntry{StartBlock: FAT_EOF}
+	_, err := contiguousAllocator{fs}.Resize(&file, 3)
+	var fragErr *FragmentationError
+	if !errors.As(err, &fragErr) || !errors.Is(err, ErrFragmented) {
+		t.Fatalf("seharusnya *FragmentationError, dapat: %v", err)
+	}
+	if fragErr.Needed != 3 || fragErr.Largest != 2 || fragErr.Free < fragErr.Needed {
+		t.Fatalf("FragmentationError %+v tidak sesuai", *fragErr)
+	}
+	if file.StartBlock != FAT_EOF || fs.countFreeBlocks() != 6 {
+		t.Fatalf("StartBlock %d, %d blok kosong setelah gagal; seharusnya FAT_EOF dan 6", file.StartBlock, fs.countFreeBlocks())
+	}
+	if got := fmt.Sprint(fs.freeRuns()); got != "[{20 2} {30 2} {40 2}]" {
+		t.Fatalf("run kosong setelah gagal: %s", got)
+	}
+}
@@ -0,0 +1,168 @@
+// copy.go
+package filesystem_logic
+
+import (
+	"errors"
+	"fmt"
+	"path"
+)
+
+// Copy: Menyalin src ke dst di disk yang sama. Lihat CopyTo.
+func (fs *FileSystem) Copy(src, dst string, recursive bool) error {
+	return fs.CopyTo(fs, src, dst, recursive, nil)
+}
+
+// CopyTo: Menyalin file atau direktori src di disk ini ke dst di disk dstFS (boleh disk yang sama).
+// Semua data ditulis ke blok-blok baru. Jika dst adalah direktori yang sudah ada, salinan dibuat di
+// dalamnya dengan nama yang sama seperti src; jika tidak, dst menjadi nama salinan dan tidak boleh sudah ada.
+// Direktori hanya disalin jika recursive bernilai true. Symlink disalin sebagai symlink dengan tujuan
+// yang sama (tidak diikuti), dan setiap hard link menjadi file tersendiri di tujuan.
+//
+// Sebelum mulai, seluruh pohon src diperiksa: nama harus muat di geometri tujuan, inode bebas di dstFS harus
+// cukup untuk setiap entri baru (jika disk memakai tabel inode), dan blok kosong di dstFS harus cukup untuk
+// data, blok metadata alokasi (Allocator.BlocksNeeded), dan blok direktori, sehingga penyalinan tidak berhenti
+// di tengah jalan karena disk penuh. Satu-satunya pengecualian adalah alokasi berurutan (ALLOC_CONTIGUOUS):
+// blok kosong yang cukup belum menjamin ada run blok berurutan yang cukup panjang, jadi penyalinan masih bisa
+// gagal dengan *FragmentationError. Salinan yang setengah jadi selalu dihapus lagi.
+func (fs *FileSystem) CopyTo(dstFS *FileSystem, src, dst string, recursive bool, progress ProgressFunc) error {
+	if dstFS == nil {
+		return errors.New("FileSystem tujuan tidak boleh nil")
+	}
+	srcEntry, err := fs.Lstat(src)
+	if err != nil {
+		return err
+	}
+	if srcEntry.Type == TYPE_DIRECTORY && !recursive {
+		return fmt.Errorf("'%s' %w, gunakan salin rekursif", src, ErrIsDir)
+	}
+
+	// 1. Tentukan path tujuan akhir
+	target := dst
+	if dstEntry, err := dstFS.Lookup(dst); err == nil {
+		if dstEntry.Type != TYPE_DIRECTORY {
+			return fmt.Errorf("'%s' %w", dst, ErrExist)
+		}
+		srcName := srcEntry.NameString()
+		if srcName == "/" || srcName == "." || srcName == ".." {
+			return fmt.Errorf("'%s' tidak bisa disalin ke dalam direktori tanpa nama tujuan", src)
+		}
+		target = path.Join(dst, srcName)
+	} else if !errors.Is(err, ErrNotExist) {
+		return err
+	}
+	targetParent, targetName, err := dstFS.resolveParent(target)
+	if err != nil {
+		return err
+	}
+	if _, err := dstFS.findEntry(targetParent, targetName); err == nil {
+		return fmt.Errorf("'%s' %w", target, ErrExist)
+	}
+	if dstFS == fs && srcEntry.Type == TYPE_DIRECTORY {
+		inside, err := fs.isInsideDirectory(targetParent, srcEntry.StartBlock)
+		if err != nil {
+			return err
+		}
+		if inside {
+			return fmt.Errorf("tidak dapat menyalin direktori '%s' ke dalam dirinya sendiri", src)
+		}
+	}
+
+	// 2. Hitung kebutuhan blok di disk tujuan dan periksa nama
+	blockSize := int64(dstFS.Geometry.BlockSize)
+	entriesPerBlock := dstFS.Geometry.EntriesPerBlock()
+	alloc := dstFS.allocator()
+	var totalBytes int64
+	blocksNeeded := 1 // Cadangan jika direktori induk tujuan perlu blok baru
+	entries := 0      // Entri yang akan dibuat, masing-masing memakai satu inode di disk dengan tabel inode
+	err = fs.walkTree(srcEntry, "", func(relPath string, e DirectoryEntry) error {
+		name := targetName
+		if relPath != "" {
+			name = path.Base(relPath)
+		}
+		if err := validateName(name); err != nil {
+			return err
+		}
+		entries++
+		if e.Type != TYPE_DIRECTORY {
+			totalBytes += e.Size
+			fileBlocks := int((e.Size + blockSize - 1) / blockSize)
+			if fileBlocks == 0 {
+				fileBlocks = 1 // CreateFile selalu mengalokasikan satu blok
+			}
+			blocksNeeded += alloc.BlocksNeeded(fileBlocks) // Termasuk blok indeks atau blok extent
+			return nil
+		}
+		children, err := fs.ListEntries(e.StartBlock)
+		if err != nil {
+			return err
+		}
+		slots := 0 // Nama panjang memakai beberapa slot (lihat slotsForName)
+		for _, child := range children {
+			slots += dstFS.slotsForName(child.NameString())
+		}
+		blocksNeeded += (slots + entriesPerBlock - 1) / entriesPerBlock
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if free := int(dstFS.countFreeBlocks()); free < blocksNeeded {
+		return fmt.Errorf("disk tujuan tidak cukup: butuh %d blok, tersisa %d blok", blocksNeeded, free)
+	}
+	if used, total := dstFS.InodeUsage(); dstFS.hasInodes() && total-used < entries {
+		return fmt.Errorf("tabel inode disk tujuan tidak cukup: butuh %d inode, tersisa %d inode", entries, total-used)
+	}
+
+	// 3. Salin; jika tetap gagal di tengah jalan, hapus lagi salinan yang setengah jadi
+	var done int64
+	err = fs.walkTree(srcEntry, "", func(relPath string, e DirectoryEntry) error {
+		dstPath := path.Join(target, relPath)
+		switch e.Type {
+		case TYPE_DIRECTORY:
+			if err := dstFS.Mkdir(dstPath); err != nil {
+				return err
+			}
+		case TYPE_SYMLINK:
+			linkTarget, err := fs.Readlink(path.Join(src, relPath))
+			if err != nil {
+				return err
+			}
+			if err := dstFS.Symlink(linkTarget, dstPath); err != nil {
+				return err
+			}
+			done += e.Size
+		default:
+			if err := fs.copyFileData(path.Join(src, relPath), dstFS, dstPath); err != nil {
+				return err
+			}
+			done += e.Size
+		}
+		if progress != nil {
+			progress(done, totalBytes, dstPath)
+		}
+		return nil
+	})
+	if err != nil {
+		dstFS.RemoveAll(target)
+		return fmt.Errorf("gagal menyalin '%s' ke '%s': %w", src, target, err)
+	}
+	fmt.Printf("'%s' (%d bytes) berhasil disalin ke '%s'.\n", src, totalBytes, target)
+	return nil
+}
+
+// copyFileData: Membuat file baru di dstPath pada dstFS dan mengisinya dengan isi file srcPath.
+func (fs *FileSystem) copyFileData(srcPath string, dstFS *FileSystem, dstPath string) error {
+	data, err := fs.ReadFile(srcPath)
+	if err != nil {
+		return err
+	}
+	f, err := dstFS.Open(dstPath, O_WRONLY|O_CREATE|O_EXCL)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
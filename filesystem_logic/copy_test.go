@@ -0,0 +1,218 @@
+package filesystem_logic
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// fillTo: Menulis /filler sebesar mungkin sehingga disk tinggal punya free blok kosong.
+func fillTo(t *testing.T, fs *FileSystem, free int) {
+	t.Helper()
+	for n := int(fs.countFreeBlocks()) - free; n > 0; n-- {
+		if err := fs.WriteFile("/filler", make([]byte, n*fs.Geometry.BlockSize)); err == nil && int(fs.countFreeBlocks()) == free {
+			return
+		}
+		fs.Remove("/filler")
+	}
+	t.Fatalf("tidak bisa menyisakan tepat %d blok kosong", free)
+}
+
+// Pohon direktori bisa disalin ke disk lain dengan ukuran blok berbeda; sumbernya tidak berubah.
+func TestCopyTree(t *testing.T) {
+	src := newTestDisk(t, Geometry{})
+	dst := newTestDisk(t, Geometry{BlockSize: 1024, TotalBlocks: 64})
+	for _, dir := range []string{"/proj", "/proj/src", "/proj/empty"} {
+		if err := src.Mkdir(dir); err != nil {
+			t.Fatal(err)
+		}
+	}
+	files := map[string][]byte{
+		"/proj/README":     []byte("baca saya"),
+		"/proj/src/a.go":   bytes.Repeat([]byte("package a\n"), 60),
+		"/proj/src/b.go":   nil,
+		"/proj/src/c.data": bytes.Repeat([]byte{7}, 3*src.Geometry.BlockSize),
+	}
+	for name, data := range files {
+		if err := src.WriteFile(name, data); err != nil {
+			t.Fatal(err)
+		}
+	}
+	srcFree := src.countFreeBlocks()
+
+	var last, total int64
+	if err := src.CopyTo(dst, "/proj", "/", true, func(done, all int64, _ string) { last, total = done, all }); err != nil {
+		t.Fatal(err)
+	}
+	for name, want := range files {
+		for _, fs := range []*FileSystem{src, dst} {
+			if got, err := fs.ReadFile(name); err != nil || !bytes.Equal(got, want) {
+				t.Fatalf("isi %s: %d byte, %v; seharusnya %d byte", name, len(got), err, len(want))
+			}
+		}
+	}
+	if entry, err := dst.Lookup("/proj/empty"); err != nil || entry.Type != TYPE_DIRECTORY {
+		t.Fatalf("direktori kosong tidak ikut tersalin: %v", err)
+	}
+	size, _ := src.MeasureTree("/proj")
+	if size.Files != 4 || size.Directories != 3 || last != size.Bytes || total != size.Bytes {
+		t.Fatalf("MeasureTree %+v, progress terakhir %d/%d", size, last, total)
+	}
+	if src.countFreeBlocks() != srcFree {
+		t.Fatalf("blok kosong disk sumber berubah: %d -> %d", srcFree, src.countFreeBlocks())
+	}
+
+	// Di disk yang sama, dst yang belum ada menjadi nama salinan
+	if err := src.Copy("/proj/src", "/salinan", true); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := src.ReadFile("/salinan/a.go"); !bytes.Equal(got, files["/proj/src/a.go"]) {
+		t.Fatal("isi /salinan/a.go berbeda")
+	}
+}
+
+// Penyalinan yang tidak mungkin ditolak sebelum apa pun dibuat di tujuan.
+func TestCopyRejected(t *testing.T) {
+	fs := newTestDisk(t, Geometry{})
+	for _, dir := range []string{"/a", "/a/b"} {
+		if err := fs.Mkdir(dir); err != nil {
+			t.Fatal(err)
+		}
+	}
+	fs.WriteFile("/a/b/nama-yang-panjang.txt", []byte("x"))
+	fs.WriteFile("/f", []byte("f"))
+
+	cases := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"direktori tanpa rekursif", fs.Copy("/a", "/c", false), "rekursif"},
+		{"tujuan sudah ada", fs.Copy("/a/b/nama-yang-panjang.txt", "/f", false), ErrExist.Error()},
+		{"ke dalam dirinya sendiri", fs.Copy("/a", "/a/b/c", true), "dirinya sendiri"},
+		{"tujuan di dalam file", fs.Copy("/a", "/f/a", true), ErrNotDir.Error()},
+	}
+	for _, c := range cases {
+		if c.err == nil || !strings.Contains(c.err.Error(), c.want) {
+			t.Errorf("%s: %v, seharusnya mengandung '%s'", c.name, c.err, c.want)
+		}
+	}
+	if _, err := fs.Lookup("/a/b/c"); !errors.Is(err, ErrNotExist) {
+		t.Fatalf("/a/b/c tertinggal: %v", err)
+	}
+}
+
+// Copy di disk yang hampir penuh harus ditolak oleh pemeriksaan awal, tidak boleh gagal di tengah jalan,
+// termasuk blok metadata alokasi (blok indeks, blok extent) yang dibutuhkan setiap file.
+func TestCopyNearFullDisk(t *testing.T) {
+	for _, alloc := range AllocationMethods {
+		for free := 4; free <= 14; free++ {
+			t.Run(fmt.Sprintf("%s/free=%d", alloc, free), func(t *testing.T) {
+				fs, err := NewFileSystem(FileSystemOptions{Geometry: Geometry{TotalBlocks: 64, Allocation: alloc}})
+				if err != nil {
+					t.Fatal(err)
+				}
+				fs.Mkdir("/src")
+				for i := 0; i < 4; i++ {
+					if err := fs.WriteFile(fmt.Sprintf("/src/f%d", i), []byte("0123456789")); err != nil {
+						t.Fatal(err)
+					}
+				}
+				fillTo(t, fs, free)
+
+				err = fs.Copy("/src", "/dst", true)
+				if err == nil {
+					for i := 0; i < 4; i++ {
+						if data, err := fs.ReadFile(fmt.Sprintf("/dst/f%d", i)); err != nil || string(data) != "0123456789" {
+							t.Fatalf("isi /dst/f%d: %q, %v", i, data, err)
+						}
+					}
+					return
+				}
+				if !strings.HasPrefix(err.Error(), "disk tujuan tidak cukup") {
+					t.Fatalf("penyalinan gagal di tengah jalan: %v", err)
+				}
+				if _, errStat := fs.Lstat("/dst"); !errors.Is(errStat, ErrNotExist) {
+					t.Fatalf("/dst tertinggal setelah pemeriksaan awal gagal: %v", errStat)
+				}
+				if got := int(fs.countFreeBlocks()); got != free {
+					t.Fatalf("blok kosong %d setelah gagal, seharusnya tetap %d", got, free)
+				}
+			})
+		}
+	}
+}
+
+// Di disk dengan tabel inode, Copy harus ditolak sebelum mulai jika inode bebas tidak cukup untuk semua entri baru.
+func TestCopyInodeTableFull(t *testing.T) {
+	fs, err := NewFileSystem(FileSystemOptions{Geometry: Geometry{Inodes: 8}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	fs.Mkdir("/src")
+	for i := 0; i < 4; i++ {
+		if err := fs.WriteFile(fmt.Sprintf("/src/f%d", i), []byte("0123456789")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	used, total := fs.InodeUsage()
+	free := fs.countFreeBlocks()
+
+	err = fs.Copy("/src", "/dst", true)
+	if err == nil || !strings.HasPrefix(err.Error(), "tabel inode disk tujuan tidak cukup") {
+		t.Fatalf("seharusnya ditolak karena inode bebas hanya %d: %v", total-used, err)
+	}
+	if _, errStat := fs.Lstat("/dst"); !errors.Is(errStat, ErrNotExist) {
+		t.Fatalf("/dst tertinggal setelah pemeriksaan awal gagal: %v", errStat)
+	}
+	if usedAfter, _ := fs.InodeUsage(); usedAfter != used || fs.countFreeBlocks() != free {
+		t.Fatalf("inode terpakai %d (seharusnya %d), blok kosong %d (seharusnya %d)", usedAfter, used, fs.countFreeBlocks(), free)
+	}
+
+	// Satu file masih muat
+	if err := fs.Copy("/src/f0", "/g0", false); err != nil {
+		t.Fatal(err)
+	}
+	if data, err := fs.ReadFile("/g0"); err != nil || string(data) != "0123456789" {
+		t.Fatalf("isi /g0: %q, %v", data, err)
+	}
+}
+
+// Pada alokasi berurutan, blok kosong yang cukup belum menjamin ada run yang cukup panjang: penyalinan boleh
+// gagal karena external fragmentation, tetapi salinan yang setengah jadi harus dihapus lagi.
+func TestCopyFragmentedContiguous(t *testing.T) {
+	fs, err := NewFileSystem(FileSystemOptions{Geometry: Geometry{TotalBlocks: 64, Allocation: ALLOC_CONTIGUOUS}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	blockSize := fs.Geometry.BlockSize
+	if err := fs.WriteFile("/big", make([]byte, 6*blockSize)); err != nil {
+		t.Fatal(err)
+	}
+	// Isi disk dengan file kecil lalu hapus setiap file kedua, sehingga blok kosong tersebar dalam run pendek
+	for i := 0; fs.countFreeBlocks() >= 2; i++ {
+		if err := fs.WriteFile(fmt.Sprintf("/h%02d", i), []byte("x")); err != nil {
+			break
+		}
+	}
+	for i := 0; i < 64; i += 2 {
+		fs.Remove(fmt.Sprintf("/h%02d", i))
+	}
+	free := int(fs.countFreeBlocks())
+	if free < 8 {
+		t.Fatalf("hanya %d blok kosong, skenario tidak valid", free)
+	}
+
+	err = fs.Copy("/big", "/big2", false)
+	if !errors.Is(err, ErrFragmented) {
+		t.Fatalf("seharusnya gagal karena fragmentasi (%d blok kosong), dapat: %v", free, err)
+	}
+	if _, errStat := fs.Lstat("/big2"); !errors.Is(errStat, ErrNotExist) {
+		t.Fatalf("/big2 tertinggal setelah gagal: %v", errStat)
+	}
+	if got := int(fs.countFreeBlocks()); got != free {
+		t.Fatalf("blok kosong %d setelah gagal, seharusnya tetap %d", got, free)
+	}
+}
@@ -0,0 +1,101 @@
+// directory.go
+package filesystem_logic
+
+import (
+	"fmt"
+)
+
+// growDirectory: Memperpanjang rantai direktori dengan satu blok baru setelah lastBlock (blok terakhir direktori).
+// Blok baru diisi nol agar semua slotnya terbaca kosong oleh ListEntries.
+func (fs *FileSystem) growDirectory(lastBlock BlockID) (BlockID, error) {
+	if fs.FAT[lastBlock] != FAT_EOF {
+		return -1, fmt.Errorf("blok %d bukan blok terakhir direktori", lastBlock)
+	}
+	newBlock, err := fs.findFreeBlock()
+	if err != nil {
+		return -1, err
+	}
+	fs.setFAT(newBlock, FAT_EOF)
+	for i := range fs.Disk[newBlock] {
+		fs.Disk[newBlock][i] = 0
+	}
+	fs.setFAT(lastBlock, newBlock) // Sambungkan blok baru ke ujung rantai
+	fmt.Printf("Direktori diperluas: blok %d disambungkan setelah blok %d.\n", newBlock, lastBlock)
+	return newBlock, nil
+}
+
+// isDirectoryBlockEmpty: true jika tidak ada satu pun slot terpakai di blok direktori.
+func (fs *FileSystem) isDirectoryBlockEmpty(block BlockID) bool {
+	for offset := 0; offset+DIRECTORY_ENTRY_SIZE <= fs.Geometry.BlockSize; offset += DIRECTORY_ENTRY_SIZE {
+		if fs.Disk[block][offset] != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// compactDirectory: Melepas blok-blok direktori yang sudah tidak berisi entri sama sekali (setelah penghapusan),
+// sehingga rantai direktori menyusut lagi. Blok pertama tidak pernah dilepas karena berisi "." dan ".."
+// dan menjadi StartBlock yang dirujuk direktori induk.
+func (fs *FileSystem) compactDirectory(dirStartBlock BlockID) error {
+	prevBlock := dirStartBlock
+	currentBlock := fs.FAT[dirStartBlock]
+	for steps := 0; currentBlock != FAT_EOF; steps++ {
+		if currentBlock < 0 || currentBlock >= BlockID(fs.Geometry.TotalBlocks) {
+			return fmt.Errorf("nomor blok tidak valid (%d) dalam rantai direktori", currentBlock)
+		}
+		if steps > fs.Geometry.TotalBlocks { // Pengaman jika FAT membentuk siklus
+			return fmt.Errorf("rantai direktori mulai blok %d membentuk siklus", dirStartBlock)
+		}
+		nextBlock := fs.FAT[currentBlock]
+		if fs.isDirectoryBlockEmpty(currentBlock) {
+			fs.setFAT(prevBlock, nextBlock) // Lewati blok kosong di rantai
+			fs.setFAT(currentBlock, FAT_FREE)
+			fmt.Printf("Blok direktori %d kosong dan dilepas dari rantai.\n", currentBlock)
+		} else {
+			prevBlock = currentBlock
+		}
+		currentBlock = nextBlock
+	}
+	return nil
+}
+
+// dirSlot: Lokasi satu DirectoryEntry di disk (blok direktori dan offset di dalam blok).
+// Lokasi ini tetap selama entri tidak dihapus, jadi dipakai sebagai identitas file yang sedang terbuka.
+type dirSlot struct {
+	Block  BlockID
+	Offset int
+}
+
+// findEntrySlot: Mencari entri bernama name di direktori dan mengembalikan lokasi slot entri pendeknya.
+func (fs *FileSystem) findEntrySlot(dirStartBlock BlockID, name string) (dirSlot, DirectoryEntry, error) {
+	rec, err := fs.findRecord(dirStartBlock, name)
+	if err != nil {
+		return dirSlot{}, DirectoryEntry{}, err
+	}
+	return rec.Slot, rec.Entry, nil
+}
+
+// readSlot: Membaca entri yang tersimpan di sebuah slot.
+func (fs *FileSystem) readSlot(slot dirSlot) (DirectoryEntry, error) {
+	if fs.Disk[slot.Block][slot.Offset] == 0 {
+		return DirectoryEntry{}, fmt.Errorf("slot blok %d offset %d kosong", slot.Block, slot.Offset)
+	}
+	return fs.decodeSlot(fs.Disk[slot.Block][slot.Offset : slot.Offset+DIRECTORY_ENTRY_SIZE])
+}
+
+// writeSlot: Menimpa entri di sebuah slot. Jika entri lamanya punya hard link lain, perubahannya
+// ikut disalin ke link-link tersebut (lihat syncLinks) agar metadata seluruh grup tetap sama.
+// Pada layout inode semua link berbagi inode, jadi penyalinan itu tidak diperlukan.
+func (fs *FileSystem) writeSlot(slot dirSlot, entry DirectoryEntry) error {
+	old, errOld := fs.readSlot(slot)
+	entryBytes, err := fs.encodeSlot(entry)
+	if err != nil {
+		return fmt.Errorf("gagal serialize entri: %w", err)
+	}
+	copy(fs.Disk[slot.Block][slot.Offset:], entryBytes)
+	if errOld == nil && old.hasLinks() && !fs.hasInodes() {
+		return fs.syncLinks(old, slot, entry)
+	}
+	return nil
+}
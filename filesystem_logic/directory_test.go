@@ -0,0 +1,74 @@
+package filesystem_logic
+
+import (
+	"fmt"
+	"testing"
+)
+
+// Direktori tumbuh ke blok baru saat blok terakhirnya penuh, dan blok yang kosong setelah penghapusan
+// dilepas lagi sampai tinggal blok pertama.
+func TestDirectoryGrowsAndShrinks(t *testing.T) {
+	fs := newTestDisk(t, Geometry{})
+	free := fs.countFreeBlocks()
+	perBlock := fs.Geometry.EntriesPerBlock()
+	files := 3*perBlock + 1 // Bersama "." dan "..", butuh 4 blok direktori
+
+	for i := 0; i < files; i++ {
+		if err := fs.CreateFile(fs.RootDirBlock, fmt.Sprintf("f%02d", i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	blocks, err := fs.chainBlocks(fs.RootDirBlock)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (files + 2 + perBlock - 1) / perBlock; len(blocks) != want {
+		t.Fatalf("root directory %d blok, seharusnya %d", len(blocks), want)
+	}
+	entries, err := fs.ListEntries(fs.RootDirBlock)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != files+2 {
+		t.Fatalf("%d entri terbaca, seharusnya %d", len(entries), files+2)
+	}
+
+	for i := 0; i < files; i++ {
+		if err := fs.DeleteEntry(fs.RootDirBlock, fmt.Sprintf("f%02d", i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if blocks, _ := fs.chainBlocks(fs.RootDirBlock); len(blocks) != 1 {
+		t.Fatalf("root directory masih %d blok setelah semua file dihapus", len(blocks))
+	}
+	if fs.countFreeBlocks() != free {
+		t.Fatalf("%d blok kosong setelah semua file dihapus, seharusnya %d", fs.countFreeBlocks(), free)
+	}
+}
+
+// Hanya blok yang benar-benar kosong yang dilepas: entri di blok-blok berikutnya tetap bisa dibaca.
+func TestDirectoryCompactKeepsLaterEntries(t *testing.T) {
+	fs := newTestDisk(t, Geometry{})
+	perBlock := fs.Geometry.EntriesPerBlock()
+	// Blok pertama: ".", "..", dan perBlock-2 file; blok kedua seluruhnya; blok ketiga satu file
+	var names []string
+	for i := 0; i < 2*perBlock-2+1; i++ {
+		names = append(names, fmt.Sprintf("f%02d", i))
+		if err := fs.CreateFile(fs.RootDirBlock, names[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, name := range names[perBlock-2 : 2*perBlock-2] {
+		if err := fs.DeleteEntry(fs.RootDirBlock, name); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if blocks, _ := fs.chainBlocks(fs.RootDirBlock); len(blocks) != 2 {
+		t.Fatalf("root directory %d blok, seharusnya 2 setelah blok tengah kosong", len(blocks))
+	}
+	for _, name := range append(names[:perBlock-2:perBlock-2], names[len(names)-1]) {
+		if _, err := fs.findEntry(fs.RootDirBlock, name); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
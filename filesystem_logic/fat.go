@@ -0,0 +1,121 @@
+// fat.go
+package filesystem_logic
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// Layout area FAT di disk (setelah superblock di blok 0):
+//
+//	blok 0                      : superblock
+//	blok 1 .. ReservedBlocks-1  : blok reserved lain (jika ada)
+//	blok ReservedBlocks ...     : FAT salinan pertama (Geometry.FATBlocksPerCopy() blok)
+//	blok berikutnya ...         : FAT salinan kedua / mirror (jika FATCopies = 2)
+//	blok setelah area FAT       : tabel inode dan bitmap blok kosong (jika ada), lalu root directory
+//
+// Setiap entri FAT disimpan sebagai int32 little endian, jadi satu blok memuat BlockSize/4 entri.
+const (
+	FAT_ENTRY_SIZE     = 4 // Ukuran satu BlockID di disk (int32)
+	DEFAULT_FAT_COPIES = 2 // Seperti FAT12/16: FAT utama + satu mirror
+	MAX_FAT_COPIES     = 2
+)
+
+// setFAT: Satu-satunya cara mengubah isi FAT. Nilai baru langsung di-flush ke semua salinan FAT di disk,
+// dan jika status kosong/terpakai blok tersebut berubah, FreeSpaceManager (lihat freespace.go) dan
+// jumlah blok kosong di superblock ikut diperbarui.
+func (fs *FileSystem) setFAT(block BlockID, value BlockID) {
+	oldValue := fs.FAT[block]
+	fs.FAT[block] = value
+	fs.writeFATEntry(block)
+
+	if (oldValue == FAT_FREE) != (value == FAT_FREE) {
+		fs.updateFreeSpace(block, value == FAT_FREE)
+		if value == FAT_FREE {
+			fs.superblock.FreeBlocks++
+		} else {
+			fs.superblock.FreeBlocks--
+		}
+		if err := fs.writeSuperblock(&fs.superblock); err != nil {
+			fmt.Printf("Warning: Gagal memperbarui superblock setelah perubahan FAT: %v\n", err)
+		}
+	}
+}
+
+// writeFATEntry: Menulis fs.FAT[block] ke posisinya di setiap salinan FAT di disk.
+func (fs *FileSystem) writeFATEntry(block BlockID) {
+	entriesPerBlock := fs.Geometry.BlockSize / FAT_ENTRY_SIZE
+	blockInCopy := int(block) / entriesPerBlock
+	offset := (int(block) % entriesPerBlock) * FAT_ENTRY_SIZE
+
+	for copyIndex := 0; copyIndex < int(fs.superblock.FATCopies); copyIndex++ {
+		diskBlock := int(fs.superblock.FATStart) + copyIndex*int(fs.superblock.FATBlocks) + blockInCopy
+		binary.LittleEndian.PutUint32(fs.Disk[diskBlock][offset:], uint32(fs.FAT[block]))
+	}
+}
+
+// flushFAT: Menulis seluruh FAT ke semua salinannya di disk (dipakai saat format).
+func (fs *FileSystem) flushFAT() {
+	for i := range fs.FAT {
+		fs.writeFATEntry(BlockID(i))
+	}
+}
+
+// readFATCopy: Membaca satu salinan FAT dari disk dan memvalidasi setiap nilainya.
+func readFATCopy(disk [][]byte, sb Superblock, copyIndex int) ([]BlockID, error) {
+	entriesPerBlock := int(sb.BlockSize) / FAT_ENTRY_SIZE
+	fat := make([]BlockID, sb.TotalBlocks)
+	firstBlock := int(sb.FATStart) + copyIndex*int(sb.FATBlocks)
+
+	for i := range fat {
+		diskBlock := firstBlock + i/entriesPerBlock
+		offset := (i % entriesPerBlock) * FAT_ENTRY_SIZE
+		next := BlockID(int32(binary.LittleEndian.Uint32(disk[diskBlock][offset:])))
+		if next != FAT_FREE && next != FAT_EOF && next != FAT_RESERVED && (next < 0 || next >= BlockID(sb.TotalBlocks)) {
+			return nil, fmt.Errorf("FAT salinan %d rusak: FAT[%d] = %d", copyIndex+1, i, next)
+		}
+		fat[i] = next
+	}
+	return fat, nil
+}
+
+// loadFATFromDisk: Memuat FAT dari blok-blok FAT di disk saat mount.
+// Jika salinan utama rusak, mirror dipakai (seperti pada FAT sungguhan).
+func loadFATFromDisk(disk [][]byte, sb Superblock) ([]BlockID, error) {
+	var firstErr error
+	for copyIndex := 0; copyIndex < int(sb.FATCopies); copyIndex++ {
+		fat, err := readFATCopy(disk, sb, copyIndex)
+		if err == nil {
+			if copyIndex > 0 {
+				fmt.Printf("Warning: FAT utama rusak (%v), memakai FAT salinan %d.\n", firstErr, copyIndex+1)
+			}
+			return fat, nil
+		}
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+	if firstErr == nil {
+		firstErr = errors.New("tidak ada salinan FAT di disk")
+	}
+	return nil, firstErr
+}
+
+// chainBlocks: Daftar nomor blok dalam rantai FAT mulai dari startBlock, berurutan.
+// Rantai kosong (startBlock FAT_EOF/FAT_FREE) menghasilkan slice kosong.
+func (fs *FileSystem) chainBlocks(startBlock BlockID) ([]BlockID, error) {
+	var blocks []BlockID
+	currentBlock := startBlock
+	for currentBlock != FAT_EOF && currentBlock != FAT_FREE {
+		if currentBlock < 0 || currentBlock >= BlockID(fs.Geometry.TotalBlocks) {
+			return blocks, fmt.Errorf("nomor blok tidak valid (%d) dalam rantai", currentBlock)
+		}
+		if len(blocks) >= fs.Geometry.TotalBlocks { // Pengaman jika FAT membentuk siklus
+			return blocks, fmt.Errorf("rantai mulai blok %d membentuk siklus", startBlock)
+		}
+		blocks = append(blocks, currentBlock)
+		currentBlock = fs.FAT[currentBlock]
+	}
+	return blocks, nil
+}
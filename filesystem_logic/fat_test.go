@@ -0,0 +1,73 @@
+package filesystem_logic
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// checkFATCopies: Setiap salinan FAT di disk harus sama dengan FAT di memori.
+func checkFATCopies(t *testing.T, fs *FileSystem) {
+	t.Helper()
+	for i := 0; i < int(fs.superblock.FATCopies); i++ {
+		fat, err := readFATCopy(fs.Disk, fs.superblock, i)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if fmt.Sprint(fat) != fmt.Sprint(fs.FAT) {
+			t.Fatalf("FAT salinan %d di disk berbeda dengan FAT di memori", i+1)
+		}
+	}
+}
+
+// Setiap perubahan alokasi langsung ditulis ke semua salinan FAT, dan root directory diletakkan tepat
+// setelah area FAT.
+func TestFATStoredOnDisk(t *testing.T) {
+	for copies := 1; copies <= MAX_FAT_COPIES; copies++ {
+		t.Run(fmt.Sprintf("copies=%d", copies), func(t *testing.T) {
+			fs := newTestDisk(t, Geometry{FATCopies: copies})
+			if want := fs.Geometry.RootBlock(); fs.RootDirBlock != want {
+				t.Fatalf("root directory di blok %d, seharusnya %d", fs.RootDirBlock, want)
+			}
+			for b := SUPERBLOCK_BLOCK; b < fs.RootDirBlock; b++ {
+				if fs.FAT[b] != FAT_RESERVED {
+					t.Fatalf("blok sistem %d tidak bertanda reserved: %d", b, fs.FAT[b])
+				}
+			}
+			checkFATCopies(t, fs)
+
+			writeTestFile(t, fs, "a.txt", make([]byte, 3*fs.Geometry.BlockSize))
+			checkFATCopies(t, fs)
+			if err := fs.DeleteEntry(fs.RootDirBlock, "a.txt"); err != nil {
+				t.Fatal(err)
+			}
+			checkFATCopies(t, fs)
+		})
+	}
+}
+
+// Jika FAT utama di image rusak, disk tetap bisa dimuat dari mirror dan FAT utama diperbaiki.
+func TestLoadImageFallsBackToFATMirror(t *testing.T) {
+	image := filepath.Join(t.TempDir(), "disk.img")
+	fs := newTestDisk(t, Geometry{})
+	writeTestFile(t, fs, "data.txt", []byte("isi file"))
+	if err := fs.SaveImage(image); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(image)
+	if err != nil {
+		t.Fatal(err)
+	}
+	copy(data[int(fs.Geometry.FATStart())*fs.Geometry.BlockSize:], []byte{0xff, 0xff, 0xff, 0x7f})
+	if err := os.WriteFile(image, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := NewFileSystem(FileSystemOptions{ImagePath: image})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkTestFile(t, loaded, "data.txt", []byte("isi file"))
+	checkFATCopies(t, loaded)
+}
@@ -0,0 +1,389 @@
+// file.go
+package filesystem_logic
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// Flag untuk FileSystem.Open, nilainya sama dengan flag di package os agar mudah dikenali.
+// Tepat satu dari O_RDONLY, O_WRONLY, O_RDWR harus dipakai, ditambah flag lain dengan operator |.
+const (
+	O_RDONLY = os.O_RDONLY // Hanya baca
+	O_WRONLY = os.O_WRONLY // Hanya tulis
+	O_RDWR   = os.O_RDWR   // Baca dan tulis
+	O_APPEND = os.O_APPEND // Setiap Write selalu ditulis di akhir file
+	O_CREATE = os.O_CREATE // Buat file jika belum ada
+	O_EXCL   = os.O_EXCL   // Bersama O_CREATE: gagal jika file sudah ada
+	O_TRUNC  = os.O_TRUNC  // Kosongkan file saat dibuka (butuh akses tulis)
+
+	accessModeMask = O_RDONLY | O_WRONLY | O_RDWR
+)
+
+var (
+	ErrExist      = errors.New("sudah ada")
+	ErrClosed     = errors.New("file sudah ditutup")
+	ErrPermission = errors.New("izin ditolak") // Mode akses File atau izin entri (lihat PermissionError)
+)
+
+// File: Satu entri di tabel file terbuka sistem (open file description), dibuat oleh FileSystem.Open
+// atau Process.Open. Menyimpan offset dan mode akses; beberapa file descriptor bisa menunjuk ke File
+// yang sama (lihat Process.Dup), sehingga mereka berbagi offset seperti di Unix.
+// Mengimplementasikan io.Reader, io.Writer, io.Seeker, io.ReaderAt, io.WriterAt, dan io.Closer.
+// Berbeda dengan WriteToFile yang selalu menulis ulang seluruh file, File hanya menyentuh
+// blok-blok yang terkena operasi; blok baru dialokasikan hanya jika file bertambah panjang.
+type File struct {
+	fs     *FileSystem
+	id     int    // Nomor entri di tabel file terbuka
+	node   *vnode // File di disk yang dibuka (dipakai bersama oleh semua File untuk file yang sama)
+	path   string
+	flags  int
+	offset int64 // Posisi baca/tulis untuk Read, Write, dan Seek
+	refs   int   // Jumlah referensi (File dari Open + file descriptor hasil Dup)
+	closed bool
+}
+
+var (
+	_ io.ReadWriteSeeker = (*File)(nil)
+	_ io.ReaderAt        = (*File)(nil)
+	_ io.WriterAt        = (*File)(nil)
+	_ io.Closer          = (*File)(nil)
+)
+
+// Open: Membuka file di path dengan flag seperti os.OpenFile (O_RDONLY, O_RDWR, O_CREATE, O_TRUNC, O_APPEND, ...).
+// File yang dikembalikan tercatat di tabel file terbuka sampai Close. Direktori tidak bisa dibuka sebagai File.
+// Symlink diikuti sampai ke file tujuannya.
+func (fs *FileSystem) Open(path string, flags int) (*File, error) {
+	parentBlock, name, err := fs.resolveTarget(path)
+	if err != nil {
+		return nil, err
+	}
+
+	slot, entry, err := fs.findEntrySlot(parentBlock, name)
+	switch {
+	case errors.Is(err, ErrNotExist) && flags&O_CREATE != 0:
+		if err := fs.CreateFile(parentBlock, name); err != nil {
+			return nil, err
+		}
+		slot, entry, err = fs.findEntrySlot(parentBlock, name)
+		if err != nil {
+			return nil, err
+		}
+	case err != nil:
+		return nil, err
+	case flags&O_CREATE != 0 && flags&O_EXCL != 0:
+		return nil, fmt.Errorf("'%s' %w", path, ErrExist)
+	}
+	if entry.Type == TYPE_DIRECTORY {
+		return nil, fmt.Errorf("'%s' %w", path, ErrIsDir)
+	}
+	if flags&O_TRUNC != 0 && flags&accessModeMask == O_RDONLY {
+		return nil, fmt.Errorf("O_TRUNC pada '%s': %w", path, ErrPermission)
+	}
+
+	// Izin entri diperiksa sekali saat dibuka, seperti open(2); File yang sudah terbuka tidak diperiksa lagi
+	var need Access
+	if flags&accessModeMask != O_WRONLY {
+		need |= ACCESS_READ
+	}
+	if flags&accessModeMask != O_RDONLY {
+		need |= ACCESS_WRITE
+	}
+	if err := fs.checkAccess("open", path, entry, need); err != nil {
+		return nil, err
+	}
+
+	f := fs.FileTable.open(fs, slot, path, flags)
+	if flags&O_TRUNC != 0 {
+		if err := f.truncate(0); err != nil {
+			fs.FileTable.release(f)
+			return nil, err
+		}
+	}
+	return f, nil
+}
+
+// Truncate: Mengubah ukuran file di path menjadi size byte tanpa menulis ulang isinya (lihat File.Truncate).
+func (fs *FileSystem) Truncate(path string, size int64) error {
+	f, err := fs.Open(path, O_WRONLY)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return f.Truncate(size)
+}
+
+// Append: Menambahkan data di akhir file di path. Slack di blok terakhir diisi lebih dulu, baru
+// blok baru ditambahkan jika masih kurang; blok lain tidak disentuh.
+func (fs *FileSystem) Append(path string, data []byte) error {
+	f, err := fs.Open(path, O_WRONLY|O_APPEND)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = f.Write(data)
+	return err
+}
+
+// Truncate: Mengubah ukuran file menjadi size byte. Jika lebih kecil, blok di belakang blok terakhir
+// yang masih dipakai dibebaskan (lewat Allocator disk) dan sisa blok terakhir dinolkan, sehingga data lama
+// tidak muncul lagi jika file diperpanjang. Jika lebih besar, file diperpanjang dengan byte nol.
+// Offset file tidak berubah.
+func (f *File) Truncate(size int64) error {
+	if err := f.checkOp("truncate", true); err != nil {
+		return err
+	}
+	if size < 0 {
+		return fmt.Errorf("truncate '%s': ukuran negatif %d", f.path, size)
+	}
+	return f.truncate(size)
+}
+
+func (f *File) truncate(size int64) error {
+	if err := f.fs.loadNode(f.node); err != nil {
+		return err
+	}
+	blockSize := int64(f.fs.Geometry.BlockSize)
+	blocksNeeded := int((size + blockSize - 1) / blockSize)
+	blocks, err := f.fs.allocator().Resize(&f.node.entry, blocksNeeded)
+	if err != nil {
+		return fmt.Errorf("truncate '%s': %w", f.path, err)
+	}
+
+	// Nolkan sisa blok terakhir mulai dari ukuran baru (atau ukuran lama jika file diperpanjang)
+	zeroFrom := size
+	if f.node.entry.Size < zeroFrom {
+		zeroFrom = f.node.entry.Size
+	}
+	if index := zeroFrom / blockSize; index < int64(len(blocks)) {
+		block := f.fs.Disk[blocks[index]]
+		for i := zeroFrom % blockSize; i < blockSize; i++ {
+			block[i] = 0
+		}
+	}
+
+	f.node.entry.Size = size
+	f.node.entry.ModTime = time.Now().UnixNano()
+	f.node.entry.ChangeTime = f.node.entry.ModTime
+	return f.fs.storeNode(f.node)
+}
+
+func (f *File) readable() bool { return f.flags&accessModeMask != O_WRONLY }
+func (f *File) writable() bool { return f.flags&accessModeMask != O_RDONLY }
+
+// Name: Path yang dipakai saat membuka file.
+func (f *File) Name() string { return f.path }
+
+// Size: Ukuran file saat ini dalam byte.
+func (f *File) Size() int64 {
+	f.fs.loadNode(f.node)
+	return f.node.entry.Size
+}
+
+// checkOp: Validasi umum sebelum operasi baca/tulis.
+func (f *File) checkOp(op string, needWrite bool) error {
+	if f.closed {
+		return fmt.Errorf("%s '%s': %w", op, f.path, ErrClosed)
+	}
+	if needWrite && !f.writable() || !needWrite && !f.readable() {
+		return fmt.Errorf("%s '%s': mode akses %s tidak mengizinkan operasi ini: %w", op, f.path, AccessModeString(f.flags), ErrPermission)
+	}
+	return nil
+}
+
+// ReadAt: Membaca len(p) byte mulai dari posisi off tanpa mengubah offset file.
+// Hanya blok yang memuat rentang [off, off+len(p)) yang dibaca dari disk.
+func (f *File) ReadAt(p []byte, off int64) (int, error) {
+	if err := f.checkOp("read", false); err != nil {
+		return 0, err
+	}
+	if err := f.fs.loadNode(f.node); err != nil {
+		return 0, err
+	}
+	if off < 0 {
+		return 0, fmt.Errorf("read '%s': offset negatif %d", f.path, off)
+	}
+	if off >= f.node.entry.Size {
+		return 0, io.EOF
+	}
+
+	blocks, err := f.fs.allocator().Blocks(f.node.entry)
+	if err != nil {
+		return 0, err
+	}
+	blockSize := int64(f.fs.Geometry.BlockSize)
+	end := off + int64(len(p))
+	if end > f.node.entry.Size {
+		end = f.node.entry.Size
+	}
+
+	n := 0
+	for pos := off; pos < end; {
+		index := pos / blockSize
+		if index >= int64(len(blocks)) {
+			return n, fmt.Errorf("blok file '%s' lebih sedikit dari ukurannya (%d byte)", f.path, f.node.entry.Size)
+		}
+		inBlock := pos % blockSize
+		chunk := blockSize - inBlock
+		if chunk > end-pos {
+			chunk = end - pos
+		}
+		copy(p[n:], f.fs.Disk[blocks[index]][inBlock:inBlock+chunk])
+		n += int(chunk)
+		pos += chunk
+	}
+	if now := time.Now().UnixNano(); n > 0 && f.fs.atimeDue(f.node.entry, now) {
+		f.node.entry.AccessTime = now
+		f.fs.storeNode(f.node)
+	}
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+// WriteAt: Menulis p mulai dari posisi off tanpa mengubah offset file.
+// Blok yang sudah ada ditimpa di tempat; blok baru hanya ditambahkan jika off+len(p) melewati blok terakhir.
+// Jika off melewati akhir file, celahnya terbaca sebagai byte nol.
+func (f *File) WriteAt(p []byte, off int64) (int, error) {
+	if err := f.checkOp("write", true); err != nil {
+		return 0, err
+	}
+	if off < 0 {
+		return 0, fmt.Errorf("write '%s': offset negatif %d", f.path, off)
+	}
+	if f.flags&O_APPEND != 0 {
+		return 0, fmt.Errorf("write '%s': WriteAt tidak bisa dipakai pada file yang dibuka dengan O_APPEND", f.path)
+	}
+	return f.writeAt(p, off)
+}
+
+func (f *File) writeAt(p []byte, off int64) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	if err := f.fs.loadNode(f.node); err != nil {
+		return 0, err
+	}
+	blocks, err := f.fs.allocator().Blocks(f.node.entry)
+	if err != nil {
+		return 0, err
+	}
+	blockSize := int64(f.fs.Geometry.BlockSize)
+	end := off + int64(len(p))
+
+	// 1. Byte lama di antara akhir file dan off (slack blok terakhir) dinolkan agar celah terbaca nol
+	oldSize := f.node.entry.Size
+	for pos := oldSize; pos < off && pos/blockSize < int64(len(blocks)); pos++ {
+		f.fs.Disk[blocks[pos/blockSize]][pos%blockSize] = 0
+	}
+
+	// 2. Tambah blok di akhir file jika perlu (blok baru sudah berisi nol)
+	blocksNeeded := int((end + blockSize - 1) / blockSize)
+	if blocksNeeded > len(blocks) {
+		blocks, err = f.fs.allocator().Resize(&f.node.entry, blocksNeeded)
+		if err != nil {
+			return 0, fmt.Errorf("write '%s': %w", f.path, err)
+		}
+	}
+
+	// 3. Salin data hanya ke blok-blok yang terkena
+	n := 0
+	for pos := off; pos < end; {
+		index := pos / blockSize
+		inBlock := pos % blockSize
+		chunk := blockSize - inBlock
+		if chunk > end-pos {
+			chunk = end - pos
+		}
+		copy(f.fs.Disk[blocks[index]][inBlock:inBlock+chunk], p[n:])
+		n += int(chunk)
+		pos += chunk
+	}
+
+	// 4. Perbarui ukuran dan waktu modifikasi di direktori induk
+	if end > f.node.entry.Size {
+		f.node.entry.Size = end
+	}
+	f.node.entry.ModTime = time.Now().UnixNano()
+	f.node.entry.ChangeTime = f.node.entry.ModTime
+	if err := f.fs.storeNode(f.node); err != nil {
+		return n, fmt.Errorf("write '%s': gagal memperbarui entri: %w", f.path, err)
+	}
+	return n, nil
+}
+
+// Read: Membaca dari offset saat ini lalu memajukan offset.
+func (f *File) Read(p []byte) (int, error) {
+	n, err := f.ReadAt(p, f.offset)
+	f.offset += int64(n)
+	return n, err
+}
+
+// Write: Menulis di offset saat ini lalu memajukan offset.
+// Untuk file yang dibuka dengan O_APPEND, data selalu ditulis di akhir file.
+func (f *File) Write(p []byte) (int, error) {
+	if err := f.checkOp("write", true); err != nil {
+		return 0, err
+	}
+	if f.flags&O_APPEND != 0 {
+		if err := f.fs.loadNode(f.node); err != nil {
+			return 0, err
+		}
+		f.offset = f.node.entry.Size
+	}
+	n, err := f.writeAt(p, f.offset)
+	f.offset += int64(n)
+	return n, err
+}
+
+// Seek: Mengubah offset file (io.SeekStart, io.SeekCurrent, io.SeekEnd).
+// Offset boleh melewati akhir file; Write berikutnya akan mengisi celahnya dengan nol.
+func (f *File) Seek(offset int64, whence int) (int64, error) {
+	if f.closed {
+		return 0, fmt.Errorf("seek '%s': %w", f.path, ErrClosed)
+	}
+	var base int64
+	switch whence {
+	case io.SeekStart:
+		base = 0
+	case io.SeekCurrent:
+		base = f.offset
+	case io.SeekEnd:
+		base = f.Size()
+	default:
+		return 0, fmt.Errorf("seek '%s': whence %d tidak valid", f.path, whence)
+	}
+	if base+offset < 0 {
+		return 0, fmt.Errorf("seek '%s': posisi negatif", f.path)
+	}
+	f.offset = base + offset
+	return f.offset, nil
+}
+
+// Close: Melepas satu referensi ke File. Entri di tabel file terbuka baru dihapus setelah referensi
+// terakhir dilepas; jika file sudah dihapus (unlink) selagi terbuka, bloknya dibebaskan saat itu.
+func (f *File) Close() error {
+	if f.closed {
+		return fmt.Errorf("close '%s': %w", f.path, ErrClosed)
+	}
+	return f.fs.FileTable.release(f)
+}
+
+// AccessModeString: Mode akses flag dalam bentuk singkat untuk ditampilkan ("r", "w", "rw", ditambah "+a" untuk O_APPEND).
+func AccessModeString(flags int) string {
+	mode := "r"
+	switch flags & accessModeMask {
+	case O_WRONLY:
+		mode = "w"
+	case O_RDWR:
+		mode = "rw"
+	}
+	if flags&O_APPEND != 0 {
+		mode += "+a"
+	}
+	return mode
+}
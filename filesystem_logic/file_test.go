@@ -0,0 +1,206 @@
+package filesystem_logic
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"slices"
+	"testing"
+)
+
+// Tulis, Seek, dan baca lewat File: menimpa di tengah file tidak mengubah rantai blok, dan menulis setelah
+// akhir file mengisi celahnya dengan nol.
+func TestFileReadWriteSeek(t *testing.T) {
+	fs := newTestDisk(t, Geometry{})
+	blockSize := fs.Geometry.BlockSize
+	f, err := fs.Open("/data.bin", O_RDWR|O_CREATE)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := bytes.Repeat([]byte("abcdefgh"), 3*blockSize/8)
+	if n, err := f.Write(want); err != nil || n != len(want) {
+		t.Fatalf("Write = %d, %v", n, err)
+	}
+	entry, _ := fs.Lookup("/data.bin")
+	before, _ := fs.chainBlocks(entry.StartBlock)
+
+	// Timpa beberapa byte yang melewati batas blok pertama dan kedua
+	off := int64(blockSize - 2)
+	if _, err := f.Seek(off, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.Write([]byte("XXXX")); err != nil {
+		t.Fatal(err)
+	}
+	copy(want[off:], "XXXX")
+	entry, _ = fs.Lookup("/data.bin")
+	if got, _ := fs.chainBlocks(entry.StartBlock); !slices.Equal(got, before) {
+		t.Fatalf("rantai berubah saat menimpa di tengah file: %v -> %v", before, got)
+	}
+
+	// Tulis 10 byte setelah akhir file: celahnya terbaca nol
+	if _, err := f.Seek(10, io.SeekEnd); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.Write([]byte("end")); err != nil {
+		t.Fatal(err)
+	}
+	want = append(append(want, make([]byte, 10)...), "end"...)
+	if f.Size() != int64(len(want)) {
+		t.Fatalf("Size = %d, seharusnya %d", f.Size(), len(want))
+	}
+
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	got, err := io.ReadAll(f)
+	if err != nil || !bytes.Equal(got, want) {
+		t.Fatalf("ReadAll: %d byte, %v; seharusnya %d byte", len(got), err, len(want))
+	}
+	part := make([]byte, 6)
+	if n, err := f.ReadAt(part, off-1); err != nil || !bytes.Equal(part[:n], want[off-1:off+5]) {
+		t.Fatalf("ReadAt = %q, %v; seharusnya %q", part[:n], err, want[off-1:off+5])
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := fs.ReadFile("/data.bin"); !bytes.Equal(data, want) {
+		t.Fatal("isi file di disk berbeda dengan yang ditulis lewat File")
+	}
+}
+
+// Flag Open mengatur mode akses, pembuatan, pengosongan, dan penulisan di akhir file.
+func TestFileOpenFlags(t *testing.T) {
+	fs := newTestDisk(t, Geometry{})
+	if err := fs.WriteFile("/f", []byte("awal")); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := fs.Open("/f", O_RDWR|O_CREATE|O_EXCL); !errors.Is(err, ErrExist) {
+		t.Fatalf("O_EXCL pada file yang ada: %v", err)
+	}
+	if _, err := fs.Open("/nope", O_RDONLY); !errors.Is(err, ErrNotExist) {
+		t.Fatalf("membuka file yang tidak ada: %v", err)
+	}
+	if _, err := fs.Open("/f", O_RDONLY|O_TRUNC); !errors.Is(err, ErrPermission) {
+		t.Fatalf("O_TRUNC tanpa akses tulis: %v", err)
+	}
+	fs.Mkdir("/d")
+	if _, err := fs.Open("/d", O_RDONLY); !errors.Is(err, ErrIsDir) {
+		t.Fatalf("membuka direktori: %v", err)
+	}
+
+	r, err := fs.Open("/f", O_RDONLY)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Write([]byte("x")); !errors.Is(err, ErrPermission) {
+		t.Fatalf("Write pada O_RDONLY: %v", err)
+	}
+	w, err := fs.Open("/f", O_WRONLY|O_APPEND)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Read(make([]byte, 1)); !errors.Is(err, ErrPermission) {
+		t.Fatalf("Read pada O_WRONLY: %v", err)
+	}
+	w.Seek(0, io.SeekStart)
+	if _, err := w.Write([]byte("+akhir")); err != nil {
+		t.Fatal(err)
+	}
+	w.Close()
+	if _, err := w.Write([]byte("x")); !errors.Is(err, ErrClosed) {
+		t.Fatalf("Write setelah Close: %v", err)
+	}
+	if data, _ := fs.ReadFile("/f"); string(data) != "awal+akhir" {
+		t.Fatalf("isi setelah O_APPEND: %q", data)
+	}
+
+	tr, err := fs.Open("/f", O_RDWR|O_TRUNC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tr.Size() != 0 {
+		t.Fatalf("Size setelah O_TRUNC = %d", tr.Size())
+	}
+	tr.Close()
+}
+
+// Jika disk penuh saat file diperpanjang, blok yang terlanjur ditambahkan dibebaskan lagi.
+func TestFileWriteDiskFull(t *testing.T) {
+	fs := newTestDisk(t, Geometry{TotalBlocks: 32})
+	blockSize := fs.Geometry.BlockSize
+	f, err := fs.Open("/f", O_RDWR|O_CREATE)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.Write(make([]byte, blockSize)); err != nil {
+		t.Fatal(err)
+	}
+	free := fs.countFreeBlocks()
+	if _, err := f.Write(make([]byte, int(free+1)*blockSize)); err == nil {
+		t.Fatal("Write yang melebihi ruang kosong seharusnya gagal")
+	}
+	if fs.countFreeBlocks() != free || f.Size() != int64(blockSize) {
+		t.Fatalf("%d blok kosong dan ukuran %d setelah gagal; seharusnya %d dan %d", fs.countFreeBlocks(), f.Size(), free, blockSize)
+	}
+}
+
+// Truncate dan Append hanya mengubah ekor rantai: blok di depannya tetap sama, data lama di belakang
+// ukuran baru tidak muncul lagi saat file diperpanjang, dan blok yang dilepas kembali kosong.
+func TestTruncateAndAppend(t *testing.T) {
+	fs := newTestDisk(t, Geometry{})
+	blockSize := fs.Geometry.BlockSize
+	free := fs.countFreeBlocks()
+	data := bytes.Repeat([]byte{0xaa}, 4*blockSize)
+	if err := fs.WriteFile("/f", data); err != nil {
+		t.Fatal(err)
+	}
+	entry, _ := fs.Lookup("/f")
+	before, _ := fs.chainBlocks(entry.StartBlock)
+
+	size := int64(blockSize + 10)
+	if err := fs.Truncate("/f", size); err != nil {
+		t.Fatal(err)
+	}
+	entry, _ = fs.Lookup("/f")
+	after, _ := fs.chainBlocks(entry.StartBlock)
+	if !slices.Equal(after, before[:2]) {
+		t.Fatalf("rantai setelah Truncate %v, seharusnya %v", after, before[:2])
+	}
+	if fs.countFreeBlocks() != free-2 {
+		t.Fatalf("%d blok kosong setelah Truncate, seharusnya %d", fs.countFreeBlocks(), free-2)
+	}
+
+	// Diperpanjang lagi dengan Truncate: sisanya nol, bukan 0xaa lama
+	if err := fs.Truncate("/f", 3*int64(blockSize)); err != nil {
+		t.Fatal(err)
+	}
+	want := append(data[:size:size], make([]byte, 3*blockSize-int(size))...)
+	if got, _ := fs.ReadFile("/f"); !bytes.Equal(got, want) {
+		t.Fatal("isi setelah Truncate memperpanjang file bukan nol")
+	}
+
+	if err := fs.Append("/f", []byte("ekor")); err != nil {
+		t.Fatal(err)
+	}
+	want = append(want, "ekor"...)
+	entry, _ = fs.Lookup("/f")
+	got, _ := fs.chainBlocks(entry.StartBlock)
+	if !slices.Equal(got[:2], before[:2]) || len(got) != 4 {
+		t.Fatalf("rantai setelah Append %v", got)
+	}
+	if data, _ := fs.ReadFile("/f"); !bytes.Equal(data, want) {
+		t.Fatal("isi setelah Append berbeda")
+	}
+
+	if err := fs.Truncate("/f", -1); err == nil {
+		t.Fatal("Truncate dengan ukuran negatif seharusnya ditolak")
+	}
+	if err := fs.Truncate("/f", 0); err != nil {
+		t.Fatal(err)
+	}
+	if fs.countFreeBlocks() != free {
+		t.Fatalf("%d blok kosong setelah Truncate ke 0, seharusnya %d", fs.countFreeBlocks(), free)
+	}
+}
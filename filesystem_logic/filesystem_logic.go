@@ -6,17 +6,18 @@ import (
 	"encoding/binary" // Juga untuk serialisasi/deserialisasi
 	"errors"
 	"fmt"
+	"os"
+	"strings"
 	"time"
 )
 
 // Konstanta yang sudah kita bahas (bisa disesuaikan nanti)
 const (
-	BLOCK_SIZE       = 256         // Bytes per block (kecil untuk testing)
-	TOTAL_BLOCKS     = 256         // Total blocks on the disk (total disk size = 128*256 = 32KB)
+	// Ukuran blok dan jumlah blok tidak lagi konstanta: lihat Geometry di geometry.go (disimpan per disk di FileSystem.Geometry)
 	FAT_FREE         = BlockID(-2) // Tandai blok kosong di FAT dengan -2
 	FAT_EOF          = BlockID(-1) // Tandai akhir dari rantai blok file di FAT
-	ROOT_DIR_BLOCK   = BlockID(1)  // Blok pertama untuk root directory (konvensi, blok 0 bisa untuk info disk)
-	MAX_FILENAME_LEN = 28          // Maksimum panjang nama file (agar DirectoryEntry punya ukuran tetap)
+	FAT_RESERVED     = BlockID(-3) // Tandai blok sistem (superblock dan area FAT) yang tidak boleh dialokasikan
+	MAX_FILENAME_LEN = 28          // Lebar field nama di DirectoryEntry (agar ukurannya tetap); batas per disk ada di Geometry.MaxFilenameLen
 	// Ukuran DirectoryEntry akan: MAX_FILENAME_LEN + 1 (Type) + 4 (StartBlock) + 8 (Size) + 8 (ModTime detik) + 4 (ModTime nanosec)
 	// Perkiraan: 28 + 1 + 4 + 8 + 8 + 4 = 53 bytes. Kita bulatkan agar mudah, misal 64 bytes per entry.
 	// Ini PENTING untuk serialisasi! Mari kita buat ukuran pasti.
@@ -24,20 +25,38 @@ const (
 	// Untuk Type, kita pakai int8 agar ukurannya pasti 1 byte saat serialisasi.
 	// time.Time akan kita serialize sebagai UnixNano (int64) agar ukurannya pasti 8 bytes.
 	// Jadi: Name(28) + Type(1) + StartBlock(4) + Size(8) + ModTimeUnixNano(8) = 49 bytes.
-	// Kita akan gunakan ukuran ini.
-	DIRECTORY_ENTRY_SIZE = 49
+	// Versi format 4 menambahkan izin gaya Unix: Mode(2) + UID(2) + GID(2) = 55 bytes.
+	// Versi format 5 menambahkan AccessTime(8) + ChangeTime(8) + BirthTime(8) = 79 bytes.
+	// Versi format 7 menambahkan Nlink(2) untuk hard link = 81 bytes.
+	DIRECTORY_ENTRY_SIZE = 81
 )
 
 type BlockID int32 // Tipe untuk nomor blok
 
-var Disk [][]byte // Representasi disk kita: slice dari blok, setiap blok adalah slice dari byte
-var FAT []BlockID // File Allocation Table: slice di mana indeks adalah nomor blok
+// FileSystem: Satu disk simulasi beserta seluruh state-nya. Tidak ada lagi state global,
+// sehingga beberapa disk bisa dibuka bersamaan (misalnya dua disk berdampingan di GUI).
+type FileSystem struct {
+	CurrentDirectoryBlock BlockID        // Direktori kerja saat ini
+	Disk                  [][]byte       // Representasi disk kita: slice dari blok, setiap blok adalah slice dari byte
+	FAT                   []BlockID      // File Allocation Table: indeks adalah nomor blok (cache dari FAT yang tersimpan di disk)
+	Geometry              Geometry       // Geometri disk yang sedang di-mount (diisi oleh FormatDisk atau LoadImage)
+	RootDirBlock          BlockID        // Blok pertama root directory, dibaca dari superblock (letaknya setelah area FAT)
+	FileTable             *FileTable     // Tabel file terbuka dan proses simulasi (lihat filetable.go)
+	Users                 []User         // Pengguna simulasi (lihat permission.go)
+	Groups                []Group        // Grup simulasi
+	CurrentUser           User           // Pengguna yang menjalankan operasi; izin diperiksa terhadapnya (nilai nol = root)
+	Mount                 MountOptions   // Opsi mount seperti noatime/relatime (lihat times.go)
+	superblock            Superblock     // Salinan superblock di memori, selalu ditulis ulang ke blok 0 jika berubah (lihat setFAT)
+	freeStats             FreeSpaceStats // Biaya manajemen ruang kosong sejak format/mount (lihat freespace.go)
+	freeReads             int64          // Blok yang dibaca FreeSpaceManager sejak format/mount
+}
 
 type FileType int8 // int8 agar ukuran pasti 1 byte
 
 const (
 	TYPE_FILE      FileType = 0
 	TYPE_DIRECTORY FileType = 1
+	TYPE_SYMLINK   FileType = 2 // Isi blok datanya adalah path tujuan (lihat link.go)
 )
 
 type DirectoryEntry struct {
@@ -46,6 +65,15 @@ type DirectoryEntry struct {
 	StartBlock BlockID                // Blok pertama data di FAT (jika file) atau blok pertama isi direktori (jika direktori)
 	Size       int64                  // Ukuran file dalam bytes (untuk direktori, bisa ukuran total entri di dalamnya)
 	ModTime    int64                  // Waktu modifikasi terakhir (disimpan sebagai Unix nanoseconds)
+	Mode       FileMode               // Bit izin rwx pemilik/grup/lainnya (lihat permission.go)
+	UID        uint16                 // Pemilik entri
+	GID        uint16                 // Grup entri
+	AccessTime int64                  // Waktu akses terakhir (atime), lihat times.go
+	ChangeTime int64                  // Waktu perubahan isi atau metadata terakhir (ctime)
+	BirthTime  int64                  // Waktu entri dibuat (btime)
+	Nlink      uint16                 // Jumlah hard link yang berbagi rantai blok ini (lihat link.go); direktori selalu 1
+	LongName   string                 // Nama panjang dari slot LFN (lihat lfn.go); tidak ikut diserialisasi, Name berisi aliasnya
+	Inode      uint32                 // Nomor inode pada disk dengan tabel inode (lihat inode.go); tidak ikut diserialisasi Serialize
 }
 
 // Fungsi untuk mengkonversi struct DirectoryEntry menjadi slice byte
@@ -81,6 +109,25 @@ func (de *DirectoryEntry) Serialize() ([]byte, error) {
 	if err != nil {
 		return nil, fmt.Errorf("serialize modtime: %w", err)
 	}
+
+	// 6. Tulis Mode, UID, dan GID
+	for _, field := range []interface{}{de.Mode, de.UID, de.GID} {
+		if err := binary.Write(buf, binary.LittleEndian, field); err != nil {
+			return nil, fmt.Errorf("serialize mode/owner: %w", err)
+		}
+	}
+
+	// 7. Tulis AccessTime, ChangeTime, dan BirthTime
+	for _, field := range []interface{}{de.AccessTime, de.ChangeTime, de.BirthTime} {
+		if err := binary.Write(buf, binary.LittleEndian, field); err != nil {
+			return nil, fmt.Errorf("serialize times: %w", err)
+		}
+	}
+
+	// 8. Tulis Nlink
+	if err := binary.Write(buf, binary.LittleEndian, de.Nlink); err != nil {
+		return nil, fmt.Errorf("serialize nlink: %w", err)
+	}
 	// Pastikan panjangnya sesuai DIRECTORY_ENTRY_SIZE
 	serializedData := buf.Bytes()
 	if len(serializedData) != DIRECTORY_ENTRY_SIZE {
@@ -127,93 +174,150 @@ func DeserializeEntry(data []byte) (DirectoryEntry, error) {
 		return de, fmt.Errorf("deserialize modtime: %w", err)
 	}
 
+	// 6. Baca Mode, UID, dan GID
+	for _, field := range []interface{}{&de.Mode, &de.UID, &de.GID} {
+		if err := binary.Read(buf, binary.LittleEndian, field); err != nil {
+			return de, fmt.Errorf("deserialize mode/owner: %w", err)
+		}
+	}
+
+	// 7. Baca AccessTime, ChangeTime, dan BirthTime
+	for _, field := range []interface{}{&de.AccessTime, &de.ChangeTime, &de.BirthTime} {
+		if err := binary.Read(buf, binary.LittleEndian, field); err != nil {
+			return de, fmt.Errorf("deserialize times: %w", err)
+		}
+	}
+
+	// 8. Baca Nlink
+	if err := binary.Read(buf, binary.LittleEndian, &de.Nlink); err != nil {
+		return de, fmt.Errorf("deserialize nlink: %w", err)
+	}
+
 	return de, nil
 }
 
 // Fungsi untuk menginisialisasi seluruh "Disk" dan FAT
-// Ini seperti memformat disk.
-func FormatDisk() error {
-	// 1. Inisialisasi Disk: Buat slice Disk dengan TOTAL_BLOCKS elemen.
-	//    Setiap elemen Disk[i] adalah slice byte dengan panjang BLOCK_SIZE.
-	Disk = make([][]byte, TOTAL_BLOCKS)
-	for i := 0; i < TOTAL_BLOCKS; i++ {
-		Disk[i] = make([]byte, BLOCK_SIZE) // Setiap blok diisi byte kosong (nilai default 0)
-	}
-	fmt.Printf("Disk initialized with %d blocks, each %d bytes.\n", TOTAL_BLOCKS, BLOCK_SIZE)
-
-	// 2. Inisialisasi FAT: Buat slice FAT dengan TOTAL_BLOCKS elemen.
-	//    Setiap elemen FAT[i] awalnya adalah FAT_FREE (blok kosong).
-	FAT = make([]BlockID, TOTAL_BLOCKS)
-	for i := 0; i < TOTAL_BLOCKS; i++ {
-		FAT[i] = FAT_FREE
+// Ini seperti memformat disk. geo menentukan ukuran blok, jumlah blok, panjang nama maksimum,
+// jumlah blok reserved, dan jumlah salinan FAT; field yang bernilai 0 memakai nilai bawaan.
+func (fs *FileSystem) FormatDisk(geo Geometry) error {
+	geo = geo.WithDefaults()
+	if err := geo.Validate(); err != nil {
+		return fmt.Errorf("geometri disk tidak valid: %w", err)
+	}
+	fs.Geometry = geo
+
+	// 1. Inisialisasi Disk: Buat slice Disk dengan fs.Geometry.TotalBlocks elemen.
+	//    Setiap elemen fs.Disk[i] adalah slice byte dengan panjang fs.Geometry.BlockSize.
+	fs.Disk = make([][]byte, fs.Geometry.TotalBlocks)
+	for i := 0; i < fs.Geometry.TotalBlocks; i++ {
+		fs.Disk[i] = make([]byte, fs.Geometry.BlockSize) // Setiap blok diisi byte kosong (nilai default 0)
+	}
+	fmt.Printf("Disk initialized with %d blocks, each %d bytes.\n", fs.Geometry.TotalBlocks, fs.Geometry.BlockSize)
+
+	// 2. Inisialisasi FAT: Buat slice FAT dengan fs.Geometry.TotalBlocks elemen.
+	//    Setiap elemen fs.FAT[i] awalnya adalah FAT_FREE (blok kosong).
+	fs.FAT = make([]BlockID, fs.Geometry.TotalBlocks)
+	for i := 0; i < fs.Geometry.TotalBlocks; i++ {
+		fs.FAT[i] = FAT_FREE
 	}
 	fmt.Println("FAT initialized. All blocks marked as free.")
 
+	// 2b. Siapkan superblock, lalu cadangkan blok 0 (superblock), blok reserved lain, dan blok-blok area FAT
+	//     dengan FAT_RESERVED agar tidak pernah dialokasikan findFreeBlock.
+	//     Dengan begitu peta blok benar-benar menunjukkan ruang yang dipakai FAT itu sendiri.
+	fs.superblock = newSuperblock(DEFAULT_VOLUME_LABEL, geo)
+	for b := SUPERBLOCK_BLOCK; b < fs.superblock.FATStart; b++ {
+		fs.FAT[b] = FAT_RESERVED // Superblock dan blok reserved lainnya
+	}
+	fatEnd := fs.superblock.FATStart + BlockID(fs.superblock.FATBlocks*fs.superblock.FATCopies)
+	for b := fs.superblock.FATStart; b < fatEnd; b++ {
+		fs.FAT[b] = FAT_RESERVED
+	}
+	fmt.Printf("Blocks %d-%d reserved for %d FAT copies (%d blocks each).\n",
+		fs.superblock.FATStart, fatEnd-1, fs.superblock.FATCopies, fs.superblock.FATBlocks)
+	for b := fs.superblock.InodeStart; b < fs.superblock.RootBlock; b++ {
+		fs.FAT[b] = FAT_RESERVED // Tabel inode dan bitmap (hanya jika geo.Inodes > 0 / FREE_BITMAP, lihat inode.go dan freespace.go)
+	}
+	if geo.Inodes > 0 {
+		fmt.Printf("Blocks %d-%d reserved for the inode table (%d inodes).\n", fs.superblock.InodeStart, geo.BitmapStart()-1, geo.Inodes)
+	}
+	if geo.BitmapBlocks() > 0 {
+		fmt.Printf("Blocks %d-%d reserved for the free-space bitmap.\n", geo.BitmapStart(), fs.superblock.RootBlock-1)
+	}
+
 	// 3. Alokasikan blok untuk Root Directory:
-	//    - Pastikan ROOT_DIR_BLOCK valid (tidak melebihi TOTAL_BLOCKS).
-	//    - Set FAT[ROOT_DIR_BLOCK] menjadi FAT_EOF (karena root dir awalnya hanya 1 blok dan itu blok terakhirnya).
-	if ROOT_DIR_BLOCK >= BlockID(TOTAL_BLOCKS) || ROOT_DIR_BLOCK < 0 {
-		return errors.New("invalid ROOT_DIR_BLOCK configuration")
+	//    - Root directory diletakkan tepat setelah area FAT (RootBlock di superblock).
+	//    - Pastikan fs.RootDirBlock valid (tidak melebihi fs.Geometry.TotalBlocks).
+	//    - Set fs.FAT[fs.RootDirBlock] menjadi FAT_EOF (karena root dir awalnya hanya 1 blok dan itu blok terakhirnya).
+	fs.RootDirBlock = fs.superblock.RootBlock
+	if fs.RootDirBlock >= BlockID(fs.Geometry.TotalBlocks) || fs.RootDirBlock < 0 {
+		return errors.New("invalid fs.RootDirBlock configuration (disk terlalu kecil untuk FAT)")
 	}
-	FAT[ROOT_DIR_BLOCK] = FAT_EOF
-	fmt.Printf("Block %d allocated for Root Directory and marked as EOF in FAT.\n", ROOT_DIR_BLOCK)
+	fs.FAT[fs.RootDirBlock] = FAT_EOF
+	fmt.Printf("Block %d allocated for Root Directory and marked as EOF in FAT.\n", fs.RootDirBlock)
 
 	// 4. Buat entri "." (direktori saat ini) untuk Root Directory:
 	//    - Buat instance DirectoryEntry.
 	//    - Isi field-fieldnya:
 	//        Name: "." (gunakan helper untuk konversi string ke [MAX_FILENAME_LEN]byte)
 	//        Type: TYPE_DIRECTORY
-	//        StartBlock: ROOT_DIR_BLOCK (karena "." dari root menunjuk ke root itu sendiri)
+	//        StartBlock: fs.RootDirBlock (karena "." dari root menunjuk ke root itu sendiri)
 	//        Size: 0 (atau bisa dihitung nanti berdasarkan jumlah entri)
 	//        ModTime: time.Now().UnixNano()
 	var dotEntry DirectoryEntry
 	copy(dotEntry.Name[:], ".") // Salin string ke array byte
 	dotEntry.Type = TYPE_DIRECTORY
-	dotEntry.StartBlock = ROOT_DIR_BLOCK
+	dotEntry.StartBlock = fs.RootDirBlock
 	dotEntry.Size = 0 // Untuk direktori, size bisa berarti jumlah entri atau ukuran data entri
-	dotEntry.ModTime = time.Now().UnixNano()
+	stampNew(&dotEntry, time.Now().UnixNano())
+	dotEntry.Mode = ROOT_DIR_MODE // Root dimiliki root (UID/GID 0), izinnya dibaca dari entri "." ini
+	if err := fs.allocInode(&dotEntry); err != nil {
+		return fmt.Errorf("failed to allocate root inode: %w", err)
+	}
 
 	// 5. Buat entri ".." (parent directory) untuk Root Directory:
 	//    - Buat instance DirectoryEntry.
 	//    - Isi field-fieldnya:
 	//        Name: ".."
 	//        Type: TYPE_DIRECTORY
-	//        StartBlock: ROOT_DIR_BLOCK (karena parent dari root adalah root itu sendiri dalam simulasi ini)
+	//        StartBlock: fs.RootDirBlock (karena parent dari root adalah root itu sendiri dalam simulasi ini)
 	//        Size: 0
 	//        ModTime: time.Now().UnixNano()
 	var dotDotEntry DirectoryEntry
 	copy(dotDotEntry.Name[:], "..")
 	dotDotEntry.Type = TYPE_DIRECTORY
-	dotDotEntry.StartBlock = ROOT_DIR_BLOCK // Parent dari root adalah root
+	dotDotEntry.StartBlock = fs.RootDirBlock // Parent dari root adalah root
 	dotDotEntry.Size = 0
-	dotDotEntry.ModTime = time.Now().UnixNano()
+	stampNew(&dotDotEntry, dotEntry.BirthTime)
+	dotDotEntry.Mode = ROOT_DIR_MODE
+	dotDotEntry.Inode = dotEntry.Inode
 
-	// 6. Serialize entri "." dan ".." menjadi byte.
-	dotBytes, err := dotEntry.Serialize()
+	// 6. Serialize entri "." dan ".." menjadi byte (sesuai layout disk, lihat encodeSlot).
+	dotBytes, err := fs.encodeSlot(dotEntry)
 	if err != nil {
 		return fmt.Errorf("failed to serialize '.' entry: %w", err)
 	}
-	dotDotBytes, err := dotDotEntry.Serialize()
+	dotDotBytes, err := fs.encodeSlot(dotDotEntry)
 	if err != nil {
 		return fmt.Errorf("failed to serialize '..' entry: %w", err)
 	}
 
-	// 7. Tulis byte hasil serialisasi ke blok data Root Directory (Disk[ROOT_DIR_BLOCK]):
-	//    - Entri pertama (dotBytes) ditulis mulai dari byte ke-0 di Disk[ROOT_DIR_BLOCK].
+	// 7. Tulis byte hasil serialisasi ke blok data Root Directory (fs.Disk[fs.RootDirBlock]):
+	//    - Entri pertama (dotBytes) ditulis mulai dari byte ke-0 di fs.Disk[fs.RootDirBlock].
 	//    - Entri kedua (dotDotBytes) ditulis setelah entri pertama.
-	//    - Pastikan tidak melebihi BLOCK_SIZE.
+	//    - Pastikan tidak melebihi fs.Geometry.BlockSize.
 	offset := 0
-	if offset+len(dotBytes) > BLOCK_SIZE {
+	if offset+len(dotBytes) > fs.Geometry.BlockSize {
 		return errors.New("block size too small for '.' entry")
 	}
-	copy(Disk[ROOT_DIR_BLOCK][offset:], dotBytes)
+	copy(fs.Disk[fs.RootDirBlock][offset:], dotBytes)
 	offset += len(dotBytes)
 	fmt.Printf("Serialized '.' entry (size %d) written to Root Directory block.\n", len(dotBytes))
 
-	if offset+len(dotDotBytes) > BLOCK_SIZE {
+	if offset+len(dotDotBytes) > fs.Geometry.BlockSize {
 		return errors.New("block size too small for '..' entry after '.' entry")
 	}
-	copy(Disk[ROOT_DIR_BLOCK][offset:], dotDotBytes)
+	copy(fs.Disk[fs.RootDirBlock][offset:], dotDotBytes)
 	fmt.Printf("Serialized '..' entry (size %d) written to Root Directory block after '.' entry.\n", len(dotDotBytes))
 
 	// Kita juga perlu menandai ukuran direktori root berdasarkan entri yang ada
@@ -221,31 +325,77 @@ func FormatDisk() error {
 	// Tapi ini akan dikelola oleh fungsi yang memanipulasi direktori nanti.
 	// Untuk format, cukup entri . dan .. ada.
 
+	// 8. Tulis FAT ke semua salinannya di disk dan bangun struktur ruang kosong darinya (lihat freespace.go),
+	//    lalu superblock ke blok 0 (setelah FAT final agar jumlah blok kosong dan kepala struktur benar)
+	fs.flushFAT()
+	fs.rebuildFreeSpace()
+	fs.superblock.FreeBlocks = fs.countFreeBlocks()
+	if err := fs.writeSuperblock(&fs.superblock); err != nil {
+		return fmt.Errorf("failed to write superblock: %w", err)
+	}
+	fmt.Printf("Superblock written to block %d (label '%s', %d free blocks).\n", SUPERBLOCK_BLOCK, fs.superblock.Label(), fs.superblock.FreeBlocks)
+
+	fs.CurrentDirectoryBlock = fs.RootDirBlock
+	fs.resetFileTable()
 	fmt.Println("Disk formatting complete. Root directory initialized with '.' and '..' entries.")
 	return nil
 }
 
-// Fungsi helper untuk NewFileSystem agar bisa dipanggil dari main.go
-type FileSystem struct {
-	CurrentDirectoryBlock BlockID
-	// Bisa ditambahkan field lain jika perlu state global
+// FileSystemOptions: Opsi untuk NewFileSystem.
+// Nilai kosong (FileSystemOptions{}) berarti disk baru diformat seperti biasa.
+type FileSystemOptions struct {
+	// ImagePath: Jika diisi dan file-nya ada di host, disk dimuat dari image tersebut
+	// alih-alih diformat ulang. Jika file belum ada, disk diformat seperti biasa.
+	ImagePath string
+	// Geometry: Geometri untuk memformat disk baru. Field yang bernilai 0 memakai DefaultGeometry().
+	Geometry Geometry
+	// Mount: Opsi mount (misalnya noatime atau best fit). Nilai kosong berarti relatime dan first fit.
+	Mount MountOptions
 }
 
-func NewFileSystem() (*FileSystem, error) {
-	err := FormatDisk()
+// Fungsi helper untuk NewFileSystem agar bisa dipanggil dari main.go
+func NewFileSystem(opts FileSystemOptions) (*FileSystem, error) {
+	fs := &FileSystem{Users: DefaultUsers(), Groups: DefaultGroups(), Mount: opts.Mount}
+	fs.CurrentUser = fs.Users[0] // root
+	if opts.ImagePath != "" {
+		if _, statErr := os.Stat(opts.ImagePath); statErr == nil {
+			err := fs.LoadImage(opts.ImagePath)
+			if err != nil {
+				return nil, fmt.Errorf("failed to load disk image during NewFileSystem: %w", err)
+			}
+			return fs, nil
+		} else if !errors.Is(statErr, os.ErrNotExist) {
+			return nil, fmt.Errorf("failed to open disk image during NewFileSystem: %w", statErr)
+		}
+		fmt.Printf("Image '%s' belum ada, disk baru akan diformat.\n", opts.ImagePath)
+	}
+
+	err := fs.FormatDisk(opts.Geometry)
 	if err != nil {
 		return nil, fmt.Errorf("failed to format disk during NewFileSystem: %w", err)
 	}
-	fs := &FileSystem{
-		CurrentDirectoryBlock: ROOT_DIR_BLOCK,
-	}
 	return fs, nil
 }
 
 // ListEntries: Membaca semua DirectoryEntry dari sebuah direktori.
 // Input: directoryStartBlock adalah nomor blok pertama dari direktori yang ingin dibaca.
 // Output: Slice dari DirectoryEntry yang ada di direktori tersebut, dan error jika ada.
-func ListEntries(directoryStartBlock BlockID) ([]DirectoryEntry, error) {
+// Pengguna saat ini harus punya izin baca (r) pada direktori tersebut. atime direktori diperbarui sesuai MountOptions.
+func (fs *FileSystem) ListEntries(directoryStartBlock BlockID) ([]DirectoryEntry, error) {
+	if directoryStartBlock != FAT_FREE {
+		if err := fs.checkDirAccess("list", directoryStartBlock, ACCESS_READ); err != nil {
+			return nil, err
+		}
+	}
+	entries, err := fs.listEntries(directoryStartBlock)
+	if err == nil && directoryStartBlock != FAT_FREE {
+		fs.noteDirectoryAccess(directoryStartBlock) // Membaca isi direktori memperbarui atime-nya
+	}
+	return entries, err
+}
+
+// listEntries: ListEntries tanpa pemeriksaan izin, untuk penelusuran internal (mencari nama, Getwd, statistik disk).
+func (fs *FileSystem) listEntries(directoryStartBlock BlockID) ([]DirectoryEntry, error) {
 	// 1. Buat slice kosong untuk menampung hasil DirectoryEntry.
 	//    Ini adalah daftar file/folder yang akan kita kembalikan.
 	var entries []DirectoryEntry
@@ -257,58 +407,21 @@ func ListEntries(directoryStartBlock BlockID) ([]DirectoryEntry, error) {
 		// Untuk sekarang, kita kembalikan slice kosong saja, menandakan tidak ada entri.
 		return entries, nil
 	}
-	if directoryStartBlock < 0 || directoryStartBlock >= BlockID(TOTAL_BLOCKS) {
+	if directoryStartBlock < 0 || directoryStartBlock >= BlockID(fs.Geometry.TotalBlocks) {
 		return nil, fmt.Errorf("blok awal direktori tidak valid: %d", directoryStartBlock)
 	}
 
 	// 3. Iterasi Melalui Rantai Blok Direktori di FAT:
 	//    Sebuah direktori (seperti file) bisa saja memakan lebih dari satu blok jika isinya banyak.
-	//    Kita perlu mengikuti rantai blok di FAT.
-	currentBlock := directoryStartBlock
-	for currentBlock != FAT_EOF && currentBlock != FAT_FREE {
-		// a. Validasi currentBlock (lagi, untuk keamanan tambahan di dalam loop)
-		if currentBlock < 0 || currentBlock >= BlockID(TOTAL_BLOCKS) {
-			return entries, fmt.Errorf("ditemukan nomor blok tidak valid (%d) dalam rantai direktori", currentBlock)
-		}
-
-		// b. Ambil data byte dari blok disk saat ini.
-		//    Disk[currentBlock] adalah []byte yang berisi data mentah dari blok tersebut.
-		blockData := Disk[currentBlock]
-
-		// c. Iterasi di dalam satu blok untuk membaca setiap DirectoryEntry.
-		//    Setiap DirectoryEntry punya ukuran DIRECTORY_ENTRY_SIZE byte.
-		//    Kita akan membaca blok ini per DIRECTORY_ENTRY_SIZE byte.
-		for offset := 0; offset+DIRECTORY_ENTRY_SIZE <= BLOCK_SIZE; offset += DIRECTORY_ENTRY_SIZE {
-			// i. Ambil satu potong data seukuran DirectoryEntry.
-			entryData := blockData[offset : offset+DIRECTORY_ENTRY_SIZE] // ii. Cek apakah entri ini "kosong" atau dihapus (invaliated).
-			//     Konvensi sederhana: jika byte pertama dari nama adalah 0,
-			//     kita anggap itu entri kosong / tidak terpakai / dihapus.
-			//     Ini penting agar kita tidak membaca data sampah.
-			if entryData[0] == 0 {
-				// Jika kita menemukan entri yang namanya dimulai dengan byte 0,
-				// ini adalah entri kosong atau dihapus, tapi masih mungkin ada entri valid lain setelahnya.
-				// Kita lewati entri ini dan lanjutkan ke entri berikutnya dalam blok yang sama.
-				continue
-			} // iii. Deserialize data byte menjadi struct DirectoryEntry.
-			entry, err := DeserializeEntry(entryData)
-			if err != nil {
-				// Jika ada error saat deserialize satu entri, kita bisa memilih untuk
-				// mengabaikannya dan lanjut, atau mengembalikan error.
-				// Untuk sekarang, kita catat errornya dan lanjut ke blok berikutnya (jika ada).
-				// Atau, bisa juga return error:
-				// return entries, fmt.Errorf("gagal deserialize entri di blok %d offset %d: %w", currentBlock, offset, err)
-				fmt.Printf("Warning: Gagal deserialize entri di blok %d offset %d: %v. Mungkin akhir dari data valid.\n", currentBlock, offset, err)
-				continue // Skip this entry and continue with the next one
-			}
-
-			// iv. Tambahkan entri yang berhasil di-deserialize ke slice hasil.
-			entries = append(entries, entry)
-		}
-
-		// Label untuk 'goto' agar bisa lanjut ke blok berikutnya
-		// d. Ambil nomor blok berikutnya dari FAT untuk direktori ini.
-		currentBlock = FAT[currentBlock] // Pindah ke blok selanjutnya dalam rantai
-	} // Akhir dari loop 'for currentBlock'
+	//    scanDirectory mengikuti rantai blok di FAT, melewati slot kosong, dan merakit nama panjang
+	//    dari slot LFN yang mendahului setiap entri (lihat lfn.go).
+	err := fs.scanDirectory(directoryStartBlock, func(rec dirRecord) bool {
+		entries = append(entries, rec.Entry)
+		return true
+	})
+	if err != nil {
+		return entries, fmt.Errorf("rantai direktori rusak: %w", err)
+	}
 
 	// 4. Kembalikan daftar entri yang sudah terkumpul.
 	return entries, nil
@@ -317,29 +430,13 @@ func ListEntries(directoryStartBlock BlockID) ([]DirectoryEntry, error) {
 // filesystem_logic.go
 // (Lanjutan dari kode sebelumnya)
 
-// findFreeBlock: Mencari blok kosong pertama di FAT.
+// findFreeBlock: Mencari satu blok kosong di FAT dengan strategi fit disk (fs.Mount.Fit, lihat contiguous.go).
+// Dengan first fit (bawaan) hasilnya sama dengan pencarian linear dari blok 0, yaitu blok kosong pertama.
+// Superblock dan area FAT sudah bertanda FAT_RESERVED sehingga tidak akan pernah terpilih di sini.
 // Mengembalikan BlockID dari blok kosong tersebut, atau error jika tidak ada blok kosong (disk penuh).
-func findFreeBlock() (BlockID, error) {
-	// Kita iterasi melalui seluruh FAT.
-	// Ingat, blok 0 bisa jadi punya arti khusus (misalnya superblok) atau tidak digunakan.
-	// Di desain kita, ROOT_DIR_BLOCK adalah 1. Kita bisa mulai cari dari blok setelah itu,
-	// atau dari awal jika blok 0 juga bisa dipakai untuk data umum.
-	// Untuk sederhana, kita cari dari blok ke-0. Jika ada blok khusus,
-	// kita harus pastikan tidak mengalokasikannya secara tidak sengaja di sini.
-	// Mari kita asumsikan untuk saat ini, blok 0 bisa saja dipakai jika FAT_FREE.
-	// Namun, lebih aman untuk memulai pencarian dari blok setelah yang sudah pasti dipakai (misal, setelah ROOT_DIR_BLOCK).
-	// Untuk fungsi umum findFreeBlock, iterasi dari awal FAT itu logis.
-	// Kita akan skip blok 0 jika itu adalah SUPER_BLOCK_ID atau semacamnya.
-	// Karena ROOT_DIR_BLOCK kita = 1, kita bisa mulai cari dari blok 2, atau bahkan 0 jika FAT[0] bisa FAT_FREE.
-	// Mari kita cari dari semua blok untuk generalitas.
-	for i := BlockID(0); i < BlockID(TOTAL_BLOCKS); i++ {
-		if FAT[i] == FAT_FREE {
-			// Ditemukan blok kosong!
-			return i, nil // Kembalikan nomor bloknya
-		}
-	}
-	// Jika loop selesai dan tidak ada blok kosong yang ditemukan, berarti disk penuh.
-	return -1, errors.New("disk penuh, tidak ada blok kosong ditemukan")
+func (fs *FileSystem) findFreeBlock() (BlockID, error) {
+	ext, err := fs.findFreeExtent(1)
+	return ext.Start, err
 }
 
 // filesystem_logic.go
@@ -347,14 +444,14 @@ func findFreeBlock() (BlockID, error) {
 
 // addEntryToDirectory: Menambahkan sebuah DirectoryEntry baru ke dalam direktori induk.
 // Ia akan mencari slot kosong di blok-blok data direktori induk.
-// Untuk saat ini, TIDAK menangani kasus jika direktori induk perlu blok baru (itu fitur lanjutan).
-func addEntryToDirectory(parentDirStartBlock BlockID, newEntry DirectoryEntry) error {
-	if parentDirStartBlock < 0 || parentDirStartBlock >= BlockID(TOTAL_BLOCKS) || FAT[parentDirStartBlock] == FAT_FREE {
+// Jika semua blok direktori induk sudah penuh, rantai direktori diperpanjang dengan satu blok baru (lihat growDirectory).
+func (fs *FileSystem) addEntryToDirectory(parentDirStartBlock BlockID, newEntry DirectoryEntry) error {
+	if parentDirStartBlock < 0 || parentDirStartBlock >= BlockID(fs.Geometry.TotalBlocks) || fs.FAT[parentDirStartBlock] == FAT_FREE {
 		return errors.New("blok awal direktori induk tidak valid atau belum dialokasikan")
 	}
 
-	// Serialize entri baru menjadi byte
-	entryBytes, err := newEntry.Serialize()
+	// Serialize entri baru menjadi byte (pada layout inode, metadatanya sekaligus ditulis ke inode)
+	entryBytes, err := fs.encodeSlot(newEntry)
 	if err != nil {
 		return fmt.Errorf("gagal serialize entri baru: %w", err)
 	}
@@ -362,89 +459,65 @@ func addEntryToDirectory(parentDirStartBlock BlockID, newEntry DirectoryEntry) e
 		return fmt.Errorf("ukuran byte entri baru (%d) tidak sesuai dengan DIRECTORY_ENTRY_SIZE (%d)", len(entryBytes), DIRECTORY_ENTRY_SIZE)
 	}
 
-	// Iterasi melalui rantai blok direktori induk
-	currentBlock := parentDirStartBlock
-	for currentBlock != FAT_EOF && currentBlock != FAT_FREE {
-		if currentBlock < 0 || currentBlock >= BlockID(TOTAL_BLOCKS) {
-			return fmt.Errorf("nomor blok tidak valid (%d) dalam rantai direktori induk", currentBlock)
-		}
+	// Entri dengan nama panjang butuh slot LFN tepat sebelum entri pendeknya
+	slotsData := [][]byte{}
+	if newEntry.LongName != "" {
+		slotsData = encodeLFNSlots(newEntry.LongName, shortNameChecksum(newEntry.Name))
+	}
+	slotsData = append(slotsData, entryBytes)
 
-		blockData := Disk[currentBlock] // Ambil data dari blok saat ini
-
-		// Cari slot kosong di dalam blok ini
-		for offset := 0; offset+DIRECTORY_ENTRY_SIZE <= BLOCK_SIZE; offset += DIRECTORY_ENTRY_SIZE {
-			// Cek apakah slot ini kosong (nama dimulai dengan byte 0)
-			potentialEmptySlot := blockData[offset : offset+DIRECTORY_ENTRY_SIZE]
-			if potentialEmptySlot[0] == 0 { // Byte pertama dari nama adalah 0, berarti slot kosong
-				// Ditemukan slot kosong! Tulis entri baru di sini.
-				copy(Disk[currentBlock][offset:], entryBytes) // Salin byte entri baru ke disk
-				fmt.Printf("Entri '%s' ditambahkan ke blok %d direktori induk, offset %d.\n",
-					string(newEntry.Name[:bytes.IndexByte(newEntry.Name[:], 0)]), parentDirStartBlock, offset)
-				// Kita juga perlu update ModTime direktori induk
-				// Ini bisa dilakukan oleh fungsi yang memanggil addEntryToDirectory, atau di sini
-				// (Untuk sekarang kita skip update ModTime induk agar sederhana)
-				return nil // Berhasil menambahkan entri
-			}
-		}
-		// Jika blok ini penuh (tidak ada slot kosong), pindah ke blok berikutnya dari direktori induk
-		// prevBlock := currentBlock // Commented out - will be used in future implementations
-		currentBlock = FAT[currentBlock]
-
-		// FITUR LANJUTAN (BELUM DIIMPLEMENTASIKAN DI SINI):
-		// Jika currentBlock sekarang FAT_EOF (artinya blok prevBlock adalah yang terakhir dan penuh),
-		// dan kita masih belum menemukan slot, kita seharusnya:
-		// 1. Cari blok kosong baru dengan findFreeBlock().
-		// 2. Alokasikan blok baru itu di FAT, dengan FAT[prevBlock] = blokBaru, dan FAT[blokBaru] = FAT_EOF.
-		// 3. Kemudian tulis entri baru kita ke blokBaru tersebut.
-		// Untuk versi saat ini, jika semua blok yang ada penuh, kita akan error.
-		if currentBlock == FAT_EOF {
-			// Kode untuk mengalokasikan blok baru untuk direktori induk akan ada di sini
-			// Untuk sementara, kita anggap ini error "direktori induk penuh dan tidak bisa diperluas otomatis"
-			return errors.New("direktori induk penuh, tidak dapat menambahkan entri baru (fitur perluasan blok direktori belum ada)")
-		}
+	// Cari slot kosong berurutan di rantai blok direktori induk; jika semua blok sudah penuh,
+	// rantai direktori diperpanjang dengan blok baru (lihat findFreeRun dan growDirectory).
+	run, err := fs.findFreeRun(parentDirStartBlock, len(slotsData))
+	if err != nil {
+		return fmt.Errorf("tidak dapat menemukan slot untuk entri '%s' di direktori induk: %w", newEntry.NameString(), err)
 	}
-	// Jika loop selesai karena currentBlock menjadi FAT_FREE (seharusnya tidak terjadi jika FAT dikelola dengan baik)
-	// atau kondisi lain yang tidak terduga.
-	return errors.New("tidak dapat menemukan slot untuk menambahkan entri di direktori induk (mungkin rantai FAT rusak atau direktori penuh)")
+	for i, slot := range run {
+		copy(fs.Disk[slot.Block][slot.Offset:], slotsData[i]) // Salin byte entri baru ke disk
+	}
+	last := run[len(run)-1]
+	fmt.Printf("Entri '%s' ditambahkan ke blok %d direktori induk, offset %d (%d slot).\n",
+		newEntry.NameString(), last.Block, last.Offset, len(run))
+	return nil
 }
 
 // filesystem_logic.go
 // (Lanjutan dari kode sebelumnya)
 
 // CreateDirectory: Membuat direktori baru di dalam parentDirStartBlock.
-func CreateDirectory(parentDirStartBlock BlockID, newDirName string) error {
+func (fs *FileSystem) CreateDirectory(parentDirStartBlock BlockID, newDirName string) error {
 	// 1. Validasi Nama Direktori Baru
 	if len(newDirName) == 0 {
 		return errors.New("nama direktori tidak boleh kosong")
 	}
-	if len(newDirName) > MAX_FILENAME_LEN {
-		return fmt.Errorf("nama direktori terlalu panjang (maks %d karakter)", MAX_FILENAME_LEN)
+	if strings.ContainsRune(newDirName, '/') {
+		return errors.New("nama direktori tidak boleh mengandung '/' (pemisah path)")
 	}
-	// (Bisa ditambahkan validasi karakter ilegal jika perlu)
 
-	// 2. Cek Apakah Nama Sudah Ada di Direktori Induk
-	//    Kita gunakan ListEntries yang sudah kita buat!
-	parentEntries, err := ListEntries(parentDirStartBlock)
-	if err != nil {
-		return fmt.Errorf("gagal membaca direktori induk: %w", err)
+	// 1b. Membuat entri baru butuh izin tulis dan eksekusi (w, x) pada direktori induk
+	if err := fs.checkDirAccess("mkdir", parentDirStartBlock, ACCESS_WRITE|ACCESS_EXEC); err != nil {
+		return err
 	}
-	for _, entry := range parentEntries {
-		// Konversi nama dari byte array ke string untuk perbandingan
-		entryName := string(entry.Name[:bytes.IndexByte(entry.Name[:], 0)]) // Berhenti di null terminator
-		if entryName == newDirName {
-			return fmt.Errorf("direktori atau file dengan nama '%s' sudah ada", newDirName)
-		}
+
+	// 2. Cek Apakah Nama Sudah Ada di Direktori Induk (izin induk sudah diperiksa di atas),
+	//    lalu siapkan nama entri: nama pendek, atau nama panjang dengan alias 8.3 (lihat assignName).
+	if _, err := fs.findEntry(parentDirStartBlock, newDirName); err == nil {
+		return fmt.Errorf("direktori atau file dengan nama '%s' sudah ada", newDirName)
+	}
+	var dirEntryForParent DirectoryEntry
+	if err := fs.assignName(&dirEntryForParent, parentDirStartBlock, newDirName); err != nil {
+		return fmt.Errorf("nama direktori tidak valid: %w", err)
 	}
 
 	// 3. Cari Blok Kosong untuk Data Direktori Baru
-	newDirDataBlock, err := findFreeBlock()
+	newDirDataBlock, err := fs.findFreeBlock()
 	if err != nil {
 		return fmt.Errorf("gagal membuat direktori (tidak ada blok kosong): %w", err)
 	}
 
 	// 4. Alokasikan Blok Tersebut di FAT untuk Direktori Baru
 	//    Direktori baru awalnya hanya 1 blok dan itu blok terakhirnya.
-	FAT[newDirDataBlock] = FAT_EOF
+	fs.setFAT(newDirDataBlock, FAT_EOF)
 	fmt.Printf("Blok %d dialokasikan untuk direktori baru '%s'.\n", newDirDataBlock, newDirName)
 
 	// 5. Buat dan Tulis Entri "." dan ".." untuk Direktori Baru Ini
@@ -454,8 +527,13 @@ func CreateDirectory(parentDirStartBlock BlockID, newDirName string) error {
 	dotEntry.Type = TYPE_DIRECTORY
 	dotEntry.StartBlock = newDirDataBlock           // Menunjuk ke blok data direktori baru ini
 	dotEntry.Size = int64(2 * DIRECTORY_ENTRY_SIZE) // Awalnya berisi . dan ..
-	dotEntry.ModTime = time.Now().UnixNano()
-	dotBytes, _ := dotEntry.Serialize() // Error handling diabaikan untuk ringkas, idealnya dicek
+	stampNew(&dotEntry, time.Now().UnixNano())
+	fs.newEntryOwner(&dotEntry, DEFAULT_DIR_MODE) // Sumber izin direktori baru (lihat directoryMeta)
+	if err := fs.allocInode(&dotEntry); err != nil {
+		fs.setFAT(newDirDataBlock, FAT_FREE)
+		return fmt.Errorf("gagal membuat direktori '%s': %w", newDirName, err)
+	}
+	dotBytes, _ := fs.encodeSlot(dotEntry) // Error handling diabaikan untuk ringkas, idealnya dicek
 
 	//    b. Entri ".." (menunjuk ke direktori induknya)
 	var dotDotEntry DirectoryEntry
@@ -463,46 +541,53 @@ func CreateDirectory(parentDirStartBlock BlockID, newDirName string) error {
 	dotDotEntry.Type = TYPE_DIRECTORY
 	dotDotEntry.StartBlock = parentDirStartBlock // Menunjuk ke blok awal direktori induk
 	dotDotEntry.Size = 0                         // Size untuk ".." bisa 0 atau size induk, untuk simpel 0 dulu
-	dotDotEntry.ModTime = time.Now().UnixNano()
-	dotDotBytes, _ := dotDotEntry.Serialize() // Error handling diabaikan
+	stampNew(&dotDotEntry, dotEntry.BirthTime)
+	if parentMeta, errMeta := fs.directoryMeta(parentDirStartBlock); errMeta == nil {
+		dotDotEntry.Mode, dotDotEntry.UID, dotDotEntry.GID = parentMeta.Mode, parentMeta.UID, parentMeta.GID
+		dotDotEntry.Inode = parentMeta.Inode
+	}
+	dotDotBytes, _ := fs.encodeSlot(dotDotEntry) // Error handling diabaikan
 
-	//    c. Tulis kedua entri ini ke blok data direktori baru (Disk[newDirDataBlock])
+	//    c. Tulis kedua entri ini ke blok data direktori baru (fs.Disk[newDirDataBlock])
 	offset := 0
-	copy(Disk[newDirDataBlock][offset:], dotBytes)
+	copy(fs.Disk[newDirDataBlock][offset:], dotBytes)
 	offset += len(dotBytes)
-	copy(Disk[newDirDataBlock][offset:], dotDotBytes)
+	copy(fs.Disk[newDirDataBlock][offset:], dotDotBytes)
 	offset += len(dotDotBytes)
 	fmt.Printf("Entri '.' dan '..' ditulis ke blok data direktori '%s'.\n", newDirName)
 	// --- TAMBAHAN BARU: Tandai akhir entri di blok direktori baru ---
 	// Jika masih ada ruang di blok setelah entri "." dan "..",
 	// pastikan byte pertama dari slot entri berikutnya adalah 0.
 	// Ini menandakan ke ListEntries bahwa tidak ada entri lagi di blok ini.
-	if offset < BLOCK_SIZE {
+	if offset < fs.Geometry.BlockSize {
 		// Inicializa seluruh sisa blok dengan 0 untuk memastikan tidak ada data sampah
-		for i := offset; i < BLOCK_SIZE; i++ {
-			Disk[newDirDataBlock][i] = 0
+		for i := offset; i < fs.Geometry.BlockSize; i++ {
+			fs.Disk[newDirDataBlock][i] = 0
 		}
 	}
 	// --- AKHIR TAMBAHAN BARU ---
 
 	// 6. Buat DirectoryEntry untuk Direktori Baru Ini (yang akan disimpan di direktori induk)
-	var dirEntryForParent DirectoryEntry
-	copy(dirEntryForParent.Name[:], newDirName)
+	//    (Name dan LongName sudah diisi assignName di langkah 2)
 	dirEntryForParent.Type = TYPE_DIRECTORY
 	dirEntryForParent.StartBlock = newDirDataBlock           // Menunjuk ke blok data yang baru dialokasikan
 	dirEntryForParent.Size = int64(2 * DIRECTORY_ENTRY_SIZE) // Ukuran awal karena ada . dan ..
-	dirEntryForParent.ModTime = time.Now().UnixNano()
+	stampNew(&dirEntryForParent, dotEntry.BirthTime)         // Sama dengan entri "." miliknya
+	fs.newEntryOwner(&dirEntryForParent, DEFAULT_DIR_MODE)
+	dirEntryForParent.Inode = dotEntry.Inode // Pada layout inode, "." dan entri di induk berbagi inode
 
 	// 7. Tambahkan Entri Direktori Baru Ini ke Direktori Induk
-	err = addEntryToDirectory(parentDirStartBlock, dirEntryForParent)
+	err = fs.addEntryToDirectory(parentDirStartBlock, dirEntryForParent)
 	if err != nil {
 		// Jika gagal menambahkan ke induk (misalnya induk penuh),
 		// kita idealnya harus membatalkan alokasi newDirDataBlock di FAT (rollback).
 		// Untuk sekarang, kita hanya kembalikan error.
-		FAT[newDirDataBlock] = FAT_FREE // Rollback sederhana: bebaskan lagi bloknya
+		fs.setFAT(newDirDataBlock, FAT_FREE) // Rollback sederhana: bebaskan lagi blok dan inode-nya
+		fs.freeInode(dotEntry.Inode)
 		return fmt.Errorf("gagal menambahkan entri direktori '%s' ke induk: %w", newDirName, err)
 	}
 
+	fs.touchDirectory(parentDirStartBlock) // Isi direktori induk berubah: perbarui mtime/ctime-nya
 	fmt.Printf("Direktori '%s' berhasil dibuat.\n", newDirName)
 	return nil
 }
@@ -511,68 +596,71 @@ func CreateDirectory(parentDirStartBlock BlockID, newDirName string) error {
 // (Lanjutan dari kode sebelumnya)
 
 // CreateFile: Membuat file baru di dalam parentDirStartBlock.
-func CreateFile(parentDirStartBlock BlockID, newFileName string) error {
+func (fs *FileSystem) CreateFile(parentDirStartBlock BlockID, newFileName string) error {
 	// 1. Validasi Nama File Baru
 	if len(newFileName) == 0 {
 		return errors.New("nama file tidak boleh kosong")
 	}
-	if len(newFileName) > MAX_FILENAME_LEN {
-		return fmt.Errorf("nama file terlalu panjang (maks %d karakter)", MAX_FILENAME_LEN)
+	if strings.ContainsRune(newFileName, '/') {
+		return errors.New("nama file tidak boleh mengandung '/' (pemisah path)")
 	}
-	// (Bisa ditambahkan validasi karakter ilegal jika perlu, misal '/')
 
-	// 2. Cek Apakah Nama Sudah Ada di Direktori Induk
-	//    Gunakan ListEntries yang sudah ada.
-	parentEntries, err := ListEntries(parentDirStartBlock)
-	if err != nil {
-		return fmt.Errorf("gagal membaca direktori induk saat membuat file: %w", err)
+	// 1b. Membuat entri baru butuh izin tulis dan eksekusi (w, x) pada direktori induk
+	if err := fs.checkDirAccess("create", parentDirStartBlock, ACCESS_WRITE|ACCESS_EXEC); err != nil {
+		return err
 	}
-	for _, entry := range parentEntries {
-		entryName := string(entry.Name[:bytes.IndexByte(entry.Name[:], 0)]) // Konversi nama ke string
-		if entryName == newFileName {
-			return fmt.Errorf("file atau direktori dengan nama '%s' sudah ada", newFileName)
-		}
+
+	// 2. Cek Apakah Nama Sudah Ada di Direktori Induk (izin induk sudah diperiksa di atas),
+	//    lalu siapkan nama entri: nama pendek, atau nama panjang dengan alias 8.3 (lihat assignName).
+	if _, err := fs.findEntry(parentDirStartBlock, newFileName); err == nil {
+		return fmt.Errorf("file atau direktori dengan nama '%s' sudah ada", newFileName)
+	}
+	var fileEntryForParent DirectoryEntry
+	if err := fs.assignName(&fileEntryForParent, parentDirStartBlock, newFileName); err != nil {
+		return fmt.Errorf("nama file tidak valid: %w", err)
 	}
 
-	// 3. Cari Blok Kosong untuk Data Awal File Baru
-	//    Meskipun file awalnya 0 byte, kita alokasikan 1 blok untuknya dan tandai EOF.
+	// 3. Alokasikan Blok untuk Data Awal File Baru lewat Allocator Disk (lihat alloc.go)
+	//    Meskipun file awalnya 0 byte, kita alokasikan 1 blok untuknya.
 	//    Ini akan mempermudah operasi tulis nanti dan memberikan StartBlock yang valid.
-	newFileDataBlock, err := findFreeBlock()
-	if err != nil {
+	fileEntryForParent.StartBlock = FAT_EOF
+	if _, err := fs.allocator().Resize(&fileEntryForParent, 1); err != nil {
 		return fmt.Errorf("gagal membuat file (tidak ada blok kosong untuk data file): %w", err)
 	}
+	fmt.Printf("Blok %d dialokasikan untuk file baru '%s'.\n", fileEntryForParent.StartBlock, newFileName)
 
-	// 4. Alokasikan Blok Tersebut di FAT untuk File Baru
-	//    File baru (kosong) hanya 1 blok (yang belum tentu diisi data) dan itu blok terakhirnya.
-	FAT[newFileDataBlock] = FAT_EOF
-	fmt.Printf("Blok %d dialokasikan untuk file baru '%s'.\n", newFileDataBlock, newFileName)
-
-	// 5. Buat DirectoryEntry untuk File Baru Ini (yang akan disimpan di direktori induk)
-	var fileEntryForParent DirectoryEntry
-	copy(fileEntryForParent.Name[:], newFileName)      // Salin nama file
-	fileEntryForParent.Type = TYPE_FILE                // Set tipe sebagai FILE
-	fileEntryForParent.StartBlock = newFileDataBlock   // Menunjuk ke blok data yang baru dialokasikan
-	fileEntryForParent.Size = 0                        // File baru ukurannya 0 byte
-	fileEntryForParent.ModTime = time.Now().UnixNano() // Waktu modifikasi saat ini
+	// 4. Buat DirectoryEntry untuk File Baru Ini (yang akan disimpan di direktori induk)
+	//    (Name dan LongName sudah diisi assignName di langkah 2)
+	fileEntryForParent.Type = TYPE_FILE                      // Set tipe sebagai FILE
+	fileEntryForParent.Size = 0                              // File baru ukurannya 0 byte
+	stampNew(&fileEntryForParent, time.Now().UnixNano())     // mtime, atime, ctime, dan btime = sekarang
+	fs.newEntryOwner(&fileEntryForParent, DEFAULT_FILE_MODE) // Pemilik: pengguna saat ini
+	// Pada disk dengan tabel inode, metadata di atas disimpan di inode baru (lihat inode.go)
+	if err := fs.allocInode(&fileEntryForParent); err != nil {
+		fs.freeFileBlocks(fileEntryForParent)
+		return fmt.Errorf("gagal membuat file '%s': %w", newFileName, err)
+	}
 
-	// 6. Tambahkan Entri File Baru Ini ke Direktori Induk
+	// 5. Tambahkan Entri File Baru Ini ke Direktori Induk
 	//    Gunakan fungsi addEntryToDirectory yang sudah kita buat.
-	err = addEntryToDirectory(parentDirStartBlock, fileEntryForParent)
+	err := fs.addEntryToDirectory(parentDirStartBlock, fileEntryForParent)
 	if err != nil {
 		// Jika gagal menambahkan ke induk (misalnya induk penuh),
-		// batalkan alokasi newFileDataBlock di FAT (rollback).
-		FAT[newFileDataBlock] = FAT_FREE // Bebaskan lagi bloknya
+		// batalkan alokasi blok data awal (rollback).
+		fs.freeFileBlocks(fileEntryForParent) // Bebaskan lagi blok dan inode-nya
+		fs.freeInode(fileEntryForParent.Inode)
 		return fmt.Errorf("gagal menambahkan entri file '%s' ke direktori induk: %w", newFileName, err)
 	}
 
+	fs.touchDirectory(parentDirStartBlock)
 	fmt.Printf("File '%s' berhasil dibuat.\n", newFileName)
 	return nil
 }
 
 // freeBlockChain: Membebaskan rantai blok di FAT mulai dari startBlock.
 // Semua blok dalam rantai akan di-set menjadi FAT_FREE.
-func freeBlockChain(startBlock BlockID) error {
-	if startBlock < 0 || startBlock >= BlockID(TOTAL_BLOCKS) {
+func (fs *FileSystem) freeBlockChain(startBlock BlockID) error {
+	if startBlock < 0 || startBlock >= BlockID(fs.Geometry.TotalBlocks) {
 		// Jika startBlock adalah FAT_EOF atau FAT_FREE atau tidak valid, anggap tidak ada yang perlu dibebaskan.
 		// FAT_EOF (-1) dan FAT_FREE (-2) memang < 0.
 		if startBlock == FAT_EOF || startBlock == FAT_FREE {
@@ -583,12 +671,12 @@ func freeBlockChain(startBlock BlockID) error {
 
 	currentBlock := startBlock
 	for currentBlock != FAT_EOF && currentBlock != FAT_FREE {
-		if currentBlock < 0 || currentBlock >= BlockID(TOTAL_BLOCKS) {
+		if currentBlock < 0 || currentBlock >= BlockID(fs.Geometry.TotalBlocks) {
 			// Seharusnya tidak terjadi jika FAT konsisten, tapi sebagai pengaman
 			return fmt.Errorf("ditemukan blok tidak valid (%d) saat membebaskan rantai", currentBlock)
 		}
-		nextBlock := FAT[currentBlock]
-		FAT[currentBlock] = FAT_FREE // Bebaskan blok saat ini
+		nextBlock := fs.FAT[currentBlock]
+		fs.setFAT(currentBlock, FAT_FREE) // Bebaskan blok saat ini
 		// fmt.Printf("Blok %d dibebaskan.\n", currentBlock) // Untuk debug
 		currentBlock = nextBlock
 	}
@@ -597,58 +685,27 @@ func freeBlockChain(startBlock BlockID) error {
 
 // updateEntryInDirectory: Mengupdate DirectoryEntry yang sudah ada di direktori induk.
 // Mencari entri dengan nama yang sama dan menimpanya dengan updatedEntry.
-func updateEntryInDirectory(parentDirStartBlock BlockID, updatedEntry DirectoryEntry) error {
-	if parentDirStartBlock < 0 || parentDirStartBlock >= BlockID(TOTAL_BLOCKS) || FAT[parentDirStartBlock] == FAT_FREE {
+func (fs *FileSystem) updateEntryInDirectory(parentDirStartBlock BlockID, updatedEntry DirectoryEntry) error {
+	if parentDirStartBlock < 0 || parentDirStartBlock >= BlockID(fs.Geometry.TotalBlocks) || fs.FAT[parentDirStartBlock] == FAT_FREE {
 		return errors.New("blok awal direktori induk tidak valid atau belum dialokasikan untuk update")
 	}
 
-	updatedEntryBytes, err := updatedEntry.Serialize()
+	// Cari entri berdasarkan nama (nama panjang atau alias), lalu timpa slot entri pendeknya.
+	// Slot LFN tidak perlu disentuh karena alias di field Name tidak berubah.
+	updatedEntryName := updatedEntry.NameString()
+	rec, err := fs.findRecord(parentDirStartBlock, updatedEntryName)
 	if err != nil {
-		return fmt.Errorf("gagal serialize updatedEntry: %w", err)
+		return fmt.Errorf("entri dengan nama '%s' tidak ditemukan di direktori induk untuk diupdate: %w", updatedEntryName, err)
 	}
-
-	updatedEntryName := string(updatedEntry.Name[:bytes.IndexByte(updatedEntry.Name[:], 0)])
-
-	currentBlock := parentDirStartBlock
-	for currentBlock != FAT_EOF && currentBlock != FAT_FREE {
-		if currentBlock < 0 || currentBlock >= BlockID(TOTAL_BLOCKS) {
-			return fmt.Errorf("nomor blok tidak valid (%d) dalam rantai direktori induk saat update", currentBlock)
-		}
-
-		blockData := Disk[currentBlock]
-		for offset := 0; offset+DIRECTORY_ENTRY_SIZE <= BLOCK_SIZE; offset += DIRECTORY_ENTRY_SIZE {
-			entryData := blockData[offset : offset+DIRECTORY_ENTRY_SIZE]
-			if entryData[0] == 0 { // Slot kosong, berarti entri yang dicari tidak ada di sisa blok ini
-				goto nextBlockInUpdate // Lanjut ke blok berikutnya jika ada
-			}
-
-			// Deserialize untuk perbandingan nama
-			existingEntry, errDeserialize := DeserializeEntry(entryData)
-			if errDeserialize != nil {
-				// Abaikan entri yang rusak, lanjutkan pencarian
-				fmt.Printf("Warning: Gagal deserialize entri di blok %d offset %d saat update: %v\n", currentBlock, offset, errDeserialize)
-				continue
-			}
-
-			existingEntryName := string(existingEntry.Name[:bytes.IndexByte(existingEntry.Name[:], 0)])
-
-			if existingEntryName == updatedEntryName {
-				// Ditemukan entri yang cocok! Timpa dengan data baru.
-				copy(Disk[currentBlock][offset:], updatedEntryBytes)
-				// fmt.Printf("Entri '%s' diupdate di blok %d direktori induk, offset %d.\n", updatedEntryName, currentBlock, offset)
-				return nil // Berhasil update
-			}
-		}
-	nextBlockInUpdate:
-		currentBlock = FAT[currentBlock]
+	if err := fs.writeSlot(rec.Slot, updatedEntry); err != nil {
+		return fmt.Errorf("gagal serialize updatedEntry: %w", err)
 	}
-
-	return fmt.Errorf("entri dengan nama '%s' tidak ditemukan di direktori induk untuk diupdate", updatedEntryName)
+	return nil
 }
 
 // WriteToFile: Menulis data ke sebuah file. Mode saat ini adalah OVERWRITE.
 // Membebaskan blok lama, lalu mengalokasikan blok baru sesuai kebutuhan data.
-func WriteToFile(fileEntry *DirectoryEntry, parentDirStartBlock BlockID, dataToWrite []byte) error {
+func (fs *FileSystem) WriteToFile(fileEntry *DirectoryEntry, parentDirStartBlock BlockID, dataToWrite []byte) error {
 	// 1. Validasi Awal
 	if fileEntry == nil {
 		return errors.New("fileEntry tidak boleh nil")
@@ -656,22 +713,21 @@ func WriteToFile(fileEntry *DirectoryEntry, parentDirStartBlock BlockID, dataToW
 	if fileEntry.Type != TYPE_FILE {
 		return errors.New("hanya bisa menulis ke entri bertipe FILE")
 	}
-	if parentDirStartBlock < 0 || parentDirStartBlock >= BlockID(TOTAL_BLOCKS) || FAT[parentDirStartBlock] == FAT_FREE {
+	if parentDirStartBlock < 0 || parentDirStartBlock >= BlockID(fs.Geometry.TotalBlocks) || fs.FAT[parentDirStartBlock] == FAT_FREE {
 		return errors.New("blok awal direktori induk tidak valid atau belum dialokasikan untuk file")
 	}
 
-	fileNameForLog := string(fileEntry.Name[:bytes.IndexByte(fileEntry.Name[:], 0)])
+	fileNameForLog := fileEntry.NameString()
+	if err := fs.checkAccess("write", fileNameForLog, *fileEntry, ACCESS_WRITE); err != nil {
+		return err
+	}
 	fmt.Printf("Menulis ke file '%s'. Ukuran data: %d bytes.\n", fileNameForLog, len(dataToWrite))
 
 	// 2. Bebaskan Blok Lama yang Mungkin Digunakan File Ini (Mode Overwrite)
-	//    fileEntry.StartBlock menyimpan blok pertama dari data file lama.
+	//    Blok lama dibebaskan lewat Allocator disk (rantai FAT atau blok indeks, lihat alloc.go).
 	//    Jika fileEntry.StartBlock adalah FAT_FREE atau FAT_EOF, berarti file belum punya blok data.
-	if fileEntry.StartBlock != FAT_FREE && fileEntry.StartBlock != FAT_EOF {
-		// fmt.Printf("Membebaskan blok lama dari file '%s' mulai dari blok %d.\n", fileNameForLog, fileEntry.StartBlock)
-		err := freeBlockChain(fileEntry.StartBlock)
-		if err != nil {
-			return fmt.Errorf("gagal membebaskan blok lama file '%s': %w", fileNameForLog, err)
-		}
+	if err := fs.freeFileBlocks(*fileEntry); err != nil {
+		return fmt.Errorf("gagal membebaskan blok lama file '%s': %w", fileNameForLog, err)
 	}
 	fileEntry.StartBlock = FAT_EOF // Reset StartBlock, akan diisi jika ada data
 	fileEntry.Size = 0             // Reset Size
@@ -681,8 +737,9 @@ func WriteToFile(fileEntry *DirectoryEntry, parentDirStartBlock BlockID, dataToW
 		fmt.Printf("Tidak ada data untuk ditulis ke '%s'. File akan menjadi kosong.\n", fileNameForLog)
 		// StartBlock sudah FAT_EOF, Size sudah 0. Tinggal update ModTime.
 		fileEntry.ModTime = time.Now().UnixNano()
+		fileEntry.ChangeTime = fileEntry.ModTime
 		// Update entri ini di direktori induknya
-		errUpdate := updateEntryInDirectory(parentDirStartBlock, *fileEntry)
+		errUpdate := fs.updateEntryInDirectory(parentDirStartBlock, *fileEntry)
 		if errUpdate != nil {
 			return fmt.Errorf("gagal update entri untuk file kosong '%s' di direktori induk: %w", fileNameForLog, errUpdate)
 		}
@@ -691,71 +748,39 @@ func WriteToFile(fileEntry *DirectoryEntry, parentDirStartBlock BlockID, dataToW
 
 	// 4. Hitung Jumlah Blok yang Dibutuhkan
 	//    (panjang_data + ukuran_blok - 1) / ukuran_blok  (integer division untuk pembulatan ke atas)
-	numBlocksNeeded := (len(dataToWrite) + BLOCK_SIZE - 1) / BLOCK_SIZE
+	numBlocksNeeded := (len(dataToWrite) + fs.Geometry.BlockSize - 1) / fs.Geometry.BlockSize
 	// fmt.Printf("Data membutuhkan %d blok.\n", numBlocksNeeded)
 
-	var allocatedBlocks []BlockID        // Untuk menyimpan daftar blok yang berhasil dialokasikan
-	var firstBlockOfFile = FAT_EOF       // Akan diisi dengan blok pertama yang dialokasikan
-	var previousAllocatedBlock = FAT_EOF // Untuk chaining di FAT
-
-	// 5. Alokasikan Blok Baru dan Tulis Data per Blok
-	for i := 0; i < numBlocksNeeded; i++ {
-		newBlock, err := findFreeBlock()
-		if err != nil {
-			// Gagal alokasi blok. Perlu rollback: bebaskan semua blok yang sudah dialokasikan di loop ini.
-			for _, allocatedBlock := range allocatedBlocks {
-				FAT[allocatedBlock] = FAT_FREE
-			}
-			return fmt.Errorf("disk penuh saat mencoba alokasi blok ke-%d untuk file '%s': %w", i+1, fileNameForLog, err)
-		}
-
-		FAT[newBlock] = FAT_EOF // Awalnya, setiap blok baru adalah EOF sampai ada blok berikutnya
-		allocatedBlocks = append(allocatedBlocks, newBlock)
-		// fmt.Printf("Blok %d dialokasikan untuk file '%s'.\n", newBlock, fileNameForLog)
-
-		if i == 0 {
-			firstBlockOfFile = newBlock
-		}
-
-		if previousAllocatedBlock != FAT_EOF {
-			FAT[previousAllocatedBlock] = newBlock // Hubungkan blok sebelumnya ke blok baru ini
-		}
-		previousAllocatedBlock = newBlock
-
+	// 5. Alokasikan Blok Baru lewat Allocator Disk, lalu Tulis Data per Blok
+	//    Allocator mengisi fileEntry.StartBlock dan memberikan blok-blok baru (sudah berisi nol) secara berurutan.
+	//    Jika disk penuh di tengah jalan, blok yang terlanjur dialokasikan sudah dibebaskan lagi olehnya.
+	allocatedBlocks, err := fs.allocator().Resize(fileEntry, numBlocksNeeded)
+	if err != nil {
+		// Blok lama sudah dibebaskan di langkah 2: catat file sebagai kosong agar entrinya tidak menunjuk blok bebas
+		fs.updateEntryInDirectory(parentDirStartBlock, *fileEntry)
+		return fmt.Errorf("gagal mengalokasikan %d blok untuk file '%s': %w", numBlocksNeeded, fileNameForLog, err)
+	}
+	for i, newBlock := range allocatedBlocks {
 		// Tentukan bagian data yang akan ditulis ke blok ini
-		startByte := i * BLOCK_SIZE
-		endByte := (i + 1) * BLOCK_SIZE
+		startByte := i * fs.Geometry.BlockSize
+		endByte := (i + 1) * fs.Geometry.BlockSize
 		if endByte > len(dataToWrite) {
 			endByte = len(dataToWrite)
 		}
-		dataChunk := dataToWrite[startByte:endByte]
-
-		// Salin dataChunk ke Disk[newBlock]
-		// Pastikan blok di-clear dulu jika ada sisa data lama (meskipun findFreeBlock seharusnya mengembalikan blok "bersih")
-		// copy sudah menimpa, jadi tidak perlu clear manual jika blok baru.
-		copy(Disk[newBlock][:len(dataChunk)], dataChunk) // Hanya salin sejumlah dataChunk
-		// Jika len(dataChunk) < BLOCK_SIZE, sisa Disk[newBlock] akan tetap 0 (jika blok baru) atau data lama (jika blok dipakai ulang).
-		// Untuk keamanan, kita bisa clear sisa bloknya:
-		if len(dataChunk) < BLOCK_SIZE {
-			for k := len(dataChunk); k < BLOCK_SIZE; k++ {
-				Disk[newBlock][k] = 0 // Set sisa byte di blok menjadi 0
-			}
-		}
-		// fmt.Printf("%d bytes ditulis ke blok %d.\n", len(dataChunk), newBlock)
+		// Sisa blok terakhir sudah bernilai 0 karena blok baru selalu dinolkan Allocator
+		copy(fs.Disk[newBlock], dataToWrite[startByte:endByte])
 	}
 
 	// 6. Update Informasi di DirectoryEntry file
-	fileEntry.StartBlock = firstBlockOfFile
 	fileEntry.Size = int64(len(dataToWrite))
 	fileEntry.ModTime = time.Now().UnixNano()
+	fileEntry.ChangeTime = fileEntry.ModTime
 
 	// 7. Tulis Ulang (Update) DirectoryEntry yang Sudah Diperbarui ke Direktori Induk
-	errUpdate := updateEntryInDirectory(parentDirStartBlock, *fileEntry)
+	errUpdate := fs.updateEntryInDirectory(parentDirStartBlock, *fileEntry)
 	if errUpdate != nil {
 		// Gagal update entri di induk. Perlu rollback: bebaskan semua blok yang baru dialokasikan.
-		for _, allocatedBlock := range allocatedBlocks {
-			FAT[allocatedBlock] = FAT_FREE
-		}
+		fs.freeFileBlocks(*fileEntry)
 		return fmt.Errorf("gagal update entri file '%s' di direktori induk setelah menulis data: %w", fileNameForLog, errUpdate)
 	}
 
@@ -766,13 +791,16 @@ func WriteToFile(fileEntry *DirectoryEntry, parentDirStartBlock BlockID, dataToW
 // ReadFromFile: Membaca seluruh konten data dari sebuah file.
 // Input: fileEntry adalah DirectoryEntry dari file yang ingin dibaca.
 // Output: Slice byte yang berisi data file, dan error jika ada.
-func ReadFromFile(fileEntry DirectoryEntry) ([]byte, error) {
+func (fs *FileSystem) ReadFromFile(fileEntry DirectoryEntry) ([]byte, error) {
 	// 1. Validasi Awal
-	if fileEntry.Type != TYPE_FILE {
-		return nil, errors.New("hanya bisa membaca dari entri bertipe FILE")
+	if fileEntry.Type == TYPE_DIRECTORY { // FILE atau SYMLINK (isi symlink adalah path tujuannya)
+		return nil, errors.New("hanya bisa membaca dari entri bertipe FILE atau SYMLINK")
 	}
 
-	fileNameForLog := string(fileEntry.Name[:bytes.IndexByte(fileEntry.Name[:], 0)])
+	fileNameForLog := fileEntry.NameString()
+	if err := fs.checkAccess("read", fileNameForLog, fileEntry, ACCESS_READ); err != nil {
+		return nil, err
+	}
 	// fmt.Printf("Membaca dari file '%s'. Ukuran diharapkan: %d bytes, StartBlock: %d.\n",
 	// 	fileNameForLog, fileEntry.Size, fileEntry.StartBlock)
 
@@ -784,7 +812,7 @@ func ReadFromFile(fileEntry DirectoryEntry) ([]byte, error) {
 		return []byte{}, nil // File kosong, tidak ada data untuk dibaca
 	}
 	if fileEntry.StartBlock == FAT_EOF || fileEntry.StartBlock == FAT_FREE ||
-		fileEntry.StartBlock < 0 || fileEntry.StartBlock >= BlockID(TOTAL_BLOCKS) {
+		fileEntry.StartBlock < 0 || fileEntry.StartBlock >= BlockID(fs.Geometry.TotalBlocks) {
 		// Jika StartBlock tidak valid tapi size > 0, ini kondisi aneh/inkonsisten.
 		// Tapi untuk kasus umum file kosong yang StartBlock-nya FAT_EOF/FAT_FREE, ini benar.
 		// fmt.Printf("File '%s' tidak memiliki blok data yang dialokasikan (StartBlock=%d).\n", fileNameForLog, fileEntry.StartBlock)
@@ -797,27 +825,29 @@ func ReadFromFile(fileEntry DirectoryEntry) ([]byte, error) {
 	// 3. Siapkan Buffer untuk Menampung Data Hasil Baca
 	//    Kita gunakan bytes.Buffer untuk menggabungkan data dari beberapa blok.
 	var fileDataBuffer bytes.Buffer
-	bytesToRead := fileEntry.Size        // Berapa banyak byte lagi yang perlu kita baca
-	currentBlock := fileEntry.StartBlock // Mulai dari blok pertama file
-
-	// 4. Iterasi Melalui Rantai Blok File di FAT
-	for bytesToRead > 0 && currentBlock != FAT_EOF && currentBlock != FAT_FREE {
-		// a. Validasi currentBlock (keamanan tambahan)
-		if currentBlock < 0 || currentBlock >= BlockID(TOTAL_BLOCKS) {
-			return nil, fmt.Errorf("ditemukan nomor blok tidak valid (%d) saat membaca file '%s'", currentBlock, fileNameForLog)
+	bytesToRead := fileEntry.Size // Berapa banyak byte lagi yang perlu kita baca
+
+	// 4. Iterasi Melalui Blok-Blok Data File (urutannya dari Allocator disk: rantai FAT atau blok indeks)
+	blocks, err := fs.allocator().Blocks(fileEntry)
+	if err != nil {
+		return nil, fmt.Errorf("gagal membaca daftar blok file '%s': %w", fileNameForLog, err)
+	}
+	for _, currentBlock := range blocks {
+		if bytesToRead <= 0 {
+			break
 		}
 
-		// b. Ambil data byte dari blok disk saat ini.
-		blockData := Disk[currentBlock]
+		// a. Ambil data byte dari blok disk saat ini.
+		blockData := fs.Disk[currentBlock]
 
-		// c. Tentukan berapa banyak byte yang akan dibaca dari blok ini.
-		//    Bisa jadi sisa bytesToRead lebih kecil dari BLOCK_SIZE (jika ini blok terakhir).
-		chunkSize := int64(BLOCK_SIZE)
+		// b. Tentukan berapa banyak byte yang akan dibaca dari blok ini.
+		//    Bisa jadi sisa bytesToRead lebih kecil dari fs.Geometry.BlockSize (jika ini blok terakhir).
+		chunkSize := int64(fs.Geometry.BlockSize)
 		if bytesToRead < chunkSize {
 			chunkSize = bytesToRead
 		}
 
-		// d. Tulis bagian data dari blok ini ke buffer hasil.
+		// c. Tulis bagian data dari blok ini ke buffer hasil.
 		//    Kita hanya mengambil sebanyak chunkSize dari blockData.
 		_, err := fileDataBuffer.Write(blockData[:chunkSize])
 		if err != nil {
@@ -826,18 +856,15 @@ func ReadFromFile(fileEntry DirectoryEntry) ([]byte, error) {
 		}
 		// fmt.Printf("Membaca %d bytes dari blok %d untuk file '%s'.\n", chunkSize, currentBlock, fileNameForLog)
 
-		// e. Update jumlah byte yang masih harus dibaca.
+		// d. Update jumlah byte yang masih harus dibaca.
 		bytesToRead -= chunkSize
-
-		// f. Ambil nomor blok berikutnya dari FAT.
-		currentBlock = FAT[currentBlock]
-	} // Akhir dari loop 'for bytesToRead > 0 ...'
+	} // Akhir dari loop blok data
 
 	// 5. Validasi Akhir: Apakah kita sudah membaca semua byte sesuai ukuran file?
 	if bytesToRead > 0 {
-		// Ini berarti rantai FAT berhenti (EOF atau FREE) sebelum kita selesai membaca semua data
+		// Ini berarti blok file habis sebelum kita selesai membaca semua data
 		// sesuai dengan fileEntry.Size. Ini menandakan ada korupsi/inkonsistensi.
-		fmt.Printf("Warning: File '%s' mungkin terpotong. Diharapkan %d bytes, tapi rantai FAT berhenti saat sisa %d bytes.\n",
+		fmt.Printf("Warning: File '%s' mungkin terpotong. Diharapkan %d bytes, tapi blok file habis saat sisa %d bytes.\n",
 			fileNameForLog, fileEntry.Size, bytesToRead)
 		// Tergantung kebijakan, kita bisa kembalikan error atau data yang sudah terbaca sejauh ini.
 		// Kita kembalikan yang sudah terbaca.
@@ -849,77 +876,28 @@ func ReadFromFile(fileEntry DirectoryEntry) ([]byte, error) {
 }
 
 // invalidateEntryInParent: Menemukan entri dengan nama tertentu di direktori induk
-// dan menandainya sebagai tidak valid/dihapus dengan mengubah Name[0] menjadi 0.
-func invalidateEntryInParent(parentDirStartBlock BlockID, entryNameToInvalidate string) error {
-	if parentDirStartBlock < 0 || parentDirStartBlock >= BlockID(TOTAL_BLOCKS) || FAT[parentDirStartBlock] == FAT_FREE {
+// dan menandainya sebagai tidak valid/dihapus dengan mengubah byte pertama slotnya (dan slot LFN-nya) menjadi 0.
+func (fs *FileSystem) invalidateEntryInParent(parentDirStartBlock BlockID, entryNameToInvalidate string) error {
+	if parentDirStartBlock < 0 || parentDirStartBlock >= BlockID(fs.Geometry.TotalBlocks) || fs.FAT[parentDirStartBlock] == FAT_FREE {
 		return errors.New("blok awal direktori induk tidak valid atau belum dialokasikan untuk invalidasi")
 	}
 
-	// Iterasi melalui rantai blok direktori induk
-	currentBlock := parentDirStartBlock
-	entryFoundAndInvalidated := false
-
-	for currentBlock != FAT_EOF && currentBlock != FAT_FREE {
-		if currentBlock < 0 || currentBlock >= BlockID(TOTAL_BLOCKS) {
-			return fmt.Errorf("nomor blok tidak valid (%d) dalam rantai direktori induk saat invalidasi", currentBlock)
-		}
-
-		blockData := Disk[currentBlock] // Ambil data dari blok saat ini
-
-		// Cari entri di dalam blok ini
-		for offset := 0; offset+DIRECTORY_ENTRY_SIZE <= BLOCK_SIZE; offset += DIRECTORY_ENTRY_SIZE {
-			entryDataSlice := blockData[offset : offset+DIRECTORY_ENTRY_SIZE] // Ini slice, jadi modifikasi akan ke Disk
-
-			if entryDataSlice[0] == 0 { // Slot sudah kosong, tidak mungkin ini entri yang kita cari
-				continue // Lanjut ke slot berikutnya
-			}
-
-			// Kita perlu deserialize sementara untuk cek nama, meskipun kita hanya akan modifikasi byte Name[0]
-			// Ini agak tidak efisien, tapi paling mudah untuk sekarang.
-			// Alternatif: langsung bandingkan byte nama.
-			tempEntry, errDeserialize := DeserializeEntry(entryDataSlice)
-			if errDeserialize != nil {
-				// Abaikan entri yang rusak
-				continue
-			}
-
-			currentEntryName := string(tempEntry.Name[:bytes.IndexByte(tempEntry.Name[:], 0)])
-
-			if currentEntryName == entryNameToInvalidate {
-				// Ditemukan entri yang cocok! Invalidate dengan set Name[0] = 0.
-				// Kita modifikasi langsung slice yang merujuk ke Disk.
-				Disk[currentBlock][offset] = 0 // Byte pertama dari nama di-set 0
-				// Atau, jika ingin lebih "bersih" terhadap seluruh field nama (opsional):
-				// for k := 0; k < MAX_FILENAME_LEN; k++ {
-				// 	Disk[currentBlock][offset+k] = 0
-				// }
-
-				fmt.Printf("Entri '%s' diinvalidaasi dari blok %d direktori induk, offset %d.\n",
-					entryNameToInvalidate, currentBlock, offset)
-				entryFoundAndInvalidated = true
-				// Kita bisa 'return nil' di sini jika yakin nama unik.
-				// Atau lanjutkan loop jika ada kemungkinan nama duplikat (seharusnya tidak ada di desain kita).
-				// Untuk sekarang, kita anggap nama unik dan langsung keluar setelah invalidasi.
-				// Jika direktori bisa multi-blok, kita mungkin perlu 'goto endInvalidateLoop' setelah ini.
-				// Tapi karena kita akan return, tidak masalah.
-				return nil
-			}
-		}
-		// Pindah ke blok berikutnya dari direktori induk
-		currentBlock = FAT[currentBlock]
-	}
-
-	if !entryFoundAndInvalidated {
-		return fmt.Errorf("entri dengan nama '%s' tidak ditemukan di direktori induk untuk diinvalidasi", entryNameToInvalidate)
+	// Cari entri beserta slot LFN-nya, lalu kosongkan semuanya (byte pertama setiap slot di-set 0)
+	rec, err := fs.findRecord(parentDirStartBlock, entryNameToInvalidate)
+	if err != nil {
+		return fmt.Errorf("entri dengan nama '%s' tidak ditemukan di direktori induk untuk diinvalidasi: %w", entryNameToInvalidate, err)
 	}
-	return nil // Seharusnya sudah return di dalam loop jika ketemu
+	fs.clearRecord(rec)
+	fmt.Printf("Entri '%s' diinvalidasi dari blok %d direktori induk, offset %d (%d slot LFN).\n",
+		entryNameToInvalidate, rec.Slot.Block, rec.Slot.Offset, len(rec.LFNSlots))
+	return nil
 }
 
 // filesystem_logic.go
 // (Lanjutan dari kode sebelumnya)
 
 // DeleteEntry: Menghapus file atau direktori (kosong).
-func DeleteEntry(parentDirStartBlock BlockID, entryName string) error {
+func (fs *FileSystem) DeleteEntry(parentDirStartBlock BlockID, entryName string) error {
 	// 1. Validasi Nama
 	if len(entryName) == 0 {
 		return errors.New("nama entri untuk dihapus tidak boleh kosong")
@@ -928,8 +906,13 @@ func DeleteEntry(parentDirStartBlock BlockID, entryName string) error {
 		return errors.New("tidak dapat menghapus entri '.' atau '..'")
 	}
 
+	// 1b. Menghapus entri butuh izin tulis dan eksekusi (w, x) pada direktori induk
+	if err := fs.checkDirAccess("delete", parentDirStartBlock, ACCESS_WRITE|ACCESS_EXEC); err != nil {
+		return err
+	}
+
 	// 2. Cari Entri yang Akan Dihapus di Direktori Induk
-	parentEntries, err := ListEntries(parentDirStartBlock)
+	parentEntries, err := fs.listEntries(parentDirStartBlock)
 	if err != nil {
 		return fmt.Errorf("gagal membaca direktori induk saat mencari entri '%s': %w", entryName, err)
 	}
@@ -937,8 +920,7 @@ func DeleteEntry(parentDirStartBlock BlockID, entryName string) error {
 	var entryToDelete DirectoryEntry
 	found := false
 	for _, entry := range parentEntries {
-		currentEntryName := string(entry.Name[:bytes.IndexByte(entry.Name[:], 0)])
-		if currentEntryName == entryName {
+		if entry.matchesName(entryName) {
 			entryToDelete = entry // Salin structnya
 			found = true
 			break
@@ -950,12 +932,24 @@ func DeleteEntry(parentDirStartBlock BlockID, entryName string) error {
 	}
 
 	// 3. Proses Berdasarkan Tipe Entri
-	if entryToDelete.Type == TYPE_FILE {
-		// Jika file, bebaskan rantai blok datanya
-		fmt.Printf("Menghapus file '%s'. Membebaskan blok mulai dari %d.\n", entryName, entryToDelete.StartBlock)
-		err = freeBlockChain(entryToDelete.StartBlock)
-		if err != nil {
-			return fmt.Errorf("gagal membebaskan blok data file '%s': %w", entryName, err)
+	//    (pada disk dengan tabel inode, inode entri baru dibebaskan setelah slotnya dikosongkan di langkah 4)
+	slot, _, errSlot := fs.findEntrySlot(parentDirStartBlock, entryName)
+	releaseInode := false
+	if entryToDelete.Type == TYPE_FILE || entryToDelete.Type == TYPE_SYMLINK {
+		if entryToDelete.hasLinks() {
+			// Masih ada hard link lain: blok data tetap dipakai, link count dikurangi setelah entri dihapus (langkah 4)
+			fmt.Printf("'%s' masih punya %d hard link lain, blok datanya tidak dibebaskan.\n", entryName, entryToDelete.Nlink-1)
+		} else if errSlot == nil && fs.FileTable.unlinkIfOpen(fs, slot) {
+			// Jika file masih terbuka, bloknya baru dibebaskan saat handle terakhir ditutup (aturan unlink Unix)
+			fmt.Printf("File '%s' masih terbuka, blok datanya dibebaskan setelah handle terakhir ditutup.\n", entryName)
+		} else {
+			// Jika file, bebaskan rantai blok datanya
+			fmt.Printf("Menghapus file '%s'. Membebaskan blok mulai dari %d.\n", entryName, entryToDelete.StartBlock)
+			err = fs.freeFileBlocks(entryToDelete)
+			if err != nil {
+				return fmt.Errorf("gagal membebaskan blok data file '%s': %w", entryName, err)
+			}
+			releaseInode = true
 		}
 		// Set StartBlock ke FAT_FREE atau FAT_EOF untuk menandakan tidak ada blok lagi
 		// Ini tidak perlu karena entri akan diinvalidasi. Metadata lama tidak masalah.
@@ -965,40 +959,24 @@ func DeleteEntry(parentDirStartBlock BlockID, entryName string) error {
 
 		// Pastikan StartBlock direktori yang akan dihapus itu valid sebelum ListEntries
 		if entryToDelete.StartBlock == FAT_FREE || entryToDelete.StartBlock == FAT_EOF ||
-			entryToDelete.StartBlock < 0 || entryToDelete.StartBlock >= BlockID(TOTAL_BLOCKS) {
+			entryToDelete.StartBlock < 0 || entryToDelete.StartBlock >= BlockID(fs.Geometry.TotalBlocks) {
 			// Ini kasus aneh, direktori tanpa blok data yang valid. Anggap "kosong" dan bisa dihapus entrinya.
 			fmt.Printf("Direktori '%s' tidak memiliki blok data valid, dianggap kosong.\n", entryName)
 		} else {
-			subEntries, errListSub := ListEntries(entryToDelete.StartBlock)
+			subEntries, errListSub := fs.listEntries(entryToDelete.StartBlock)
 			if errListSub != nil {
 				return fmt.Errorf("gagal membaca isi direktori '%s' untuk pemeriksaan kekosongan: %w", entryName, errListSub)
 			}
 			// --- TAMBAHKAN DEBUG PRINT DI SINI ---
 			fmt.Printf("DEBUG: Isi subEntries untuk '%s':\n", entryName)
 			for k, se := range subEntries {
-				seNameIdx := bytes.IndexByte(se.Name[:], 0)
-				var seNameStr string
-				if seNameIdx == -1 {
-					seNameStr = string(se.Name[:])
-				} else {
-					seNameStr = string(se.Name[:seNameIdx])
-				}
-				fmt.Printf("  SubEntri %d: Nama='%s', Tipe=%d\n", k, seNameStr, se.Type)
+				fmt.Printf("  SubEntri %d: Nama='%s', Tipe=%d\n", k, se.NameString(), se.Type)
 			}
 			// --- AKHIR DEBUG PRINT ---			// Direktori kosong jika hanya ada "." dan ".." atau tidak ada sama sekali (seharusnya minimal . dan .. jika sudah diinisialisasi)
 			// Kita hitung entri yang BUKAN "." atau ".."
 			realEntryCount := 0
 			for _, subEntry := range subEntries {
-				// Ambil nama dengan aman (INI BAGIAN YANG DIPERBAIKI)
-				idx := bytes.IndexByte(subEntry.Name[:], 0)
-				var subEntryName string
-				if idx == -1 { // Jika tidak ada null byte (nama mengisi seluruh array)
-					// Ini seharusnya tidak terjadi jika MAX_FILENAME_LEN > panjang nama aktual seperti "." atau ".."
-					// Tapi sebagai pengaman:
-					subEntryName = string(subEntry.Name[:])
-				} else {
-					subEntryName = string(subEntry.Name[:idx])
-				}
+				subEntryName := subEntry.ShortName() // "." dan ".." tidak pernah punya nama panjang
 
 				if subEntryName != "." && subEntryName != ".." {
 					realEntryCount++
@@ -1015,82 +993,70 @@ func DeleteEntry(parentDirStartBlock BlockID, entryName string) error {
 
 		// Jika direktori kosong (atau dianggap kosong), bebaskan blok datanya
 		fmt.Printf("Direktori '%s' kosong. Membebaskan blok mulai dari %d.\n", entryName, entryToDelete.StartBlock)
-		err = freeBlockChain(entryToDelete.StartBlock)
+		err = fs.freeBlockChain(entryToDelete.StartBlock)
 		if err != nil {
 			return fmt.Errorf("gagal membebaskan blok data direktori '%s': %w", entryName, err)
 		}
+		releaseInode = true
 	} else {
 		return fmt.Errorf("tipe entri tidak dikenal untuk '%s'", entryName)
 	}
 
 	// 4. Invalidate/Hapus Entri dari Direktori Induk
-	err = invalidateEntryInParent(parentDirStartBlock, entryName)
+	err = fs.invalidateEntryInParent(parentDirStartBlock, entryName)
 	if err != nil {
 		// Jika gagal menginvalidasi dari induk, ini masalah.
 		// Blok data mungkin sudah terlanjur dibebaskan. Idealnya ada mekanisme transaksi/rollback yang lebih baik.
 		// Untuk sekarang, kita kembalikan errornya.
 		return fmt.Errorf("berhasil membebaskan blok data untuk '%s', TAPI gagal menginvalidasi entri dari direktori induk: %w", entryName, err)
 	}
+	if releaseInode {
+		fs.freeInode(entryToDelete.Inode)
+	}
+	if entryToDelete.hasLinks() && errSlot == nil {
+		if err := fs.dropLink(entryToDelete, slot); err != nil {
+			return fmt.Errorf("gagal memperbarui link count '%s': %w", entryName, err)
+		}
+	}
+
+	// 5. Lepaskan blok direktori induk yang sekarang kosong agar rantainya menyusut lagi
+	if err := fs.compactDirectory(parentDirStartBlock); err != nil {
+		fmt.Printf("Warning: Gagal memadatkan direktori induk (Blok %d): %v\n", parentDirStartBlock, err)
+	}
+	fs.touchDirectory(parentDirStartBlock)
 
 	fmt.Printf("Entri '%s' berhasil dihapus.\n", entryName)
 	return nil
 }
 
 // ChangeDirectory: Mengubah direktori kerja saat ini (CurrentDirectoryBlock) di FileSystem.
-// Menerima pointer ke FileSystem agar bisa memodifikasinya.
-func ChangeDirectory(fs *FileSystem, targetName string) error {
-	if fs == nil {
-		return errors.New("FileSystem instance tidak boleh nil")
-	}
-
-	// 1. Handle kasus khusus targetName
-	if targetName == "/" { // Pindah ke root directory
-		fs.CurrentDirectoryBlock = ROOT_DIR_BLOCK
-		fmt.Printf("Direktori diubah ke root (Blok %d).\n", fs.CurrentDirectoryBlock)
-		return nil
-	}
-
-	if targetName == "." { // Direktori saat ini, tidak ada perubahan
-		// fmt.Printf("Direktori tetap di Blok %d.\n", fs.CurrentDirectoryBlock)
-		return nil
+// targetPath boleh satu nama ("docs", ".."), path relatif ("../a/b"), maupun path absolut ("/a/b").
+// Jika salah satu komponen tidak ada atau bukan direktori, direktori kerja tidak berubah.
+func (fs *FileSystem) ChangeDirectory(targetPath string) error {
+	// 1. Pecah path menjadi komponen (path absolut mulai dari root, relatif dari direktori saat ini)
+	startBlock, components, err := fs.splitPath(targetPath)
+	if err != nil {
+		return err
 	}
 
-	// 2. Ambil semua entri dari direktori saat ini untuk mencari targetName
-	currentEntries, err := ListEntries(fs.CurrentDirectoryBlock)
+	// 2. Telusuri setiap komponen; ".." mengikuti entri ".." sehingga di root tetap di root
+	targetBlock, err := fs.walkComponents(startBlock, components, new(int))
 	if err != nil {
-		return fmt.Errorf("gagal membaca direktori saat ini (Blok %d) untuk cd: %w", fs.CurrentDirectoryBlock, err)
-	}
-
-	// 3. Cari targetName (baik itu ".." atau nama direktori spesifik)
-	var targetEntry *DirectoryEntry // Gunakan pointer agar bisa nil jika tidak ketemu
-	for i, entry := range currentEntries {
-		entryNameStr := string(entry.Name[:bytes.IndexByte(entry.Name[:], 0)])
-		if entryNameStr == targetName {
-			// Periksa apakah ini direktori (untuk nama biasa atau "..")
-			if entry.Type == TYPE_DIRECTORY {
-				targetEntry = &currentEntries[i] // Ambil alamat dari elemen slice
-				break
-			} else if targetName != ".." { // Jika nama biasa tapi bukan direktori
-				return fmt.Errorf("'%s' bukan direktori", targetName)
-			}
-            // Jika targetName adalah ".." dan tipenya bukan direktori, itu aneh, tapi ListEntries harusnya hanya return dir untuk ".."
-		}
+		return fmt.Errorf("gagal pindah ke '%s': %w", targetPath, err)
 	}
 
-	// 4. Proses hasil pencarian
-	if targetEntry == nil {
-		return fmt.Errorf("direktori '%s' tidak ditemukan di direktori saat ini (Blok %d)", targetName, fs.CurrentDirectoryBlock)
+	// 3. Pastikan blok tujuan valid sebelum mengubah CurrentDirectoryBlock
+	if targetBlock < 0 || targetBlock >= BlockID(fs.Geometry.TotalBlocks) || fs.FAT[targetBlock] == FAT_FREE {
+		// Ini seharusnya tidak terjadi kecuali metadata korup.
+		return fmt.Errorf("StartBlock untuk direktori tujuan '%s' (Blok %d) tidak valid atau belum dialokasikan", targetPath, targetBlock)
 	}
 
-	// Jika ditemukan dan merupakan direktori, ubah CurrentDirectoryBlock
-	// targetEntry.StartBlock adalah blok awal dari direktori tujuan (baik itu ".." atau nama direktori lain)
-	if targetEntry.StartBlock < 0 || targetEntry.StartBlock >= BlockID(TOTAL_BLOCKS) || FAT[targetEntry.StartBlock] == FAT_FREE {
-		// Ini seharusnya tidak terjadi jika entri valid, kecuali untuk ".." di root yang StartBlock-nya ROOT_DIR_BLOCK
-        // atau jika metadata korup.
-		return fmt.Errorf("StartBlock untuk direktori tujuan '%s' (Blok %d) tidak valid atau belum dialokasikan", targetName, targetEntry.StartBlock)
+	// 4. Masuk ke direktori butuh izin eksekusi (x) pada direktori tujuan
+	if err := fs.checkDirAccess("chdir", targetBlock, ACCESS_EXEC); err != nil {
+		return err
 	}
 
-	fs.CurrentDirectoryBlock = targetEntry.StartBlock
-	fmt.Printf("Direktori diubah ke '%s' (Blok %d).\n", targetName, fs.CurrentDirectoryBlock)
+	fs.CurrentDirectoryBlock = targetBlock
+	fmt.Printf("Direktori diubah ke '%s' (Blok %d).\n", targetPath, fs.CurrentDirectoryBlock)
 	return nil
 }
\ No newline at end of file
@@ -0,0 +1,71 @@
+package filesystem_logic
+
+import (
+	"bytes"
+	"testing"
+)
+
+// newTestDisk: Disk baru dengan geometri g (field 0 memakai nilai bawaan).
+func newTestDisk(t *testing.T, g Geometry) *FileSystem {
+	t.Helper()
+	fs, err := NewFileSystem(FileSystemOptions{Geometry: g})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return fs
+}
+
+// writeTestFile: Membuat file name di root dan mengisinya dengan data.
+func writeTestFile(t *testing.T, fs *FileSystem, name string, data []byte) {
+	t.Helper()
+	if err := fs.CreateFile(fs.RootDirBlock, name); err != nil {
+		t.Fatal(err)
+	}
+	entry, err := fs.findEntry(fs.RootDirBlock, name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := fs.WriteToFile(&entry, fs.RootDirBlock, data); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// checkTestFile: Isi file name di root harus sama dengan want.
+func checkTestFile(t *testing.T, fs *FileSystem, name string, want []byte) {
+	t.Helper()
+	entry, err := fs.findEntry(fs.RootDirBlock, name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := fs.ReadFromFile(entry)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Fatalf("isi '%s' berbeda: %d byte, seharusnya %d byte", name, len(got), len(want))
+	}
+}
+
+// Dua disk yang dibuka bersamaan tidak saling memengaruhi: masing-masing punya geometri, FAT, dan isi sendiri.
+func TestFileSystemsAreIndependent(t *testing.T) {
+	a := newTestDisk(t, Geometry{})
+	b := newTestDisk(t, Geometry{BlockSize: 512, TotalBlocks: 64})
+	freeB := b.countFreeBlocks()
+
+	writeTestFile(t, a, "a.txt", make([]byte, 4*a.Geometry.BlockSize))
+	if b.countFreeBlocks() != freeB {
+		t.Fatalf("menulis ke disk A mengubah blok kosong disk B: %d -> %d", freeB, b.countFreeBlocks())
+	}
+	if _, err := b.findEntry(b.RootDirBlock, "a.txt"); err == nil {
+		t.Fatal("file di disk A terlihat di disk B")
+	}
+
+	writeTestFile(t, b, "b.txt", []byte("disk b"))
+	if err := a.DeleteEntry(a.RootDirBlock, "a.txt"); err != nil {
+		t.Fatal(err)
+	}
+	checkTestFile(t, b, "b.txt", []byte("disk b"))
+	if a.Geometry.BlockSize != DEFAULT_BLOCK_SIZE || b.Geometry.BlockSize != 512 {
+		t.Fatalf("geometri tercampur: A %s, B %s", a.Geometry, b.Geometry)
+	}
+}
@@ -0,0 +1,351 @@
+// filetable.go
+package filesystem_logic
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// Model file terbuka mengikuti Unix, dengan tiga tingkat:
+//
+//	Process.fds (per proses)  ->  File (tabel file terbuka sistem)  ->  vnode (file di disk)
+//
+// - File descriptor hanyalah indeks di tabel milik satu proses yang menunjuk ke sebuah File.
+// - File menyimpan offset dan mode akses; Dup/Dup2 membuat descriptor baru ke File yang sama (offset dipakai bersama).
+// - vnode mewakili satu file di disk, dipakai bersama oleh semua File yang membuka file tersebut.
+//   Jika file dihapus selagi masih terbuka, entrinya hilang dari direktori tetapi bloknya baru
+//   dibebaskan setelah File terakhir ditutup.
+
+// MAX_FDS: Jumlah file descriptor maksimum per proses.
+const MAX_FDS = 16
+
+// ErrBadFD: File descriptor tidak valid atau tidak sedang dipakai.
+var ErrBadFD = errors.New("file descriptor tidak valid")
+
+// vnode: File di disk yang sedang dibuka. Diidentifikasi lewat lokasi slot entrinya di direktori induk.
+type vnode struct {
+	slot     dirSlot
+	entry    DirectoryEntry // Salinan entri; dibaca ulang dari slot sebelum dipakai selama file belum dihapus
+	opens    int            // Jumlah File yang menunjuk ke vnode ini
+	unlinked bool           // Entri sudah dihapus dari direktori, blok dibebaskan saat opens menjadi 0
+}
+
+// loadNode: Menyegarkan salinan entri dari slotnya (misalnya setelah WriteToFile dari GUI).
+// File yang sudah di-unlink tidak punya slot lagi, jadi salinannya dipakai apa adanya.
+func (fs *FileSystem) loadNode(node *vnode) error {
+	if node.unlinked {
+		return nil
+	}
+	entry, err := fs.readSlot(node.slot)
+	if err != nil {
+		return err
+	}
+	node.entry = entry
+	return nil
+}
+
+// storeNode: Menulis salinan entri kembali ke slotnya di direktori induk.
+func (fs *FileSystem) storeNode(node *vnode) error {
+	if node.unlinked {
+		return nil
+	}
+	return fs.writeSlot(node.slot, node.entry)
+}
+
+// FileTable: Tabel file terbuka sistem beserta daftar proses simulasi.
+type FileTable struct {
+	files      []*File // Entri tabel file terbuka, urut sesuai waktu dibuka
+	nodes      map[dirSlot]*vnode
+	processes  []*Process
+	nextFileID int
+	nextPID    int
+}
+
+func newFileTable() *FileTable {
+	return &FileTable{nodes: make(map[dirSlot]*vnode), nextFileID: 1, nextPID: 1}
+}
+
+// resetFileTable: Dipanggil saat disk diformat atau image baru dimuat. Semua handle dan proses
+// lama tidak berlaku lagi karena menunjuk ke disk yang sudah diganti.
+func (fs *FileSystem) resetFileTable() {
+	if fs.FileTable != nil {
+		for _, f := range fs.FileTable.files {
+			f.closed = true
+		}
+		for _, p := range fs.FileTable.processes {
+			p.exited = true
+		}
+	}
+	fs.FileTable = newFileTable()
+}
+
+// open: Menambahkan entri baru ke tabel file terbuka untuk file di slot.
+func (ft *FileTable) open(fs *FileSystem, slot dirSlot, path string, flags int) *File {
+	node, ok := ft.nodes[slot]
+	if !ok {
+		node = &vnode{slot: slot}
+		ft.nodes[slot] = node
+	}
+	node.opens++
+	f := &File{fs: fs, id: ft.nextFileID, node: node, path: path, flags: flags, refs: 1}
+	ft.nextFileID++
+	ft.files = append(ft.files, f)
+	return f
+}
+
+// release: Melepas satu referensi ke f. Jika itu referensi terakhir, f dihapus dari tabel, dan jika
+// f juga pembuka terakhir file yang sudah di-unlink, blok datanya dibebaskan.
+func (ft *FileTable) release(f *File) error {
+	f.refs--
+	if f.refs > 0 {
+		return nil
+	}
+	f.closed = true
+	for i, other := range ft.files {
+		if other == f {
+			ft.files = append(ft.files[:i], ft.files[i+1:]...)
+			break
+		}
+	}
+
+	node := f.node
+	node.opens--
+	if node.opens > 0 {
+		return nil
+	}
+	if ft.nodes[node.slot] == node { // Slot file yang sudah di-unlink bisa saja sudah dipakai file lain
+		delete(ft.nodes, node.slot)
+	}
+	if node.unlinked {
+		fmt.Printf("Handle terakhir '%s' ditutup, blok file yang sudah dihapus dibebaskan mulai dari %d.\n", f.path, node.entry.StartBlock)
+		f.fs.freeInode(node.entry.Inode) // Inode file yang sudah di-unlink juga baru bebas sekarang
+		return f.fs.freeFileBlocks(node.entry)
+	}
+	return nil
+}
+
+// unlinkIfOpen: Dipanggil DeleteEntry sebelum entri di slot dihapus. Jika file masih terbuka,
+// vnode-nya ditandai unlinked dan true dikembalikan agar DeleteEntry tidak membebaskan blok datanya.
+func (ft *FileTable) unlinkIfOpen(fs *FileSystem, slot dirSlot) bool {
+	node, ok := ft.nodes[slot]
+	if !ok {
+		return false
+	}
+	fs.loadNode(node) // Ambil entri terakhir sebelum slotnya dikosongkan
+	node.unlinked = true
+	delete(ft.nodes, slot) // Slot boleh dipakai file lain mulai sekarang
+	return true
+}
+
+// moveNode: Dipanggil Rename saat entri pindah ke slot lain, agar file yang sedang terbuka ikut pindah.
+func (ft *FileTable) moveNode(oldSlot, newSlot dirSlot) {
+	node, ok := ft.nodes[oldSlot]
+	if !ok {
+		return
+	}
+	delete(ft.nodes, oldSlot)
+	node.slot = newSlot
+	ft.nodes[newSlot] = node
+}
+
+// Process: Proses simulasi dengan tabel file descriptor sendiri.
+type Process struct {
+	PID    int
+	Name   string
+	fs     *FileSystem
+	fds    [MAX_FDS]*File
+	exited bool
+}
+
+// NewProcess: Membuat proses simulasi baru dengan tabel file descriptor kosong.
+func (fs *FileSystem) NewProcess(name string) *Process {
+	p := &Process{PID: fs.FileTable.nextPID, Name: name, fs: fs}
+	fs.FileTable.nextPID++
+	fs.FileTable.processes = append(fs.FileTable.processes, p)
+	return p
+}
+
+// Processes: Daftar proses yang masih berjalan.
+func (ft *FileTable) Processes() []*Process {
+	return append([]*Process(nil), ft.processes...)
+}
+
+// lowestFreeFD: Seperti Unix, descriptor baru selalu memakai nomor terkecil yang masih kosong.
+func (p *Process) lowestFreeFD() (int, error) {
+	for fd, f := range p.fds {
+		if f == nil {
+			return fd, nil
+		}
+	}
+	return -1, fmt.Errorf("proses %d (%s) kehabisan file descriptor (maks %d)", p.PID, p.Name, MAX_FDS)
+}
+
+// File: File di tabel file terbuka yang ditunjuk oleh fd.
+func (p *Process) File(fd int) (*File, error) {
+	if p.exited {
+		return nil, fmt.Errorf("proses %d sudah berhenti", p.PID)
+	}
+	if fd < 0 || fd >= MAX_FDS || p.fds[fd] == nil {
+		return nil, fmt.Errorf("fd %d: %w", fd, ErrBadFD)
+	}
+	return p.fds[fd], nil
+}
+
+// Open: Membuka file untuk proses ini dan mengembalikan file descriptor baru.
+func (p *Process) Open(path string, flags int) (int, error) {
+	if p.exited {
+		return -1, fmt.Errorf("proses %d sudah berhenti", p.PID)
+	}
+	fd, err := p.lowestFreeFD()
+	if err != nil {
+		return -1, err
+	}
+	f, err := p.fs.Open(path, flags)
+	if err != nil {
+		return -1, err
+	}
+	p.fds[fd] = f
+	return fd, nil
+}
+
+// Close: Menutup file descriptor. File di tabel sistem baru ditutup jika tidak ada descriptor lain yang menunjuknya.
+func (p *Process) Close(fd int) error {
+	f, err := p.File(fd)
+	if err != nil {
+		return err
+	}
+	p.fds[fd] = nil
+	return p.fs.FileTable.release(f)
+}
+
+// Dup: Membuat descriptor baru (nomor terkecil yang kosong) yang menunjuk ke File yang sama dengan fd.
+func (p *Process) Dup(fd int) (int, error) {
+	f, err := p.File(fd)
+	if err != nil {
+		return -1, err
+	}
+	newFD, err := p.lowestFreeFD()
+	if err != nil {
+		return -1, err
+	}
+	f.refs++
+	p.fds[newFD] = f
+	return newFD, nil
+}
+
+// Dup2: Membuat newFD menunjuk ke File yang sama dengan oldFD. Jika newFD sedang dipakai, ia ditutup dulu.
+// Jika oldFD == newFD, tidak ada yang berubah.
+func (p *Process) Dup2(oldFD, newFD int) (int, error) {
+	f, err := p.File(oldFD)
+	if err != nil {
+		return -1, err
+	}
+	if newFD < 0 || newFD >= MAX_FDS {
+		return -1, fmt.Errorf("fd %d: %w", newFD, ErrBadFD)
+	}
+	if oldFD == newFD {
+		return newFD, nil
+	}
+	if p.fds[newFD] != nil {
+		if err := p.Close(newFD); err != nil {
+			return -1, err
+		}
+	}
+	f.refs++
+	p.fds[newFD] = f
+	return newFD, nil
+}
+
+// Read, Write, Seek: Operasi I/O lewat file descriptor.
+func (p *Process) Read(fd int, buf []byte) (int, error) {
+	f, err := p.File(fd)
+	if err != nil {
+		return 0, err
+	}
+	return f.Read(buf)
+}
+
+func (p *Process) Write(fd int, data []byte) (int, error) {
+	f, err := p.File(fd)
+	if err != nil {
+		return 0, err
+	}
+	return f.Write(data)
+}
+
+func (p *Process) Seek(fd int, offset int64, whence int) (int64, error) {
+	f, err := p.File(fd)
+	if err != nil {
+		return 0, err
+	}
+	return f.Seek(offset, whence)
+}
+
+// Exit: Menutup semua descriptor milik proses lalu menghapusnya dari daftar proses.
+func (p *Process) Exit() {
+	if p.exited {
+		return
+	}
+	for fd := range p.fds {
+		if p.fds[fd] != nil {
+			p.Close(fd)
+		}
+	}
+	p.exited = true
+	ft := p.fs.FileTable
+	for i, other := range ft.processes {
+		if other == p {
+			ft.processes = append(ft.processes[:i], ft.processes[i+1:]...)
+			break
+		}
+	}
+}
+
+// OpenHandle: Satu baris di tampilan handle terbuka (GUI). Untuk File yang dibuka langsung
+// lewat FileSystem.Open tanpa proses, PID bernilai 0 dan FD bernilai -1.
+type OpenHandle struct {
+	PID      int
+	Process  string
+	FD       int
+	FileID   int // Nomor entri di tabel file terbuka; descriptor hasil Dup punya FileID yang sama
+	Path     string
+	Mode     string // Lihat AccessModeString
+	Offset   int64
+	Size     int64
+	Refs     int
+	Unlinked bool
+}
+
+// Handles: Semua handle yang sedang terbuka, diurutkan per proses lalu per fd (handle tanpa proses di akhir).
+func (ft *FileTable) Handles() []OpenHandle {
+	var handles []OpenHandle
+	owned := make(map[*File]bool)
+	row := func(f *File) OpenHandle {
+		return OpenHandle{FileID: f.id, Path: f.path, Mode: AccessModeString(f.flags), Offset: f.offset,
+			Size: f.Size(), Refs: f.refs, Unlinked: f.node.unlinked, FD: -1}
+	}
+	for _, p := range ft.processes {
+		for fd, f := range p.fds {
+			if f == nil {
+				continue
+			}
+			h := row(f)
+			h.PID, h.Process, h.FD = p.PID, p.Name, fd
+			handles = append(handles, h)
+			owned[f] = true
+		}
+	}
+	for _, f := range ft.files {
+		if !owned[f] {
+			handles = append(handles, row(f))
+		}
+	}
+	sort.SliceStable(handles, func(i, j int) bool {
+		if handles[i].PID != handles[j].PID {
+			return handles[j].PID == 0 || handles[i].PID != 0 && handles[i].PID < handles[j].PID
+		}
+		return handles[i].FD < handles[j].FD
+	})
+	return handles
+}
@@ -0,0 +1,151 @@
+package filesystem_logic
+
+import (
+	"errors"
+	"io"
+	"testing"
+)
+
+// Descriptor hasil Dup dan Dup2 berbagi offset dengan descriptor asalnya, sedangkan Open terpisah punya
+// offset sendiri. Descriptor baru selalu memakai nomor terkecil yang kosong.
+func TestDupSharesOffset(t *testing.T) {
+	fs := newTestDisk(t, Geometry{})
+	p := fs.NewProcess("sh")
+	fd, err := p.Open("/log", O_RDWR|O_CREATE)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dup, err := p.Dup(fd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fd != 0 || dup != 1 {
+		t.Fatalf("fd %d dan dup %d, seharusnya 0 dan 1", fd, dup)
+	}
+	p.Write(fd, []byte("abc"))
+	p.Write(dup, []byte("def")) // Melanjutkan offset fd, bukan menimpa dari awal
+	if data, _ := fs.ReadFile("/log"); string(data) != "abcdef" {
+		t.Fatalf("isi setelah menulis lewat fd dan dup: %q", data)
+	}
+
+	if _, err := p.Dup2(fd, 7); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := p.Seek(7, 1, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	buf := make([]byte, 2)
+	if n, _ := p.Read(fd, buf); string(buf[:n]) != "bc" {
+		t.Fatalf("Read lewat fd setelah Seek lewat fd 7: %q", buf[:n])
+	}
+
+	other, err := p.Open("/log", O_RDONLY)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if other != 2 {
+		t.Fatalf("Open berikutnya mendapat fd %d, seharusnya 2", other)
+	}
+	if n, _ := p.Read(other, buf); string(buf[:n]) != "ab" {
+		t.Fatalf("Open terpisah seharusnya mulai dari offset 0, dapat %q", buf[:n])
+	}
+
+	// Menutup fd asal tidak menutup File selama dup masih ada
+	if err := p.Close(fd); err != nil {
+		t.Fatal(err)
+	}
+	if n, err := p.Read(dup, buf); err != nil || string(buf[:n]) != "de" {
+		t.Fatalf("Read lewat dup setelah fd ditutup: %q, %v", buf[:n], err)
+	}
+	if err := p.Close(fd); !errors.Is(err, ErrBadFD) {
+		t.Fatalf("Close fd yang sudah ditutup: %v", err)
+	}
+	if _, err := p.Dup2(dup, MAX_FDS); !errors.Is(err, ErrBadFD) {
+		t.Fatalf("Dup2 ke fd di luar batas: %v", err)
+	}
+}
+
+// Jumlah descriptor per proses dibatasi MAX_FDS, dan Exit menutup semuanya.
+func TestProcessFDLimitAndExit(t *testing.T) {
+	fs := newTestDisk(t, Geometry{})
+	if err := fs.WriteFile("/f", []byte("x")); err != nil {
+		t.Fatal(err)
+	}
+	p := fs.NewProcess("a")
+	for i := 0; i < MAX_FDS; i++ {
+		if _, err := p.Open("/f", O_RDONLY); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := p.Open("/f", O_RDONLY); err == nil {
+		t.Fatal("Open melebihi MAX_FDS seharusnya gagal")
+	}
+	q := fs.NewProcess("b")
+	if _, err := q.Open("/f", O_RDONLY); err != nil {
+		t.Fatal(err)
+	}
+	if got := len(fs.FileTable.Handles()); got != MAX_FDS+1 {
+		t.Fatalf("%d handle terbuka, seharusnya %d", got, MAX_FDS+1)
+	}
+
+	p.Exit()
+	if got := len(fs.FileTable.Handles()); got != 1 {
+		t.Fatalf("%d handle terbuka setelah proses berhenti, seharusnya 1", got)
+	}
+	if len(fs.FileTable.Processes()) != 1 {
+		t.Fatal("proses yang sudah berhenti masih terdaftar")
+	}
+	if _, err := p.Open("/f", O_RDONLY); err == nil {
+		t.Fatal("proses yang sudah berhenti seharusnya tidak bisa membuka file")
+	}
+}
+
+// File yang dihapus selagi terbuka tetap bisa dibaca lewat handle-nya; bloknya baru dibebaskan saat
+// handle terakhir ditutup.
+func TestUnlinkWhileOpen(t *testing.T) {
+	fs := newTestDisk(t, Geometry{})
+	free := fs.countFreeBlocks()
+	data := make([]byte, 3*fs.Geometry.BlockSize)
+	for i := range data {
+		data[i] = byte(i)
+	}
+	if err := fs.WriteFile("/tmp.bin", data); err != nil {
+		t.Fatal(err)
+	}
+	f, err := fs.Open("/tmp.bin", O_RDONLY)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := fs.NewProcess("reader")
+	fd, err := p.Open("/tmp.bin", O_RDONLY)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := fs.Remove("/tmp.bin"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := fs.Lookup("/tmp.bin"); !errors.Is(err, ErrNotExist) {
+		t.Fatalf("file masih terlihat setelah dihapus: %v", err)
+	}
+	// Slot entri yang kosong boleh langsung dipakai file lain
+	if err := fs.WriteFile("/baru", []byte("baru")); err != nil {
+		t.Fatal(err)
+	}
+	got, err := io.ReadAll(f)
+	if err != nil || string(got) != string(data) {
+		t.Fatalf("membaca file yang sudah dihapus: %d byte, %v", len(got), err)
+	}
+
+	f.Close()
+	if fs.countFreeBlocks() == free-1 {
+		t.Fatal("blok dibebaskan padahal fd proses masih terbuka")
+	}
+	p.Close(fd)
+	if fs.countFreeBlocks() != free-1 { // Tinggal satu blok untuk /baru
+		t.Fatalf("%d blok kosong setelah handle terakhir ditutup, seharusnya %d", fs.countFreeBlocks(), free-1)
+	}
+	if got, _ := fs.ReadFile("/baru"); string(got) != "baru" {
+		t.Fatalf("isi /baru: %q", got)
+	}
+}
@@ -0,0 +1,529 @@
+// freespace.go
+package filesystem_logic
+
+import (
+	"sort"
+)
+
+// Manajemen ruang kosong, dipilih saat format lewat Geometry.FreeSpace dan disimpan di superblock.
+// FAT tetap menjadi peta alokasi yang sebenarnya (blok kosong bernilai FAT_FREE); FreeSpaceManager adalah
+// struktur di disk yang dipakai findFreeExtent (dan findFreeBlock) untuk *mencari* blok kosong, seperti pada
+// buku teks sistem operasi. setFAT memberi tahu manager setiap kali status kosong/terpakai sebuah blok berubah,
+// sehingga struktur tersebut selalu sinkron dengan FAT:
+//
+//	FAT scan  : memindai entri FAT dari blok 0 (bawaan, sama seperti versi sebelumnya), tanpa struktur tambahan
+//	bitmap    : satu bit per blok (1 = kosong) di blok-blok bitmap antara tabel inode dan root directory
+//	free list : setiap blok kosong menyimpan nomor blok kosong berikutnya (pointer 0); kepala list di superblock
+//	grouping  : blok grup menyimpan blok grup berikutnya (pointer 0), jumlah alamat (pointer 1), lalu alamat
+//	            blok-blok kosong lain; blok grup itu sendiri juga kosong dan dipakai setelah alamatnya habis
+//	counting  : blok pertama setiap run kosong menyimpan awal run berikutnya (pointer 0) dan panjang run (pointer 1),
+//	            urut dari blok terkecil dan selalu digabung dengan run tetangganya
+//
+// Free list, grouping, dan counting menumpang di blok kosong itu sendiri, jadi blok yang baru dibebaskan langsung
+// ditimpa pointer. Karena itu blok harus ditandai terpakai di FAT dulu sebelum isinya ditulis (lihat newZeroBlock).
+// Setiap manager menghitung blok disk yang dibacanya (FreeSpaceStats) agar kecepatan alokasi bisa dibandingkan.
+type FreeSpaceMethod int
+
+const (
+	FREE_FAT_SCAN FreeSpaceMethod = iota // Bawaan: pencarian linear di FAT
+	FREE_BITMAP
+	FREE_LIST
+	FREE_GROUPING
+	FREE_COUNTING
+)
+
+func (m FreeSpaceMethod) String() string {
+	switch m {
+	case FREE_BITMAP:
+		return "bitmap"
+	case FREE_LIST:
+		return "free list"
+	case FREE_GROUPING:
+		return "grouping"
+	case FREE_COUNTING:
+		return "counting"
+	default:
+		return "FAT scan"
+	}
+}
+
+// FreeSpaceMethods: Semua metode manajemen ruang kosong, untuk pilihan di GUI.
+var FreeSpaceMethods = []FreeSpaceMethod{FREE_FAT_SCAN, FREE_BITMAP, FREE_LIST, FREE_GROUPING, FREE_COUNTING}
+
+// FreeSpaceManager: Struktur yang mencatat blok kosong di disk.
+type FreeSpaceManager interface {
+	// Method: Metode manajemen ruang kosong yang diimplementasikan.
+	Method() FreeSpaceMethod
+	// Runs: Memanggil visit untuk setiap run blok kosong dalam urutan alami struktur (belum tentu urut nomor blok,
+	// dan run yang bersebelahan belum tentu digabung) sampai semua run dikunjungi atau visit mengembalikan false.
+	Runs(visit func(Extent) bool)
+	// Claim: Mengeluarkan block dari struktur karena baru saja ditandai terpakai di FAT.
+	Claim(block BlockID)
+	// Release: Memasukkan block ke struktur karena baru saja dibebaskan di FAT.
+	Release(block BlockID)
+	// Rebuild: Membangun ulang struktur dari FAT (saat format).
+	Rebuild()
+}
+
+// FreeSpaceStats: Biaya manajemen ruang kosong sejak disk diformat atau dimuat, dalam blok yang dibaca.
+type FreeSpaceStats struct {
+	Method          FreeSpaceMethod
+	MetaBlocks      int   // Blok yang dicadangkan khusus untuk struktur ini (hanya bitmap; yang lain menumpang di blok kosong)
+	Searches        int   // Jumlah pencarian blok kosong
+	SearchReads     int64 // Total blok yang dibaca saat mencari
+	LastSearchReads int   // Blok yang dibaca pencarian terakhir
+	Updates         int   // Jumlah blok yang berubah status kosong/terpakai
+	UpdateReads     int64 // Total blok yang dibaca saat memperbarui struktur
+}
+
+// AvgSearchReads: Rata-rata blok yang dibaca per pencarian.
+func (s FreeSpaceStats) AvgSearchReads() float64 {
+	if s.Searches == 0 {
+		return 0
+	}
+	return float64(s.SearchReads) / float64(s.Searches)
+}
+
+// FreeSpaceStats: Biaya manajemen ruang kosong disk ini.
+func (fs *FileSystem) FreeSpaceStats() FreeSpaceStats {
+	stats := fs.freeStats
+	stats.Method = fs.Geometry.FreeSpace
+	stats.MetaBlocks = fs.Geometry.BitmapBlocks()
+	return stats
+}
+
+// freeSpace: FreeSpaceManager untuk metode manajemen ruang kosong disk ini.
+func (fs *FileSystem) freeSpace() FreeSpaceManager {
+	switch fs.Geometry.FreeSpace {
+	case FREE_BITMAP:
+		return bitmapManager{fs}
+	case FREE_LIST:
+		return freeListManager{fs}
+	case FREE_GROUPING:
+		return groupingManager{fs}
+	case FREE_COUNTING:
+		return countingManager{fs}
+	default:
+		return fatScanManager{fs}
+	}
+}
+
+// rebuildFreeSpace: Membangun struktur ruang kosong dari FAT dan mengosongkan statistik biayanya.
+func (fs *FileSystem) rebuildFreeSpace() {
+	fs.superblock.FreeHead = FAT_EOF
+	fs.freeSpace().Rebuild()
+	fs.freeStats, fs.freeReads = FreeSpaceStats{}, 0
+}
+
+// updateFreeSpace: Dipanggil setFAT saat block berubah dari kosong ke terpakai atau sebaliknya.
+func (fs *FileSystem) updateFreeSpace(block BlockID, free bool) {
+	start := fs.freeReads
+	if free {
+		fs.freeSpace().Release(block)
+	} else {
+		fs.freeSpace().Claim(block)
+	}
+	fs.freeStats.Updates++
+	fs.freeStats.UpdateReads += fs.freeReads - start
+}
+
+// collectFreeRuns: Mengumpulkan run kosong dari FreeSpaceManager untuk mencari length blok berurutan.
+// Dengan first fit penelusuran berhenti di run pertama yang cukup panjang (found true, run itu ada di akhir slice);
+// selain itu semua run dikunjungi lalu digabung dan diurutkan (lihat mergeRuns).
+func (fs *FileSystem) collectFreeRuns(length int) (runs []Extent, found bool) {
+	start := fs.freeReads
+	fs.freeSpace().Runs(func(run Extent) bool {
+		runs = append(runs, run)
+		found = fs.Mount.Fit == FIT_FIRST && run.Length >= length
+		return !found
+	})
+	reads := fs.freeReads - start
+	fs.freeStats.Searches++
+	fs.freeStats.SearchReads += reads
+	fs.freeStats.LastSearchReads = int(reads)
+	if !found {
+		runs = mergeRuns(runs)
+	}
+	return runs, found
+}
+
+// mergeRuns: Mengurutkan run menurut blok awal dan menggabungkan run yang bersebelahan.
+func mergeRuns(runs []Extent) []Extent {
+	sort.Slice(runs, func(i, j int) bool { return runs[i].Start < runs[j].Start })
+	var merged []Extent
+	for _, run := range runs {
+		if n := len(merged); n > 0 && merged[n-1].End() == run.Start {
+			merged[n-1].Length += run.Length
+			continue
+		}
+		merged = append(merged, run)
+	}
+	return merged
+}
+
+// validBlock: true jika block adalah nomor blok di disk (bukan FAT_EOF atau pointer rusak).
+func (fs *FileSystem) validBlock(block BlockID) bool {
+	return block >= 0 && int(block) < fs.Geometry.TotalBlocks
+}
+
+// linkFree: Mengarahkan pointer 0 blok prev (atau kepala struktur di superblock jika prev FAT_EOF) ke next.
+func (fs *FileSystem) linkFree(prev, next BlockID) {
+	if prev == FAT_EOF {
+		fs.superblock.FreeHead = next
+	} else {
+		fs.setBlockPointer(prev, 0, next)
+	}
+}
+
+// runBuilder: Menggabungkan blok-blok kosong yang dikunjungi berurutan menjadi run untuk scanRuns.
+type runBuilder struct {
+	run   Extent
+	visit func(Extent) bool
+}
+
+// add: Menambahkan block; false jika visit meminta penelusuran berhenti.
+func (r *runBuilder) add(block BlockID) bool {
+	if r.run.Length > 0 && block == r.run.End() {
+		r.run.Length++
+		return true
+	}
+	if r.run.Length > 0 && !r.visit(r.run) {
+		return false
+	}
+	r.run = Extent{Start: block, Length: 1}
+	return true
+}
+
+// flush: Mengunjungi run terakhir yang belum dikirim.
+func (r *runBuilder) flush() {
+	if r.run.Length > 0 {
+		r.visit(r.run)
+	}
+}
+
+// scanRuns: Pencarian linear untuk FAT scan dan bitmap: perBlock entri per blok disk, satu baca per blok yang dilewati.
+func (fs *FileSystem) scanRuns(perBlock int, isFree func(i int) bool, visit func(Extent) bool) {
+	r := runBuilder{visit: visit}
+	for i := 0; i < fs.Geometry.TotalBlocks; i++ {
+		if i%perBlock == 0 {
+			fs.freeReads++
+		}
+		if isFree(i) && !r.add(BlockID(i)) {
+			return
+		}
+	}
+	r.flush()
+}
+
+// fatScanManager: Tanpa struktur tambahan; blok kosong dicari langsung di FAT.
+type fatScanManager struct{ fs *FileSystem }
+
+func (m fatScanManager) Method() FreeSpaceMethod { return FREE_FAT_SCAN }
+
+func (m fatScanManager) Runs(visit func(Extent) bool) {
+	m.fs.scanRuns(m.fs.Geometry.BlockSize/FAT_ENTRY_SIZE, func(i int) bool { return m.fs.FAT[i] == FAT_FREE }, visit)
+}
+
+func (m fatScanManager) Claim(block BlockID)   {} // Entri FAT sudah diubah oleh setFAT
+func (m fatScanManager) Release(block BlockID) {}
+func (m fatScanManager) Rebuild()              {}
+
+// bitmapManager: Satu bit per blok di blok-blok bitmap (Geometry.BitmapStart).
+type bitmapManager struct{ fs *FileSystem }
+
+func (m bitmapManager) Method() FreeSpaceMethod { return FREE_BITMAP }
+
+// bit: Letak bit milik blok i di area bitmap.
+func (m bitmapManager) bit(i int) (block BlockID, offset int, mask byte) {
+	perBlock := m.fs.Geometry.BlockSize * 8
+	block = m.fs.Geometry.BitmapStart() + BlockID(i/perBlock)
+	return block, (i % perBlock) / 8, 1 << (i % 8)
+}
+
+func (m bitmapManager) isFree(i int) bool {
+	block, offset, mask := m.bit(i)
+	return m.fs.Disk[block][offset]&mask != 0
+}
+
+func (m bitmapManager) set(i int, free bool) {
+	block, offset, mask := m.bit(i)
+	m.fs.freeReads++ // Baca-ubah-tulis satu blok bitmap
+	if free {
+		m.fs.Disk[block][offset] |= mask
+	} else {
+		m.fs.Disk[block][offset] &^= mask
+	}
+}
+
+func (m bitmapManager) Runs(visit func(Extent) bool) {
+	m.fs.scanRuns(m.fs.Geometry.BlockSize*8, m.isFree, visit)
+}
+
+func (m bitmapManager) Claim(block BlockID)   { m.set(int(block), false) }
+func (m bitmapManager) Release(block BlockID) { m.set(int(block), true) }
+
+func (m bitmapManager) Rebuild() {
+	for i := range m.fs.FAT {
+		block, offset, mask := m.bit(i)
+		if m.fs.FAT[i] == FAT_FREE {
+			m.fs.Disk[block][offset] |= mask
+		} else {
+			m.fs.Disk[block][offset] &^= mask
+		}
+	}
+}
+
+// freeListManager: Linked list blok kosong; pointer 0 setiap blok kosong menunjuk blok kosong berikutnya.
+// Blok yang dibebaskan dimasukkan di kepala list, dan alokasi mengambil dari kepala list.
+type freeListManager struct{ fs *FileSystem }
+
+func (m freeListManager) Method() FreeSpaceMethod { return FREE_LIST }
+
+// Runs: Setiap blok dikunjungi sebagai run sepanjang 1; blok berurutan baru terlihat setelah seluruh list dibaca.
+func (m freeListManager) Runs(visit func(Extent) bool) {
+	b := m.fs.superblock.FreeHead
+	for steps := 0; m.fs.validBlock(b) && steps < m.fs.Geometry.TotalBlocks; steps++ {
+		m.fs.freeReads++
+		if !visit(Extent{Start: b, Length: 1}) {
+			return
+		}
+		b = m.fs.blockPointer(b, 0)
+	}
+}
+
+func (m freeListManager) Claim(block BlockID) {
+	prev := FAT_EOF
+	b := m.fs.superblock.FreeHead
+	for steps := 0; m.fs.validBlock(b) && steps < m.fs.Geometry.TotalBlocks; steps++ {
+		m.fs.freeReads++
+		next := m.fs.blockPointer(b, 0)
+		if b == block {
+			m.fs.linkFree(prev, next)
+			return
+		}
+		prev, b = b, next
+	}
+}
+
+func (m freeListManager) Release(block BlockID) {
+	m.fs.setBlockPointer(block, 0, m.fs.superblock.FreeHead)
+	m.fs.superblock.FreeHead = block
+}
+
+func (m freeListManager) Rebuild() {
+	for i := len(m.fs.FAT) - 1; i >= 0; i-- {
+		if m.fs.FAT[i] == FAT_FREE {
+			m.Release(BlockID(i))
+		}
+	}
+}
+
+// groupingManager: Daftar blok grup; setiap blok grup mencatat alamat beberapa blok kosong lain.
+type groupingManager struct{ fs *FileSystem }
+
+func (m groupingManager) Method() FreeSpaceMethod { return FREE_GROUPING }
+
+// capacity: Jumlah alamat yang muat di satu blok grup (setelah pointer berikutnya dan jumlah alamat).
+func (m groupingManager) capacity() int {
+	return m.fs.Geometry.BlockSize/FAT_ENTRY_SIZE - 2
+}
+
+// count: Jumlah alamat di blok grup (dibatasi kapasitas jika blok rusak).
+func (m groupingManager) count(group BlockID) int {
+	return max(0, min(int(m.fs.blockPointer(group, 1)), m.capacity()))
+}
+
+// writeGroup: Mengisi blok grup dengan pointer next dan daftar alamat; sisa alamat dikosongkan.
+func (m groupingManager) writeGroup(group, next BlockID, addrs []BlockID) {
+	m.fs.setBlockPointer(group, 0, next)
+	m.fs.setBlockPointer(group, 1, BlockID(len(addrs)))
+	for i := 0; i < m.capacity(); i++ {
+		addr := FAT_EOF
+		if i < len(addrs) {
+			addr = addrs[i]
+		}
+		m.fs.setBlockPointer(group, 2+i, addr)
+	}
+}
+
+// addrs: Alamat-alamat di blok grup.
+func (m groupingManager) addrs(group BlockID) []BlockID {
+	var addrs []BlockID
+	for i := 0; i < m.count(group); i++ {
+		addrs = append(addrs, m.fs.blockPointer(group, 2+i))
+	}
+	return addrs
+}
+
+// Runs: Alamat di setiap grup dikunjungi sebagai run sepanjang 1, lalu blok grup itu sendiri.
+func (m groupingManager) Runs(visit func(Extent) bool) {
+	g := m.fs.superblock.FreeHead
+	for steps := 0; m.fs.validBlock(g) && steps < m.fs.Geometry.TotalBlocks; steps++ {
+		m.fs.freeReads++
+		for _, addr := range m.addrs(g) {
+			if m.fs.validBlock(addr) && !visit(Extent{Start: addr, Length: 1}) {
+				return
+			}
+		}
+		if !visit(Extent{Start: g, Length: 1}) {
+			return
+		}
+		g = m.fs.blockPointer(g, 0)
+	}
+}
+
+func (m groupingManager) Claim(block BlockID) {
+	prev := FAT_EOF
+	g := m.fs.superblock.FreeHead
+	for steps := 0; m.fs.validBlock(g) && steps < m.fs.Geometry.TotalBlocks; steps++ {
+		m.fs.freeReads++
+		next := m.fs.blockPointer(g, 0)
+		addrs := m.addrs(g)
+		for i, addr := range addrs {
+			if addr == block {
+				m.writeGroup(g, next, append(addrs[:i:i], addrs[i+1:]...))
+				return
+			}
+		}
+		if g == block {
+			if len(addrs) == 0 {
+				m.fs.linkFree(prev, next)
+				return
+			}
+			// Isi grup dipindahkan ke alamat terakhirnya, yang menjadi blok grup pengganti
+			last := addrs[len(addrs)-1]
+			m.writeGroup(last, next, addrs[:len(addrs)-1])
+			m.fs.linkFree(prev, last)
+			return
+		}
+		prev, g = g, next
+	}
+}
+
+func (m groupingManager) Release(block BlockID) {
+	head := m.fs.superblock.FreeHead
+	if m.fs.validBlock(head) {
+		m.fs.freeReads++
+		if addrs := m.addrs(head); len(addrs) < m.capacity() {
+			m.writeGroup(head, m.fs.blockPointer(head, 0), append(addrs, block))
+			return
+		}
+	}
+	m.writeGroup(block, head, nil) // Grup kepala penuh (atau belum ada): block menjadi grup baru
+	m.fs.superblock.FreeHead = block
+}
+
+func (m groupingManager) Rebuild() {
+	var free []BlockID
+	for i, next := range m.fs.FAT {
+		if next == FAT_FREE {
+			free = append(free, BlockID(i))
+		}
+	}
+	prev := FAT_EOF
+	for i := 0; i < len(free); {
+		group := free[i]
+		addrs := free[i+1 : min(i+1+m.capacity(), len(free))]
+		m.writeGroup(group, FAT_EOF, addrs)
+		m.fs.linkFree(prev, group)
+		prev = group
+		i += 1 + len(addrs)
+	}
+}
+
+// countingManager: Daftar run kosong (awal, panjang), urut dari blok terkecil; tidak pernah ada dua run bersebelahan.
+type countingManager struct{ fs *FileSystem }
+
+func (m countingManager) Method() FreeSpaceMethod { return FREE_COUNTING }
+
+// read: Run yang dicatat di blok start beserta awal run berikutnya.
+func (m countingManager) read(start BlockID) (Extent, BlockID) {
+	return Extent{Start: start, Length: int(m.fs.blockPointer(start, 1))}, m.fs.blockPointer(start, 0)
+}
+
+func (m countingManager) write(run Extent, next BlockID) {
+	m.fs.setBlockPointer(run.Start, 0, next)
+	m.fs.setBlockPointer(run.Start, 1, BlockID(run.Length))
+}
+
+func (m countingManager) Runs(visit func(Extent) bool) {
+	s := m.fs.superblock.FreeHead
+	for steps := 0; m.fs.validBlock(s) && steps < m.fs.Geometry.TotalBlocks; steps++ {
+		m.fs.freeReads++
+		run, next := m.read(s)
+		if run.Length <= 0 || int(run.End()) > m.fs.Geometry.TotalBlocks || !visit(run) {
+			return
+		}
+		s = next
+	}
+}
+
+func (m countingManager) Claim(block BlockID) {
+	prev := FAT_EOF
+	s := m.fs.superblock.FreeHead
+	for steps := 0; m.fs.validBlock(s) && steps < m.fs.Geometry.TotalBlocks; steps++ {
+		m.fs.freeReads++
+		run, next := m.read(s)
+		if block < run.Start {
+			return
+		}
+		if block < run.End() {
+			// Run dipecah menjadi bagian sebelum dan sesudah block
+			left := Extent{Start: run.Start, Length: int(block - run.Start)}
+			right := Extent{Start: block + 1, Length: int(run.End() - block - 1)}
+			switch {
+			case left.Length == 0 && right.Length == 0:
+				m.fs.linkFree(prev, next)
+			case left.Length == 0:
+				m.write(right, next)
+				m.fs.linkFree(prev, right.Start)
+			case right.Length == 0:
+				m.write(left, next)
+			default:
+				m.write(right, next)
+				m.write(left, right.Start)
+			}
+			return
+		}
+		prev, s = run.Start, next
+	}
+}
+
+func (m countingManager) Release(block BlockID) {
+	// Cari run terakhir sebelum block (prev) dan run pertama setelahnya (after, dengan penerusnya afterNext)
+	prev, after := Extent{Start: FAT_EOF}, Extent{Start: FAT_EOF}
+	afterNext := FAT_EOF
+	s := m.fs.superblock.FreeHead
+	for steps := 0; m.fs.validBlock(s) && steps < m.fs.Geometry.TotalBlocks; steps++ {
+		m.fs.freeReads++
+		run, next := m.read(s)
+		if run.Start > block {
+			after, afterNext = run, next
+			break
+		}
+		prev, s = run, next
+	}
+
+	// Gabungkan dengan run sesudahnya, lalu dengan run sebelumnya jika bersebelahan
+	merged, next := Extent{Start: block, Length: 1}, after.Start
+	if after.Start == block+1 {
+		merged.Length += after.Length
+		next = afterNext
+	}
+	if prev.Start != FAT_EOF && prev.End() == block {
+		m.write(Extent{Start: prev.Start, Length: prev.Length + merged.Length}, next)
+		return
+	}
+	m.write(merged, next)
+	m.fs.linkFree(prev.Start, block)
+}
+
+func (m countingManager) Rebuild() {
+	runs := m.fs.freeRuns()
+	next := FAT_EOF
+	for i := len(runs) - 1; i >= 0; i-- {
+		m.write(runs[i], next)
+		next = runs[i].Start
+	}
+	m.fs.superblock.FreeHead = next
+}
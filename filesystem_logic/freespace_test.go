@@ -0,0 +1,86 @@
+package filesystem_logic
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+)
+
+// checkFreeSpaceSynced: Run kosong menurut FreeSpaceManager (setelah digabung) harus sama persis dengan run
+// FAT_FREE di FAT, dan jumlah blok kosong di superblock harus sama dengan jumlah blok kosong di FAT.
+func checkFreeSpaceSynced(t *testing.T, fs *FileSystem, when string) {
+	t.Helper()
+	var runs []Extent
+	fs.freeSpace().Runs(func(run Extent) bool {
+		runs = append(runs, run)
+		return true
+	})
+	if got, want := fmt.Sprint(mergeRuns(runs)), fmt.Sprint(fs.freeRuns()); got != want {
+		t.Fatalf("%s: run %s = %s, FAT = %s", when, fs.Geometry.FreeSpace, got, want)
+	}
+	if got, want := fs.superblock.FreeBlocks, fs.countFreeBlocks(); int64(got) != int64(want) {
+		t.Fatalf("%s: superblock mencatat %d blok kosong, FAT %d", when, got, want)
+	}
+}
+
+// Setiap FreeSpaceManager tetap sinkron dengan FAT setelah file dibuat, dihapus, dipotong, dan diperpanjang,
+// dan setelah disk disimpan ke image lalu dimuat lagi.
+func TestFreeSpaceManagersMatchFAT(t *testing.T) {
+	for _, method := range FreeSpaceMethods {
+		for _, alloc := range AllocationMethods {
+			t.Run(fmt.Sprintf("%s/%s", method, alloc), func(t *testing.T) {
+				fs := newTestDisk(t, Geometry{TotalBlocks: 128, Allocation: alloc, FreeSpace: method})
+				checkFreeSpaceSynced(t, fs, "format")
+				blockSize := fs.Geometry.BlockSize
+
+				fs.Mkdir("/d")
+				for i := 0; i < 10; i++ {
+					if err := fs.WriteFile(fmt.Sprintf("/d/f%d", i), make([]byte, (i%4)*blockSize+7)); err != nil {
+						t.Fatal(err)
+					}
+				}
+				checkFreeSpaceSynced(t, fs, "create")
+
+				for i := 0; i < 10; i += 3 {
+					if err := fs.Remove(fmt.Sprintf("/d/f%d", i)); err != nil {
+						t.Fatal(err)
+					}
+				}
+				checkFreeSpaceSynced(t, fs, "delete")
+
+				if err := fs.Truncate("/d/f2", 1); err != nil {
+					t.Fatal(err)
+				}
+				if err := fs.Truncate("/d/f5", int64(5*blockSize)); err != nil {
+					t.Fatal(err)
+				}
+				if err := fs.Append("/d/f7", make([]byte, 2*blockSize)); err != nil {
+					t.Fatal(err)
+				}
+				checkFreeSpaceSynced(t, fs, "truncate")
+
+				image := filepath.Join(t.TempDir(), "disk.img")
+				if err := fs.SaveImage(image); err != nil {
+					t.Fatal(err)
+				}
+				loaded, err := NewFileSystem(FileSystemOptions{ImagePath: image})
+				if err != nil {
+					t.Fatal(err)
+				}
+				if loaded.Geometry.FreeSpace != method {
+					t.Fatalf("metode ruang kosong setelah dimuat: %s", loaded.Geometry.FreeSpace)
+				}
+				checkFreeSpaceSynced(t, loaded, "load")
+
+				// Struktur yang dimuat dari image harus tetap bisa dipakai untuk alokasi dan pembebasan berikutnya
+				if err := loaded.WriteFile("/after", make([]byte, 3*blockSize)); err != nil {
+					t.Fatal(err)
+				}
+				if err := loaded.Remove("/d/f1"); err != nil {
+					t.Fatal(err)
+				}
+				checkFreeSpaceSynced(t, loaded, "setelah load")
+			})
+		}
+	}
+}
@@ -0,0 +1,166 @@
+// geometry.go
+package filesystem_logic
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Nilai geometri bawaan (sama dengan konstanta BLOCK_SIZE/TOTAL_BLOCKS versi awal simulator).
+const (
+	DEFAULT_BLOCK_SIZE      = 256 // Bytes per block
+	DEFAULT_TOTAL_BLOCKS    = 256 // Total blok (256 x 256 = 64KB)
+	DEFAULT_RESERVED_BLOCKS = 1   // Hanya superblock
+	MAX_TOTAL_BLOCKS        = 65536
+	MAX_BLOCK_SIZE          = 65536
+)
+
+// Geometry: Ukuran-ukuran disk yang dipilih saat format dan disimpan di superblock.
+// Semua fungsi filesystem memakai fs.Geometry, bukan konstanta, sehingga disk dengan
+// ukuran blok berbeda bisa dibandingkan (misalnya untuk melihat internal fragmentation).
+type Geometry struct {
+	BlockSize      int              // Bytes per blok
+	TotalBlocks    int              // Jumlah blok di disk
+	MaxFilenameLen int              // Panjang nama pendek maksimum (<= MAX_FILENAME_LEN); nama yang lebih panjang disimpan sebagai LFN
+	ReservedBlocks int              // Blok di awal disk sebelum area FAT, termasuk superblock (minimal 1)
+	FATCopies      int              // Jumlah salinan FAT (1, atau 2 jika memakai mirror)
+	Inodes         int              // Jumlah inode di tabel inode (lihat inode.go); 0 berarti metadata disimpan langsung di entri direktori
+	Allocation     AllocationMethod // Cara blok data file dicatat (lihat alloc.go); nilai nol = rantai FAT
+	FreeSpace      FreeSpaceMethod  // Cara blok kosong dicatat (lihat freespace.go); nilai nol = pencarian di FAT
+}
+
+// DefaultGeometry: Geometri bawaan simulator.
+func DefaultGeometry() Geometry {
+	return Geometry{
+		BlockSize:      DEFAULT_BLOCK_SIZE,
+		TotalBlocks:    DEFAULT_TOTAL_BLOCKS,
+		MaxFilenameLen: MAX_FILENAME_LEN,
+		ReservedBlocks: DEFAULT_RESERVED_BLOCKS,
+		FATCopies:      DEFAULT_FAT_COPIES,
+	}
+}
+
+// WithDefaults: Mengisi field yang bernilai 0 dengan nilai bawaan.
+func (g Geometry) WithDefaults() Geometry {
+	def := DefaultGeometry()
+	if g.BlockSize == 0 {
+		g.BlockSize = def.BlockSize
+	}
+	if g.TotalBlocks == 0 {
+		g.TotalBlocks = def.TotalBlocks
+	}
+	if g.MaxFilenameLen == 0 {
+		g.MaxFilenameLen = def.MaxFilenameLen
+	}
+	if g.ReservedBlocks == 0 {
+		g.ReservedBlocks = def.ReservedBlocks
+	}
+	if g.FATCopies == 0 {
+		g.FATCopies = def.FATCopies
+	}
+	return g
+}
+
+// FATBlocksPerCopy: Jumlah blok yang dibutuhkan untuk menyimpan satu salinan FAT.
+func (g Geometry) FATBlocksPerCopy() int {
+	return (g.TotalBlocks*FAT_ENTRY_SIZE + g.BlockSize - 1) / g.BlockSize
+}
+
+// FATStart: Blok pertama area FAT (tepat setelah blok-blok reserved).
+func (g Geometry) FATStart() BlockID {
+	return BlockID(g.ReservedBlocks)
+}
+
+// InodeStart: Blok pertama tabel inode, tepat setelah area FAT.
+func (g Geometry) InodeStart() BlockID {
+	return g.FATStart() + BlockID(g.FATBlocksPerCopy()*g.FATCopies)
+}
+
+// InodeBlocks: Jumlah blok tabel inode (0 jika disk tidak memakai tabel inode). Inode tidak pernah
+// menyeberang batas blok, jadi sisa blok yang tidak muat satu inode dibiarkan kosong.
+func (g Geometry) InodeBlocks() int {
+	perBlock := g.BlockSize / INODE_SIZE
+	if g.Inodes == 0 || perBlock == 0 {
+		return 0
+	}
+	return (g.Inodes + perBlock - 1) / perBlock
+}
+
+// BitmapStart: Blok pertama bitmap blok kosong, tepat setelah tabel inode.
+func (g Geometry) BitmapStart() BlockID {
+	return g.InodeStart() + BlockID(g.InodeBlocks())
+}
+
+// BitmapBlocks: Jumlah blok bitmap (satu bit per blok disk), 0 jika disk tidak memakai manajemen ruang kosong bitmap.
+func (g Geometry) BitmapBlocks() int {
+	if g.FreeSpace != FREE_BITMAP {
+		return 0
+	}
+	perBlock := g.BlockSize * 8
+	return (g.TotalBlocks + perBlock - 1) / perBlock
+}
+
+// RootBlock: Blok root directory, tepat setelah area FAT, tabel inode, dan bitmap.
+func (g Geometry) RootBlock() BlockID {
+	return g.BitmapStart() + BlockID(g.BitmapBlocks())
+}
+
+// EntriesPerBlock: Jumlah DirectoryEntry yang muat di satu blok.
+func (g Geometry) EntriesPerBlock() int {
+	return g.BlockSize / DIRECTORY_ENTRY_SIZE
+}
+
+// Validate: Memastikan geometri bisa dipakai untuk memformat disk.
+func (g Geometry) Validate() error {
+	if g.BlockSize <= 0 || g.BlockSize > MAX_BLOCK_SIZE || g.BlockSize%FAT_ENTRY_SIZE != 0 {
+		return fmt.Errorf("ukuran blok %d tidak valid (harus kelipatan %d, maks %d)", g.BlockSize, FAT_ENTRY_SIZE, MAX_BLOCK_SIZE)
+	}
+	if g.BlockSize < SUPERBLOCK_SIZE {
+		return fmt.Errorf("ukuran blok %d terlalu kecil untuk superblock (%d byte)", g.BlockSize, SUPERBLOCK_SIZE)
+	}
+	if g.EntriesPerBlock() < 2 {
+		return fmt.Errorf("ukuran blok %d terlalu kecil, minimal harus muat entri '.' dan '..' (%d byte)", g.BlockSize, 2*DIRECTORY_ENTRY_SIZE)
+	}
+	if g.TotalBlocks <= 0 || g.TotalBlocks > MAX_TOTAL_BLOCKS {
+		return fmt.Errorf("jumlah blok %d tidak valid (maks %d)", g.TotalBlocks, MAX_TOTAL_BLOCKS)
+	}
+	if g.MaxFilenameLen < 1 || g.MaxFilenameLen > MAX_FILENAME_LEN {
+		return fmt.Errorf("panjang nama maksimum %d tidak valid (1 sampai %d)", g.MaxFilenameLen, MAX_FILENAME_LEN)
+	}
+	if g.ReservedBlocks < 1 {
+		return errors.New("jumlah blok reserved minimal 1 (untuk superblock)")
+	}
+	if g.FATCopies < 1 || g.FATCopies > MAX_FAT_COPIES {
+		return fmt.Errorf("jumlah salinan FAT harus 1 sampai %d, bukan %d", MAX_FAT_COPIES, g.FATCopies)
+	}
+	if g.Inodes < 0 || g.Inodes > MAX_INODES {
+		return fmt.Errorf("jumlah inode %d tidak valid (0 sampai %d)", g.Inodes, MAX_INODES)
+	}
+	if g.Allocation < ALLOC_LINKED || g.Allocation > ALLOC_CONTIGUOUS {
+		return fmt.Errorf("metode alokasi %d tidak dikenal", g.Allocation)
+	}
+	if g.FreeSpace < FREE_FAT_SCAN || g.FreeSpace > FREE_COUNTING {
+		return fmt.Errorf("metode manajemen ruang kosong %d tidak dikenal", g.FreeSpace)
+	}
+	// Root directory ada setelah area FAT, tabel inode, dan bitmap, dan harus masih tersisa minimal satu blok data
+	if int(g.RootBlock())+1 >= g.TotalBlocks {
+		return fmt.Errorf("disk terlalu kecil: %d blok reserved + %d blok FAT + %d blok inode + %d blok bitmap tidak menyisakan ruang data dari %d blok",
+			g.ReservedBlocks, g.FATBlocksPerCopy()*g.FATCopies, g.InodeBlocks(), g.BitmapBlocks(), g.TotalBlocks)
+	}
+	return nil
+}
+
+// String: Ringkasan geometri untuk ditampilkan di GUI/log.
+func (g Geometry) String() string {
+	s := fmt.Sprintf("%d blok x %d byte (%d KB)", g.TotalBlocks, g.BlockSize, g.TotalBlocks*g.BlockSize/1024)
+	if g.Inodes > 0 {
+		s += fmt.Sprintf(", %d inode", g.Inodes)
+	}
+	if g.Allocation != ALLOC_LINKED {
+		s += ", alokasi " + g.Allocation.String()
+	}
+	if g.FreeSpace != FREE_FAT_SCAN {
+		s += ", ruang kosong " + g.FreeSpace.String()
+	}
+	return s
+}
@@ -0,0 +1,74 @@
+package filesystem_logic
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// Geometri yang tidak bisa dipakai ditolak Validate, dan FormatDisk tidak menyentuh disk yang sedang dipakai.
+func TestGeometryValidate(t *testing.T) {
+	cases := []struct {
+		name, want string
+		geo        Geometry
+	}{
+		{"bukan kelipatan 4", "kelipatan", Geometry{BlockSize: 258}},
+		{"blok terlalu kecil", "terlalu kecil", Geometry{BlockSize: 64}},
+		{"tidak muat '.' dan '..'", "'.' dan '..'", Geometry{BlockSize: (2*DIRECTORY_ENTRY_SIZE+3)/4*4 - 4}},
+		{"blok terlalu besar", "maks", Geometry{BlockSize: 2 * MAX_BLOCK_SIZE}},
+		{"nama terlalu panjang", "panjang nama", Geometry{MaxFilenameLen: MAX_FILENAME_LEN + 1}},
+		{"salinan FAT", "salinan FAT", Geometry{FATCopies: MAX_FAT_COPIES + 1}},
+		{"disk terlalu kecil", "disk terlalu kecil", Geometry{TotalBlocks: 4}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if err := c.geo.WithDefaults().Validate(); err == nil || !strings.Contains(err.Error(), c.want) {
+				t.Fatalf("Validate seharusnya gagal dengan '%s', dapat: %v", c.want, err)
+			}
+		})
+	}
+
+	if err := (Geometry{BlockSize: (2*DIRECTORY_ENTRY_SIZE + 3) / 4 * 4}).WithDefaults().Validate(); err != nil {
+		t.Fatalf("blok terkecil yang muat '.' dan '..' ditolak: %v", err)
+	}
+
+	fs := newTestDisk(t, Geometry{})
+	writeTestFile(t, fs, "tetap.txt", []byte("tetap"))
+	if err := fs.FormatDisk(Geometry{BlockSize: 258}); err == nil {
+		t.Fatal("FormatDisk seharusnya menolak geometri tidak valid")
+	}
+	if fs.Geometry != DefaultGeometry() {
+		t.Fatalf("geometri berubah setelah format gagal: %+v", fs.Geometry)
+	}
+	checkTestFile(t, fs, "tetap.txt", []byte("tetap"))
+}
+
+// Disk dengan geometri selain bawaan bisa disimpan lalu dimuat lagi; geometrinya dibaca dari superblock.
+func TestGeometryImageRoundTrip(t *testing.T) {
+	geo := Geometry{BlockSize: 512, TotalBlocks: 1024, MaxFilenameLen: 12, ReservedBlocks: 3, FATCopies: 1}
+	fs := newTestDisk(t, geo)
+	data := make([]byte, 3*geo.BlockSize+1)
+	writeTestFile(t, fs, "besar.bin", data)
+	image := filepath.Join(t.TempDir(), "disk.img")
+	if err := fs.SaveImage(image); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := NewFileSystem(FileSystemOptions{ImagePath: image})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Geometry != geo {
+		t.Fatalf("geometri setelah dimuat: %+v, seharusnya %+v", loaded.Geometry, geo)
+	}
+	checkTestFile(t, loaded, "besar.bin", data)
+
+	// 4 blok terpakai untuk 3*512+1 byte: 511 byte terbuang di blok terakhir
+	usage, err := loaded.ComputeDiskUsage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if usage.Files != 1 || usage.FileBlocks != 4 || usage.SlackBytes != int64(geo.BlockSize-1) {
+		t.Fatalf("pemakaian disk: %+v", usage)
+	}
+}
@@ -0,0 +1,114 @@
+// image.go
+package filesystem_logic
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// Format file image di host adalah salinan mentah seluruh disk, blok demi blok mulai dari blok 0
+// (seperti hasil `dd` dari disk sungguhan). Karena superblock ada di blok 0 dan FAT tersimpan
+// di blok-blok setelahnya, image sudah "menjelaskan dirinya sendiri" tanpa header tambahan.
+
+// SaveImage: Menyimpan seluruh blok Disk (termasuk superblock dan FAT) ke satu file biner di host.
+// File ditulis ke file sementara dulu lalu di-rename, supaya image lama tidak rusak jika penulisan gagal di tengah jalan.
+func (fs *FileSystem) SaveImage(path string) error {
+	if fs.Disk == nil || fs.FAT == nil {
+		return errors.New("disk belum diformat, tidak ada yang bisa disimpan")
+	}
+
+	// Perbarui superblock (misalnya jumlah blok kosong) sebelum disk disalin ke image
+	if err := fs.SyncSuperblock(); err != nil {
+		return fmt.Errorf("gagal menyimpan image '%s': %w", path, err)
+	}
+
+	tmpPath := path + ".tmp"
+	f, err := os.Create(tmpPath)
+	if err != nil {
+		return fmt.Errorf("gagal membuat file image '%s': %w", path, err)
+	}
+	w := bufio.NewWriter(f)
+
+	// Tulis setiap blok Disk secara berurutan
+	for i := 0; err == nil && i < fs.Geometry.TotalBlocks; i++ {
+		_, err = w.Write(fs.Disk[i])
+	}
+
+	if err == nil {
+		err = w.Flush()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("gagal menulis file image '%s': %w", path, err)
+	}
+
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("gagal menyimpan file image '%s': %w", path, err)
+	}
+	fmt.Printf("Disk disimpan ke image '%s'.\n", path)
+	return nil
+}
+
+// LoadImage: Membaca file image yang dibuat SaveImage, lalu me-mount-nya:
+// superblock divalidasi, FAT dimuat dari blok-blok FAT, dan root directory diperiksa.
+// fs.Disk dan fs.FAT hanya diganti setelah seluruh image berhasil dibaca dan lolos validasi.
+func (fs *FileSystem) LoadImage(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("gagal membaca file image '%s': %w", path, err)
+	}
+
+	// 1. Validasi superblock di awal image: image asing atau rusak ditolak di sini
+	sb, err := DeserializeSuperblock(data)
+	if err != nil {
+		return fmt.Errorf("image '%s' ditolak: %w", path, err)
+	}
+	if err := sb.validateGeometry(); err != nil {
+		return fmt.Errorf("image '%s' ditolak: %w", path, err)
+	}
+	// Geometri disk diambil dari superblock, bukan dari konstanta
+	geo := sb.Geometry()
+	if len(data) != geo.TotalBlocks*geo.BlockSize {
+		return fmt.Errorf("image '%s' ditolak: ukuran %d byte, seharusnya %d byte (%s)", path, len(data), geo.TotalBlocks*geo.BlockSize, geo)
+	}
+
+	// 2. Potong image menjadi blok-blok Disk
+	newDisk := make([][]byte, geo.TotalBlocks)
+	for i := 0; i < geo.TotalBlocks; i++ {
+		newDisk[i] = make([]byte, geo.BlockSize)
+		copy(newDisk[i], data[i*geo.BlockSize:(i+1)*geo.BlockSize])
+	}
+
+	// 3. Muat FAT dari blok-blok FAT di disk
+	newFAT, err := loadFATFromDisk(newDisk, sb)
+	if err != nil {
+		return fmt.Errorf("image '%s' ditolak: %w", path, err)
+	}
+	for b := SUPERBLOCK_BLOCK; b < sb.RootBlock; b++ { // Superblock, blok reserved, area FAT, dan tabel inode
+		if newFAT[b] != FAT_RESERVED {
+			return fmt.Errorf("image '%s' ditolak: blok sistem %d tidak bertanda reserved di FAT", path, b)
+		}
+	}
+	if newFAT[sb.RootBlock] == FAT_FREE || newFAT[sb.RootBlock] == FAT_RESERVED {
+		return fmt.Errorf("image '%s' tidak memiliki root directory yang valid di blok %d", path, sb.RootBlock)
+	}
+
+	fs.Geometry = geo
+	fs.Disk = newDisk
+	fs.FAT = newFAT
+	fs.superblock = sb
+	fs.RootDirBlock = sb.RootBlock
+	fs.CurrentDirectoryBlock = sb.RootBlock
+	fs.resetFileTable()
+	fs.freeStats, fs.freeReads = FreeSpaceStats{}, 0
+	// Samakan semua salinan FAT dengan salinan yang berhasil dimuat (memperbaiki FAT utama jika tadi memakai mirror)
+	fs.flushFAT()
+	fmt.Printf("Disk dimuat dari image '%s' (label '%s').\n", path, sb.Label())
+	return nil
+}
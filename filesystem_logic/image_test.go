@@ -0,0 +1,86 @@
+package filesystem_logic
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// Disk yang disimpan lalu dimuat lagi berisi file dan isi yang sama, baik lewat LoadImage maupun lewat
+// NewFileSystem dengan ImagePath.
+func TestImageRoundTrip(t *testing.T) {
+	image := filepath.Join(t.TempDir(), "disk.img")
+	fs, err := NewFileSystem(FileSystemOptions{ImagePath: image})
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := bytes.Repeat([]byte("simulator "), 3*fs.Geometry.BlockSize/10)
+	writeTestFile(t, fs, "data.txt", data)
+	if err := fs.SaveImage(image); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(image + ".tmp"); !os.IsNotExist(err) {
+		t.Fatalf("file sementara masih ada setelah SaveImage: %v", err)
+	}
+
+	other := newTestDisk(t, Geometry{})
+	if err := other.LoadImage(image); err != nil {
+		t.Fatal(err)
+	}
+	checkTestFile(t, other, "data.txt", data)
+
+	mounted, err := NewFileSystem(FileSystemOptions{ImagePath: image})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkTestFile(t, mounted, "data.txt", data)
+}
+
+// Image yang bukan buatan SaveImage, terpotong, atau FAT-nya rusak ditolak, dan disk yang sedang dipakai
+// tidak berubah.
+func TestLoadImageRejectsInvalid(t *testing.T) {
+	dir := t.TempDir()
+	image := filepath.Join(dir, "disk.img")
+	fs := newTestDisk(t, Geometry{})
+	writeTestFile(t, fs, "asli.txt", []byte("isi asli"))
+	if err := fs.SaveImage(image); err != nil {
+		t.Fatal(err)
+	}
+	valid, err := os.ReadFile(image)
+	if err != nil {
+		t.Fatal(err)
+	}
+	geo := fs.Geometry
+
+	// FAT[0] di luar disk pada kedua salinan FAT
+	badFAT := append([]byte{}, valid...)
+	for i := 0; i < geo.FATCopies; i++ {
+		copy(badFAT[(int(geo.FATStart())+i*geo.FATBlocksPerCopy())*geo.BlockSize:], []byte{0xff, 0xff, 0xff, 0x7f})
+	}
+	cases := []struct {
+		name, want string
+		data       []byte
+	}{
+		{"asing", "ditolak", []byte("PK\x03\x04 bukan image disk")},
+		{"terpotong", "ukuran", valid[:len(valid)-geo.BlockSize/2]},
+		{"fat rusak", "FAT", badFAT},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			path := filepath.Join(dir, c.name+".img")
+			if err := os.WriteFile(path, c.data, 0o644); err != nil {
+				t.Fatal(err)
+			}
+			err := fs.LoadImage(path)
+			if err == nil || !strings.Contains(err.Error(), c.want) {
+				t.Fatalf("LoadImage seharusnya gagal dengan '%s', dapat: %v", c.want, err)
+			}
+			if _, err := NewFileSystem(FileSystemOptions{ImagePath: path}); err == nil {
+				t.Fatal("NewFileSystem seharusnya gagal memuat image rusak")
+			}
+			checkTestFile(t, fs, "asli.txt", []byte("isi asli"))
+		})
+	}
+}
@@ -0,0 +1,307 @@
+// indexed.go
+package filesystem_logic
+
+import (
+	"fmt"
+)
+
+// Alokasi berindeks gaya ext2 (Geometry.Allocation = ALLOC_INDEXED). entry.StartBlock menunjuk blok indeks
+// file, yang berisi pointer blok (int32 little endian, sama seperti entri FAT):
+//
+//	[0 .. INDEX_DIRECT-1]  pointer langsung ke blok data 0 .. INDEX_DIRECT-1
+//	[INDEX_SINGLE]         blok single indirect: BlockSize/4 pointer ke blok data berikutnya
+//	[INDEX_DOUBLE]         blok double indirect: pointer ke blok-blok single indirect
+//
+// Pointer kosong bernilai FAT_EOF. Blok indeks dan blok pointer hanya dialokasikan saat dibutuhkan dan
+// dibebaskan lagi saat file dipotong, jadi file kosong tidak punya blok sama sekali (StartBlock FAT_EOF).
+// Di FAT, semua blok milik file (data maupun pointer) bertanda FAT_EOF: FAT hanya dipakai sebagai peta
+// alokasi, urutan blok dibaca dari blok indeks.
+const (
+	INDEX_DIRECT = 12               // Jumlah pointer langsung, sama seperti ext2
+	INDEX_SINGLE = INDEX_DIRECT     // Posisi pointer single indirect di blok indeks
+	INDEX_DOUBLE = INDEX_DIRECT + 1 // Posisi pointer double indirect di blok indeks
+)
+
+type indexedAllocator struct{ fs *FileSystem }
+
+// indexLayout: Seluruh blok yang dipakai satu file berindeks, hasil membaca blok indeksnya.
+type indexLayout struct {
+	Index      BlockID
+	Direct     []BlockID
+	Single     BlockID // FAT_EOF jika belum ada
+	SingleData []BlockID
+	Double     BlockID // FAT_EOF jika belum ada
+	Inner      []BlockID
+	InnerData  [][]BlockID
+}
+
+func (a indexedAllocator) Method() AllocationMethod { return ALLOC_INDEXED }
+
+// perBlock: Jumlah pointer di satu blok.
+func (a indexedAllocator) perBlock() int {
+	return a.fs.Geometry.BlockSize / FAT_ENTRY_SIZE
+}
+
+// maxBlocks: Jumlah blok data terbanyak yang bisa dicatat satu blok indeks.
+func (a indexedAllocator) maxBlocks() int {
+	p := a.perBlock()
+	return INDEX_DIRECT + p + p*p
+}
+
+// pointers: Pointer di block mulai dari posisi from sampai sebelum to, berhenti di pointer kosong pertama.
+func (a indexedAllocator) pointers(block BlockID, from, to int) ([]BlockID, error) {
+	var out []BlockID
+	for i := from; i < to; i++ {
+		p := a.fs.blockPointer(block, i)
+		if p == FAT_EOF {
+			break
+		}
+		if p < 0 || p >= BlockID(a.fs.Geometry.TotalBlocks) {
+			return out, fmt.Errorf("pointer tidak valid (%d) di blok indeks %d", p, block)
+		}
+		out = append(out, p)
+	}
+	return out, nil
+}
+
+// layout: Membaca struktur indeks file.
+func (a indexedAllocator) layout(entry DirectoryEntry) (indexLayout, error) {
+	l := indexLayout{Index: entry.StartBlock, Single: FAT_EOF, Double: FAT_EOF}
+	if entry.StartBlock == FAT_EOF || entry.StartBlock == FAT_FREE {
+		return l, nil
+	}
+	if entry.StartBlock < 0 || entry.StartBlock >= BlockID(a.fs.Geometry.TotalBlocks) {
+		return l, fmt.Errorf("blok indeks tidak valid (%d)", entry.StartBlock)
+	}
+	p := a.perBlock()
+
+	var err error
+	if l.Direct, err = a.pointers(l.Index, 0, INDEX_DIRECT); err != nil {
+		return l, err
+	}
+	single, err := a.pointers(l.Index, INDEX_SINGLE, INDEX_SINGLE+1)
+	if err != nil || len(single) == 0 {
+		return l, err
+	}
+	l.Single = single[0]
+	if l.SingleData, err = a.pointers(l.Single, 0, p); err != nil {
+		return l, err
+	}
+	double, err := a.pointers(l.Index, INDEX_DOUBLE, INDEX_DOUBLE+1)
+	if err != nil || len(double) == 0 {
+		return l, err
+	}
+	l.Double = double[0]
+	if l.Inner, err = a.pointers(l.Double, 0, p); err != nil {
+		return l, err
+	}
+	for _, inner := range l.Inner {
+		data, err := a.pointers(inner, 0, p)
+		l.InnerData = append(l.InnerData, data)
+		if err != nil {
+			return l, err
+		}
+	}
+	return l, nil
+}
+
+func (a indexedAllocator) Blocks(entry DirectoryEntry) ([]BlockID, error) {
+	l, err := a.layout(entry)
+	blocks := append(append([]BlockID{}, l.Direct...), l.SingleData...)
+	for _, data := range l.InnerData {
+		blocks = append(blocks, data...)
+	}
+	return blocks, err
+}
+
+func (a indexedAllocator) MetaBlocks(entry DirectoryEntry) ([]BlockID, error) {
+	l, err := a.layout(entry)
+	var blocks []BlockID
+	for _, block := range []BlockID{l.Index, l.Single, l.Double} {
+		if block >= 0 {
+			blocks = append(blocks, block)
+		}
+	}
+	return append(blocks, l.Inner...), err
+}
+
+// child: Blok yang ditunjuk pointer ke-i di parent. Jika kosong dan create, blok pointer baru dialokasikan.
+func (a indexedAllocator) child(parent BlockID, i int, create bool) (BlockID, error) {
+	block := a.fs.blockPointer(parent, i)
+	if block != FAT_EOF {
+		return block, nil
+	}
+	if !create {
+		return block, fmt.Errorf("pointer %d di blok indeks %d kosong", i, parent)
+	}
+	block, err := a.fs.newPointerBlock()
+	if err != nil {
+		return block, err
+	}
+	a.fs.setBlockPointer(parent, i, block)
+	return block, nil
+}
+
+// dropChild: Membebaskan blok yang ditunjuk pointer ke-i di parent lalu mengosongkan pointernya.
+func (a indexedAllocator) dropChild(parent BlockID, i int) {
+	a.fs.setFAT(a.fs.blockPointer(parent, i), FAT_FREE)
+	a.fs.setBlockPointer(parent, i, FAT_EOF)
+}
+
+// slotFor: Blok pointer dan posisi di dalamnya yang mencatat blok data ke-n file dengan blok indeks index.
+// Dengan create, blok pointer yang belum ada dialokasikan; karena blok data selalu ditambah berurutan,
+// blok pointer baru hanya dibuat untuk data pertamanya, dan dibebaskan lagi jika alokasi berikutnya gagal.
+func (a indexedAllocator) slotFor(index BlockID, n int, create bool) (BlockID, int, error) {
+	p := a.perBlock()
+	if n < INDEX_DIRECT {
+		return index, n, nil
+	}
+	n -= INDEX_DIRECT
+	if n < p {
+		single, err := a.child(index, INDEX_SINGLE, create)
+		return single, n, err
+	}
+	n -= p
+	double, err := a.child(index, INDEX_DOUBLE, create)
+	if err != nil {
+		return double, 0, err
+	}
+	inner, err := a.child(double, n/p, create)
+	if err != nil && create && n == 0 {
+		a.dropChild(index, INDEX_DOUBLE)
+	}
+	return inner, n % p, err
+}
+
+// appendBlock: Menambah satu blok data (diisi nol) sebagai blok ke-n file.
+func (a indexedAllocator) appendBlock(entry *DirectoryEntry, n int) (BlockID, error) {
+	if entry.StartBlock == FAT_EOF || entry.StartBlock == FAT_FREE {
+		index, err := a.fs.newPointerBlock()
+		if err != nil {
+			return index, err
+		}
+		entry.StartBlock = index
+	}
+	data, err := a.fs.newZeroBlock()
+	if err != nil {
+		return data, err
+	}
+	block, i, err := a.slotFor(entry.StartBlock, n, true)
+	if err != nil {
+		a.fs.setFAT(data, FAT_FREE)
+		return data, err
+	}
+	a.fs.setBlockPointer(block, i, data)
+	return data, nil
+}
+
+// dropLast: Membebaskan blok data terakhir file (blok ke-n) beserta blok pointer yang menjadi kosong.
+func (a indexedAllocator) dropLast(entry *DirectoryEntry, n int) error {
+	block, i, err := a.slotFor(entry.StartBlock, n, false)
+	if err != nil {
+		return err
+	}
+	a.dropChild(block, i)
+	if n < INDEX_DIRECT || i != 0 {
+		return nil
+	}
+	m := n - INDEX_DIRECT
+	p := a.perBlock()
+	if m < p {
+		a.dropChild(entry.StartBlock, INDEX_SINGLE)
+		return nil
+	}
+	m -= p
+	double := a.fs.blockPointer(entry.StartBlock, INDEX_DOUBLE)
+	a.dropChild(double, m/p)
+	if m == 0 {
+		a.dropChild(entry.StartBlock, INDEX_DOUBLE)
+	}
+	return nil
+}
+
+// shrink: Memotong blocks menjadi keep blok; blok indeks ikut dibebaskan jika file tidak punya blok lagi.
+func (a indexedAllocator) shrink(entry *DirectoryEntry, blocks []BlockID, keep int) ([]BlockID, error) {
+	for len(blocks) > keep {
+		if err := a.dropLast(entry, len(blocks)-1); err != nil {
+			return blocks, err
+		}
+		blocks = blocks[:len(blocks)-1]
+	}
+	if keep == 0 && entry.StartBlock >= 0 {
+		a.fs.setFAT(entry.StartBlock, FAT_FREE)
+		entry.StartBlock = FAT_EOF
+	}
+	return blocks, nil
+}
+
+func (a indexedAllocator) BlocksNeeded(dataBlocks int) int {
+	if dataBlocks <= 0 {
+		return 0
+	}
+	p := a.perBlock()
+	meta := 1 // Blok indeks
+	if rest := dataBlocks - INDEX_DIRECT; rest > 0 {
+		meta++ // Blok single indirect
+		if rest -= p; rest > 0 {
+			meta += 1 + (rest+p-1)/p // Blok double indirect dan blok-blok single indirect di bawahnya
+		}
+	}
+	return dataBlocks + meta
+}
+
+func (a indexedAllocator) Resize(entry *DirectoryEntry, total int) ([]BlockID, error) {
+	if total > a.maxBlocks() {
+		return nil, fmt.Errorf("file terlalu besar untuk alokasi berindeks (%d blok, maks %d)", total, a.maxBlocks())
+	}
+	blocks, err := a.Blocks(*entry)
+	if err != nil {
+		return blocks, err
+	}
+	if total <= len(blocks) {
+		return a.shrink(entry, blocks, total)
+	}
+	original := len(blocks)
+	for len(blocks) < total {
+		block, err := a.appendBlock(entry, len(blocks))
+		if err != nil {
+			blocks, _ = a.shrink(entry, blocks, original)
+			return blocks, err
+		}
+		blocks = append(blocks, block)
+	}
+	return blocks, nil
+}
+
+func (a indexedAllocator) Describe(entry DirectoryEntry) (AllocationNode, error) {
+	l, err := a.layout(entry)
+	if l.Index < 0 {
+		return AllocationNode{Label: "No blocks allocated", Block: FAT_EOF}, err
+	}
+	node := AllocationNode{Label: fmt.Sprintf("Index block %d", l.Index), Block: l.Index}
+	node.Children = append(node.Children, pointerNode(fmt.Sprintf("Direct pointers (%d of %d)", len(l.Direct), INDEX_DIRECT), FAT_EOF, l.Direct))
+	if l.Single >= 0 {
+		node.Children = append(node.Children, pointerNode(fmt.Sprintf("Single indirect → block %d", l.Single), l.Single, l.SingleData))
+	} else {
+		node.Children = append(node.Children, AllocationNode{Label: "Single indirect: none", Block: FAT_EOF})
+	}
+	if l.Double >= 0 {
+		double := AllocationNode{Label: fmt.Sprintf("Double indirect → block %d", l.Double), Block: l.Double}
+		for i, inner := range l.Inner {
+			double.Children = append(double.Children, pointerNode(fmt.Sprintf("[%d] → indirect block %d", i, inner), inner, l.InnerData[i]))
+		}
+		node.Children = append(node.Children, double)
+	} else {
+		node.Children = append(node.Children, AllocationNode{Label: "Double indirect: none", Block: FAT_EOF})
+	}
+	return node, err
+}
+
+// pointerNode: Simpul untuk satu daftar pointer ke blok data.
+func pointerNode(label string, block BlockID, data []BlockID) AllocationNode {
+	node := AllocationNode{Label: label, Block: block}
+	for i, d := range data {
+		node.Children = append(node.Children, AllocationNode{Label: fmt.Sprintf("[%d] → data block %d", i, d), Block: d})
+	}
+	return node
+}
@@ -0,0 +1,188 @@
+// inode.go
+package filesystem_logic
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// Tabel inode: layout alternatif yang dipilih saat format lewat Geometry.Inodes.
+//
+// Pada layout bawaan (Geometry.Inodes == 0) semua metadata ada di DirectoryEntry di direktori induk,
+// seperti FAT. Pada layout inode, metadata (tipe, ukuran, blok awal, timestamp, izin, dan link count)
+// pindah ke tabel inode di blok-blok setelah area FAT (bertanda FAT_RESERVED, sama seperti FAT),
+// dan slot direktori hanya menyimpan nama, tipe, dan nomor inode:
+//
+//	[0:28]   nama (atau alias 8.3, lihat lfn.go)
+//	[28]     tipe (tetap di posisi yang sama agar slot LFN tetap dikenali)
+//	[29:33]  nomor inode (mulai dari 1; 0 berarti tidak ada)
+//
+// Sisa slot dibiarkan nol; ukuran slot tetap DIRECTORY_ENTRY_SIZE agar layout slot LFN sama di kedua layout.
+// Di memori keduanya tetap memakai DirectoryEntry utuh: decodeSlot mengisi field metadata dari inode
+// dan encodeSlot menulisnya kembali, sehingga API lain tidak perlu tahu layout mana yang dipakai.
+//
+// Entri "." direktori dan entrinya di direktori induk menunjuk inode yang sama, begitu juga semua hard link
+// sebuah file, sehingga syncLinks tidak diperlukan. Entri ".." menunjuk inode direktori induk dan tidak
+// pernah menulis inode tersebut (metadata induk hanya ditulis lewat entrinya sendiri).
+
+const (
+	INODE_SIZE = 64    // Ukuran satu inode di tabel (53 byte data, sisanya cadangan)
+	MAX_INODES = 65535 // Batas Geometry.Inodes
+	NO_INODE   = 0     // Nomor inode kosong; inode pertama bernomor 1 (root directory)
+)
+
+// inodeRecord: Isi satu inode di disk. Inode dengan Nlink 0 dianggap bebas.
+type inodeRecord struct {
+	Type       FileType
+	Nlink      uint16
+	Mode       FileMode
+	UID        uint16
+	GID        uint16
+	StartBlock BlockID
+	Size       int64
+	ModTime    int64
+	AccessTime int64
+	ChangeTime int64
+	BirthTime  int64
+}
+
+// inodeDirent: Slot direktori pada layout inode.
+type inodeDirent struct {
+	Name  [MAX_FILENAME_LEN]byte
+	Type  FileType
+	Inode uint32
+}
+
+// hasInodes: true jika disk diformat dengan tabel inode.
+func (fs *FileSystem) hasInodes() bool {
+	return fs.Geometry.Inodes > 0
+}
+
+// inodeBytes: Potongan disk tempat inode ino disimpan.
+func (fs *FileSystem) inodeBytes(ino uint32) ([]byte, error) {
+	if ino == NO_INODE || int(ino) > fs.Geometry.Inodes {
+		return nil, fmt.Errorf("nomor inode %d tidak valid (1 sampai %d)", ino, fs.Geometry.Inodes)
+	}
+	perBlock := fs.Geometry.BlockSize / INODE_SIZE
+	index := int(ino) - 1
+	block := fs.superblock.InodeStart + BlockID(index/perBlock)
+	offset := (index % perBlock) * INODE_SIZE
+	return fs.Disk[block][offset : offset+INODE_SIZE], nil
+}
+
+// readInode: Mengisi field metadata entry dari inode ino.
+func (fs *FileSystem) readInode(ino uint32, entry *DirectoryEntry) error {
+	raw, err := fs.inodeBytes(ino)
+	if err != nil {
+		return err
+	}
+	var rec inodeRecord
+	if err := binary.Read(bytes.NewReader(raw), binary.LittleEndian, &rec); err != nil {
+		return fmt.Errorf("gagal membaca inode %d: %w", ino, err)
+	}
+	if rec.Nlink == 0 {
+		return fmt.Errorf("inode %d tidak terpakai", ino)
+	}
+	entry.Inode = ino
+	entry.Type, entry.Nlink, entry.Mode, entry.UID, entry.GID = rec.Type, rec.Nlink, rec.Mode, rec.UID, rec.GID
+	entry.StartBlock, entry.Size = rec.StartBlock, rec.Size
+	entry.ModTime, entry.AccessTime, entry.ChangeTime, entry.BirthTime = rec.ModTime, rec.AccessTime, rec.ChangeTime, rec.BirthTime
+	return nil
+}
+
+// writeInode: Menulis field metadata entry ke inode entry.Inode.
+func (fs *FileSystem) writeInode(entry DirectoryEntry) error {
+	raw, err := fs.inodeBytes(entry.Inode)
+	if err != nil {
+		return err
+	}
+	rec := inodeRecord{Type: entry.Type, Nlink: entry.Nlink, Mode: entry.Mode, UID: entry.UID, GID: entry.GID,
+		StartBlock: entry.StartBlock, Size: entry.Size, ModTime: entry.ModTime, AccessTime: entry.AccessTime,
+		ChangeTime: entry.ChangeTime, BirthTime: entry.BirthTime}
+	buf := new(bytes.Buffer)
+	if err := binary.Write(buf, binary.LittleEndian, rec); err != nil {
+		return fmt.Errorf("gagal menulis inode %d: %w", entry.Inode, err)
+	}
+	copy(raw, buf.Bytes())
+	return nil
+}
+
+// allocInode: Mencari inode bebas untuk entri yang baru dibuat lalu langsung menulis metadatanya,
+// sehingga inode itu tidak dipilih lagi sebelum entrinya masuk direktori. Tidak melakukan apa-apa
+// pada disk tanpa tabel inode.
+func (fs *FileSystem) allocInode(entry *DirectoryEntry) error {
+	if !fs.hasInodes() {
+		return nil
+	}
+	for ino := uint32(1); int(ino) <= fs.Geometry.Inodes; ino++ {
+		raw, err := fs.inodeBytes(ino)
+		if err != nil {
+			return err
+		}
+		if binary.LittleEndian.Uint16(raw[1:3]) == 0 { // Nlink 0: inode bebas
+			entry.Inode = ino
+			return fs.writeInode(*entry)
+		}
+	}
+	return errors.New("tabel inode penuh")
+}
+
+// freeInode: Mengosongkan inode setelah link terakhirnya dihapus.
+func (fs *FileSystem) freeInode(ino uint32) {
+	if !fs.hasInodes() || ino == NO_INODE {
+		return
+	}
+	if raw, err := fs.inodeBytes(ino); err == nil {
+		for i := range raw {
+			raw[i] = 0
+		}
+	}
+}
+
+// InodeUsage: Jumlah inode terpakai dan total inode (keduanya 0 jika disk tidak memakai tabel inode).
+func (fs *FileSystem) InodeUsage() (used, total int) {
+	for ino := uint32(1); int(ino) <= fs.Geometry.Inodes; ino++ {
+		if raw, err := fs.inodeBytes(ino); err == nil && binary.LittleEndian.Uint16(raw[1:3]) != 0 {
+			used++
+		}
+	}
+	return used, fs.Geometry.Inodes
+}
+
+// decodeSlot: Membaca entri dari byte slot direktori sesuai layout disk.
+func (fs *FileSystem) decodeSlot(raw []byte) (DirectoryEntry, error) {
+	if !fs.hasInodes() {
+		return DeserializeEntry(raw)
+	}
+	var dirent inodeDirent
+	if err := binary.Read(bytes.NewReader(raw), binary.LittleEndian, &dirent); err != nil {
+		return DirectoryEntry{}, fmt.Errorf("deserialize dirent: %w", err)
+	}
+	entry := DirectoryEntry{Name: dirent.Name}
+	if err := fs.readInode(dirent.Inode, &entry); err != nil {
+		return entry, fmt.Errorf("entri '%s': %w", entry.ShortName(), err)
+	}
+	return entry, nil
+}
+
+// encodeSlot: Mengubah entri menjadi byte slot direktori sesuai layout disk. Pada layout inode,
+// metadata entri sekaligus ditulis ke inode-nya (kecuali untuk "..", lihat komentar di atas).
+func (fs *FileSystem) encodeSlot(entry DirectoryEntry) ([]byte, error) {
+	if !fs.hasInodes() {
+		return entry.Serialize()
+	}
+	if entry.ShortName() != ".." {
+		if err := fs.writeInode(entry); err != nil {
+			return nil, err
+		}
+	}
+	buf := new(bytes.Buffer)
+	if err := binary.Write(buf, binary.LittleEndian, inodeDirent{Name: entry.Name, Type: entry.Type, Inode: entry.Inode}); err != nil {
+		return nil, fmt.Errorf("serialize dirent: %w", err)
+	}
+	slot := make([]byte, DIRECTORY_ENTRY_SIZE)
+	copy(slot, buf.Bytes())
+	return slot, nil
+}
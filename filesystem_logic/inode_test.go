@@ -0,0 +1,79 @@
+package filesystem_logic
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+// Inode dialokasikan saat entri dibuat dan dibebaskan bersama bloknya. Jika tabel inode penuh,
+// entri baru ditolak walaupun blok data masih tersedia.
+func TestInodeAllocation(t *testing.T) {
+	fs := newTestDisk(t, Geometry{Inodes: 4})
+	if used, total := fs.InodeUsage(); used != 1 || total != 4 {
+		t.Fatalf("InodeUsage setelah format = %d/%d, seharusnya 1/4 (root)", used, total)
+	}
+	free := fs.countFreeBlocks()
+	fs.Mkdir("/d")
+	fs.WriteFile("/d/a", []byte("a"))
+	fs.WriteFile("/b", []byte("b"))
+	if used, _ := fs.InodeUsage(); used != 4 {
+		t.Fatalf("%d inode terpakai, seharusnya 4", used)
+	}
+	if err := fs.Create("/c"); err == nil {
+		t.Fatal("Create seharusnya gagal saat tabel inode penuh")
+	}
+
+	// Hard link memakai inode yang sama
+	if err := fs.Link("/b", "/d/b"); err != nil {
+		t.Fatal(err)
+	}
+	if used, _ := fs.InodeUsage(); used != 4 {
+		t.Fatalf("%d inode terpakai setelah Link, seharusnya tetap 4", used)
+	}
+	if err := fs.Append("/d/b", []byte("-lagi")); err != nil {
+		t.Fatal(err)
+	}
+	checkLinks(t, fs, "b-lagi", "/b", "/d/b")
+
+	for _, p := range []string{"/b", "/d/b", "/d/a", "/d"} {
+		if err := fs.Remove(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if used, _ := fs.InodeUsage(); used != 1 || fs.countFreeBlocks() != free {
+		t.Fatalf("%d inode dan %d blok kosong setelah semua dihapus, seharusnya 1 dan %d", used, fs.countFreeBlocks(), free)
+	}
+}
+
+// Metadata di tabel inode ikut tersimpan di image, dan layout inode dibaca dari superblock.
+func TestInodeImageRoundTrip(t *testing.T) {
+	geo := Geometry{Inodes: 32}
+	fs := newTestDisk(t, geo)
+	fs.Mkdir("/d")
+	if err := fs.WriteFile("/d/f", []byte("isi")); err != nil {
+		t.Fatal(err)
+	}
+	if err := fs.Chmod("/d/f", 0o600); err != nil {
+		t.Fatal(err)
+	}
+	want := mustLookup(t, fs, "/d/f")
+
+	image := filepath.Join(t.TempDir(), "disk.img")
+	if err := fs.SaveImage(image); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := NewFileSystem(FileSystemOptions{ImagePath: image})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Geometry != geo.WithDefaults() {
+		t.Fatalf("geometri setelah dimuat: %+v", loaded.Geometry)
+	}
+	got := mustLookup(t, loaded, "/d/f")
+	if got.Inode != want.Inode || got.Mode != 0o600 || got.Size != 3 || got.ModTime != want.ModTime || got.BirthTime != want.BirthTime {
+		t.Fatalf("entri setelah dimuat: %+v, seharusnya %+v", got, want)
+	}
+	if used, _ := loaded.InodeUsage(); used != 3 {
+		t.Fatalf("%d inode terpakai setelah dimuat, seharusnya 3", used)
+	}
+}
@@ -0,0 +1,227 @@
+// inspect.go
+package filesystem_logic
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf16"
+)
+
+// Inspector blok: akses mentah ke isi fs.Disk dan decode slot direktori per field, untuk men-debug
+// serialisasi DirectoryEntry dan untuk menyuntikkan kerusakan secara sengaja dari GUI.
+
+// ReadBlock: Salinan isi mentah blok block.
+func (fs *FileSystem) ReadBlock(block BlockID) ([]byte, error) {
+	if !fs.validBlock(block) {
+		return nil, fmt.Errorf("nomor blok tidak valid: %d", block)
+	}
+	return append([]byte(nil), fs.Disk[block]...), nil
+}
+
+// PatchBlock: Menimpa isi blok block mulai dari offset dengan data, langsung di fs.Disk tanpa lewat lapisan
+// file system mana pun (tidak ada validasi, tidak ada pembaruan waktu atau FreeSpaceManager).
+//
+// Direktori, tabel inode, blok indeks, dan struktur ruang kosong selalu dibaca dari disk sehingga perubahannya
+// langsung terlihat. Jika blok yang diubah termasuk FAT salinan pertama, entri FAT di memori ikut dibaca ulang
+// apa adanya (juga nilai yang tidak valid), sedangkan mirror tidak disentuh. Superblock di memori tidak ikut
+// berubah: perubahan di blok 0 tertimpa lagi pada penulisan superblock berikutnya.
+func (fs *FileSystem) PatchBlock(block BlockID, offset int, data []byte) error {
+	if !fs.validBlock(block) {
+		return fmt.Errorf("nomor blok tidak valid: %d", block)
+	}
+	if offset < 0 || offset+len(data) > fs.Geometry.BlockSize {
+		return fmt.Errorf("offset %d dengan %d byte di luar blok (ukuran blok %d)", offset, len(data), fs.Geometry.BlockSize)
+	}
+	copy(fs.Disk[block][offset:], data)
+
+	fatStart := BlockID(fs.superblock.FATStart)
+	if block >= fatStart && block < fatStart+BlockID(fs.superblock.FATBlocks) {
+		entriesPerBlock := fs.Geometry.BlockSize / FAT_ENTRY_SIZE
+		first := int(block-fatStart) * entriesPerBlock
+		for i := offset / FAT_ENTRY_SIZE; i*FAT_ENTRY_SIZE < offset+len(data); i++ {
+			if first+i < len(fs.FAT) {
+				fs.FAT[first+i] = BlockID(int32(binary.LittleEndian.Uint32(fs.Disk[block][i*FAT_ENTRY_SIZE:])))
+			}
+		}
+	}
+	return nil
+}
+
+// SlotKind: Jenis isi satu slot direktori.
+type SlotKind int
+
+const (
+	SLOT_EMPTY   SlotKind = iota // Byte pertama 0: slot kosong atau entri yang sudah dihapus
+	SLOT_ENTRY                   // Entri pendek (file, direktori, atau symlink)
+	SLOT_LFN                     // Potongan nama panjang (lihat lfn.go)
+	SLOT_INVALID                 // Tidak bisa di-decode (misalnya inode yang dirujuk tidak terpakai)
+)
+
+func (k SlotKind) String() string {
+	switch k {
+	case SLOT_ENTRY:
+		return "entry"
+	case SLOT_LFN:
+		return "long name"
+	case SLOT_INVALID:
+		return "invalid"
+	default:
+		return "empty"
+	}
+}
+
+// SlotField: Satu field hasil decode slot beserta posisinya.
+type SlotField struct {
+	Name   string
+	Offset int // Posisi byte di dalam slot, -1 jika field disimpan di tabel inode
+	Size   int
+	Value  string
+}
+
+// DirectorySlot: Satu slot DIRECTORY_ENTRY_SIZE byte di blok direktori.
+type DirectorySlot struct {
+	Offset int // Posisi slot di dalam blok
+	Kind   SlotKind
+	Fields []SlotField
+	Err    error // Alasan slot tidak bisa di-decode (Kind SLOT_INVALID)
+}
+
+// DirectorySlots: Decode setiap slot di block sebagai slot direktori, sesuai layout disk ini. Slot dibaca
+// satu per satu apa adanya: run LFN tidak dirakit dan entri yang rusak tetap ditampilkan. Pemanggil yang
+// menentukan apakah blok memang milik direktori (lihat BlockMap).
+func (fs *FileSystem) DirectorySlots(block BlockID) ([]DirectorySlot, error) {
+	if !fs.validBlock(block) {
+		return nil, fmt.Errorf("nomor blok tidak valid: %d", block)
+	}
+	var slots []DirectorySlot
+	for offset := 0; offset+DIRECTORY_ENTRY_SIZE <= fs.Geometry.BlockSize; offset += DIRECTORY_ENTRY_SIZE {
+		raw := fs.Disk[block][offset : offset+DIRECTORY_ENTRY_SIZE]
+		slot := DirectorySlot{Offset: offset}
+		switch {
+		case raw[0] == 0:
+			slot.Kind = SLOT_EMPTY
+		case FileType(raw[LFN_TYPE_OFFSET]) == TYPE_LFN:
+			slot.Kind, slot.Fields = SLOT_LFN, lfnFields(raw)
+		default:
+			slot.Kind = SLOT_ENTRY
+			slot.Fields, slot.Err = fs.entryFields(raw)
+			if slot.Err != nil {
+				slot.Kind = SLOT_INVALID
+			}
+		}
+		slots = append(slots, slot)
+	}
+	return slots, nil
+}
+
+// entryFields: Field entri pendek. Tanpa tabel inode semua field ada di slot (urutan Serialize); dengan
+// tabel inode slot hanya berisi nama, tipe, dan nomor inode, sisanya dibaca dari inode-nya.
+func (fs *FileSystem) entryFields(raw []byte) ([]SlotField, error) {
+	var name [MAX_FILENAME_LEN]byte
+	copy(name[:], raw)
+	fields := []SlotField{
+		{"Name", 0, MAX_FILENAME_LEN, fmt.Sprintf("%q", strings.TrimRight(string(name[:]), "\x00"))},
+		{"Type", MAX_FILENAME_LEN, 1, fileTypeText(FileType(raw[MAX_FILENAME_LEN]))},
+	}
+	if fs.hasInodes() {
+		ino := binary.LittleEndian.Uint32(raw[MAX_FILENAME_LEN+1:])
+		fields = append(fields, SlotField{"Inode", MAX_FILENAME_LEN + 1, 4, fmt.Sprint(ino)})
+		entry := DirectoryEntry{Name: name}
+		if err := fs.readInode(ino, &entry); err != nil {
+			return fields, err
+		}
+		for _, field := range metadataFields(entry) {
+			field.Offset = -1
+			fields = append(fields, field)
+		}
+		return fields, nil
+	}
+	entry, err := DeserializeEntry(raw)
+	if err != nil {
+		return fields, err
+	}
+	return append(fields[:2], metadataFields(entry)...), nil
+}
+
+// metadataFields: Field setelah Name dan Type, dengan offset sesuai urutan Serialize.
+func metadataFields(entry DirectoryEntry) []SlotField {
+	stamp := func(nanos int64) string {
+		return fmt.Sprintf("%d (%s)", nanos, time.Unix(0, nanos).Format("2006-01-02 15:04:05"))
+	}
+	fields := []SlotField{
+		{"StartBlock", 0, 4, blockIDText(entry.StartBlock)},
+		{"Size", 0, 8, fmt.Sprint(entry.Size)},
+		{"ModTime", 0, 8, stamp(entry.ModTime)},
+		{"Mode", 0, 2, fmt.Sprintf("%s (%#o)", entry.Mode, uint16(entry.Mode))},
+		{"UID", 0, 2, fmt.Sprint(entry.UID)},
+		{"GID", 0, 2, fmt.Sprint(entry.GID)},
+		{"AccessTime", 0, 8, stamp(entry.AccessTime)},
+		{"ChangeTime", 0, 8, stamp(entry.ChangeTime)},
+		{"BirthTime", 0, 8, stamp(entry.BirthTime)},
+		{"Nlink", 0, 2, fmt.Sprint(entry.Nlink)},
+	}
+	offset := MAX_FILENAME_LEN + 1
+	for i := range fields {
+		fields[i].Offset = offset
+		offset += fields[i].Size
+	}
+	return fields
+}
+
+// lfnFields: Field slot LFN; karakter setiap bagian dibaca sampai terminator 0x0000.
+func lfnFields(raw []byte) []SlotField {
+	seq := fmt.Sprint(raw[0] & LFN_SEQ_MASK)
+	if raw[0]&LFN_LAST != 0 {
+		seq += " (last)"
+	}
+	ended := false
+	part := func(from, to int) string {
+		var units []uint16
+		for k := from; k < to && !ended; k++ {
+			unit := binary.LittleEndian.Uint16(raw[lfnCharOffset(k):])
+			if unit == 0 {
+				ended = true
+				break
+			}
+			units = append(units, unit)
+		}
+		return fmt.Sprintf("%q", string(utf16.Decode(units)))
+	}
+	return []SlotField{
+		{"Sequence", 0, 1, seq},
+		{"Name part 1", LFN_PART1_OFFSET, 2 * LFN_PART1_CHARS, part(0, LFN_PART1_CHARS)},
+		{"Type", LFN_TYPE_OFFSET, 1, fileTypeText(TYPE_LFN)},
+		{"Checksum", LFN_CHECKSUM_OFFSET, 1, fmt.Sprintf("0x%02X", raw[LFN_CHECKSUM_OFFSET])},
+		{"Name part 2", LFN_PART2_OFFSET, 2 * LFN_PART2_CHARS, part(LFN_PART1_CHARS, LFN_CHARS_PER_SLOT)},
+	}
+}
+
+// fileTypeText: Nilai byte tipe dalam bentuk yang mudah dibaca.
+func fileTypeText(t FileType) string {
+	switch t {
+	case TYPE_FILE:
+		return "0 (file)"
+	case TYPE_DIRECTORY:
+		return "1 (directory)"
+	case TYPE_SYMLINK:
+		return "2 (symlink)"
+	case TYPE_LFN:
+		return "0x0F (long name)"
+	}
+	return fmt.Sprintf("%d (unknown)", t)
+}
+
+// blockIDText: Nomor blok, atau namanya jika berupa penanda FAT.
+func blockIDText(block BlockID) string {
+	switch block {
+	case FAT_EOF:
+		return "-1 (EOF)"
+	case FAT_FREE:
+		return "-2 (FREE)"
+	case FAT_RESERVED:
+		return "-3 (RESERVED)"
+	}
+	return fmt.Sprint(block)
+}
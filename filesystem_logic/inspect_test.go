@@ -0,0 +1,129 @@
+package filesystem_logic
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// DirectorySlots membaca setiap slot apa adanya: entri, potongan nama panjang, dan slot kosong, di kedua
+// layout direktori. Slot yang merujuk inode yang tidak terpakai ditandai SLOT_INVALID.
+func TestDirectorySlots(t *testing.T) {
+	for _, inodes := range []int{0, 32} {
+		t.Run(fmt.Sprintf("inodes=%d", inodes), func(t *testing.T) {
+			fs := newTestDisk(t, Geometry{BlockSize: 512, MaxFilenameLen: 12, Inodes: inodes}) // 6 slot per blok
+			if err := fs.WriteFile("/nama yang panjang.txt", []byte("isi")); err != nil {
+				t.Fatal(err)
+			}
+			slots, err := fs.DirectorySlots(fs.RootDirBlock)
+			if err != nil {
+				t.Fatal(err)
+			}
+			kinds := []SlotKind{SLOT_ENTRY, SLOT_ENTRY, SLOT_LFN}
+			if len(slots) != fs.Geometry.EntriesPerBlock() || len(slots) < len(kinds) {
+				t.Fatalf("%d slot di blok root", len(slots))
+			}
+			for i, kind := range kinds {
+				if slots[i].Kind != kind {
+					t.Fatalf("slot %d: %s, seharusnya %s", i, slots[i].Kind, kind)
+				}
+			}
+			field := func(slot DirectorySlot, name string) SlotField {
+				t.Helper()
+				for _, f := range slot.Fields {
+					if f.Name == name {
+						return f
+					}
+				}
+				t.Fatalf("slot di offset %d tidak punya field %s", slot.Offset, name)
+				return SlotField{}
+			}
+			if v := field(slots[0], "Name").Value; v != `"."` {
+				t.Fatalf("nama slot pertama %s", v)
+			}
+			if v := field(slots[0], "Type").Value; v != "1 (directory)" {
+				t.Fatalf("tipe slot pertama %s", v)
+			}
+			if v := field(slots[2], "Name part 1").Value; !strings.HasPrefix(v, `"nama yang pan`) {
+				t.Fatalf("potongan nama panjang %s", v)
+			}
+
+			// Entri pendek setelah slot LFN
+			if len(slots) < 4 || slots[3].Kind != SLOT_ENTRY {
+				t.Fatalf("slot 3 bukan entri pendek")
+			}
+			size := field(slots[3], "Size")
+			if size.Value != "3" {
+				t.Fatalf("Size = %s", size.Value)
+			}
+			if last := slots[3].Fields[len(slots[3].Fields)-1]; inodes == 0 && last.Offset+last.Size != DIRECTORY_ENTRY_SIZE {
+				t.Fatalf("field terakhir berakhir di byte %d, seharusnya %d", last.Offset+last.Size, DIRECTORY_ENTRY_SIZE)
+			}
+			if inodes > 0 && size.Offset != -1 {
+				t.Fatalf("Size pada layout inode ada di offset %d, seharusnya -1 (tabel inode)", size.Offset)
+			}
+			for _, slot := range slots[4:] {
+				if slot.Kind != SLOT_EMPTY {
+					t.Fatalf("slot di offset %d: %s, seharusnya kosong", slot.Offset, slot.Kind)
+				}
+			}
+
+			if inodes > 0 {
+				unused := make([]byte, 4)
+				binary.LittleEndian.PutUint32(unused, uint32(inodes))
+				if err := fs.PatchBlock(fs.RootDirBlock, slots[3].Offset+MAX_FILENAME_LEN+1, unused); err != nil {
+					t.Fatal(err)
+				}
+				if slots, _ = fs.DirectorySlots(fs.RootDirBlock); slots[3].Kind != SLOT_INVALID || slots[3].Err == nil {
+					t.Fatalf("slot dengan inode yang tidak terpakai: %s, %v", slots[3].Kind, slots[3].Err)
+				}
+			}
+		})
+	}
+}
+
+// PatchBlock menulis langsung ke disk: perubahan di FAT salinan pertama ikut dibaca ke fs.FAT, mirror tidak
+// disentuh, dan ReadBlock mengembalikan salinan yang aman diubah.
+func TestPatchBlock(t *testing.T) {
+	fs := newTestDisk(t, Geometry{})
+	fatStart := BlockID(fs.superblock.FATStart)
+	mirror := fatStart + BlockID(fs.superblock.FATBlocks)
+	target := fs.RootDirBlock + 1
+
+	bad := make([]byte, FAT_ENTRY_SIZE)
+	binary.LittleEndian.PutUint32(bad, 9999)
+	if err := fs.PatchBlock(fatStart, int(target)*FAT_ENTRY_SIZE, bad); err != nil {
+		t.Fatal(err)
+	}
+	if fs.FAT[target] != 9999 {
+		t.Fatalf("FAT[%d] = %d setelah PatchBlock, seharusnya 9999", target, fs.FAT[target])
+	}
+	raw, err := fs.ReadBlock(mirror)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := BlockID(int32(binary.LittleEndian.Uint32(raw[int(target)*FAT_ENTRY_SIZE:]))); got != FAT_FREE {
+		t.Fatalf("mirror FAT ikut berubah menjadi %d", got)
+	}
+
+	raw[0] ^= 0xff
+	if again, _ := fs.ReadBlock(mirror); again[0] == raw[0] {
+		t.Fatal("ReadBlock mengembalikan slice yang sama dengan fs.Disk")
+	}
+
+	for _, c := range []struct {
+		block  BlockID
+		offset int
+		n      int
+	}{
+		{BlockID(fs.Geometry.TotalBlocks), 0, 1},
+		{-1, 0, 1},
+		{target, -1, 1},
+		{target, fs.Geometry.BlockSize - 1, 2},
+	} {
+		if err := fs.PatchBlock(c.block, c.offset, make([]byte, c.n)); err == nil {
+			t.Errorf("PatchBlock(%d, %d, %d byte) seharusnya ditolak", c.block, c.offset, c.n)
+		}
+	}
+}
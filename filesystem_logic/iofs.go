@@ -0,0 +1,194 @@
+// iofs.go
+package filesystem_logic
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	iofs "io/fs" // Dialias karena receiver FileSystem di package ini bernama fs
+	"path"
+	"sort"
+	"time"
+)
+
+// IOFS: Adapter agar disk simulasi bisa dipakai oleh library standar Go
+// (fs.WalkDir, fs.Glob, http.FS, template.ParseFS, ...).
+// Mengimplementasikan fs.FS, fs.ReadDirFS, fs.StatFS, dan fs.ReadFileFS.
+//
+// Path mengikuti aturan io/fs: relatif terhadap root disk, dipisah "/", tanpa "/" di awal,
+// dan "." berarti root. Direktori kerja FileSystem tidak dipakai maupun diubah.
+type IOFS struct {
+	fs *FileSystem
+}
+
+var (
+	_ iofs.FS         = (*IOFS)(nil)
+	_ iofs.ReadDirFS  = (*IOFS)(nil)
+	_ iofs.StatFS     = (*IOFS)(nil)
+	_ iofs.ReadFileFS = (*IOFS)(nil)
+)
+
+// NewIOFS: Membuat adapter io/fs untuk sebuah FileSystem.
+func NewIOFS(fs *FileSystem) *IOFS {
+	return &IOFS{fs: fs}
+}
+
+// lookup: Memvalidasi nama sesuai aturan io/fs lalu mencari entrinya di disk.
+// Error dari FileSystem diterjemahkan ke error standar io/fs (fs.ErrNotExist, fs.ErrInvalid).
+func (f *IOFS) lookup(op, name string) (DirectoryEntry, error) {
+	if !iofs.ValidPath(name) {
+		return DirectoryEntry{}, &iofs.PathError{Op: op, Path: name, Err: iofs.ErrInvalid}
+	}
+	entry, err := f.fs.Lookup("/" + name)
+	if err != nil {
+		if errors.Is(err, ErrNotExist) || errors.Is(err, ErrNotDir) {
+			err = iofs.ErrNotExist
+		}
+		return DirectoryEntry{}, &iofs.PathError{Op: op, Path: name, Err: err}
+	}
+	return entry, nil
+}
+
+// readDirEntries: Isi direktori tanpa "." dan "..", diurutkan berdasarkan nama seperti yang diminta io/fs.
+func (f *IOFS) readDirEntries(op, name string, entry DirectoryEntry) ([]iofs.DirEntry, error) {
+	entries, err := f.fs.ListEntries(entry.StartBlock)
+	if err != nil {
+		return nil, &iofs.PathError{Op: op, Path: name, Err: err}
+	}
+	var list []iofs.DirEntry
+	for _, child := range entries {
+		childName := child.NameString()
+		if childName == "." || childName == ".." {
+			continue
+		}
+		list = append(list, iofs.FileInfoToDirEntry(&fileInfo{name: childName, entry: child}))
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name() < list[j].Name() })
+	return list, nil
+}
+
+// Open: Membuka file atau direktori untuk dibaca (implementasi fs.FS).
+func (f *IOFS) Open(name string) (iofs.File, error) {
+	entry, err := f.lookup("open", name)
+	if err != nil {
+		return nil, err
+	}
+	info := &fileInfo{name: path.Base(name), entry: entry}
+	if entry.Type == TYPE_DIRECTORY {
+		entries, err := f.readDirEntries("open", name, entry)
+		if err != nil {
+			return nil, err
+		}
+		return &ioDir{info: info, path: name, entries: entries}, nil
+	}
+	data, err := f.fs.ReadFile("/" + name)
+	if err != nil {
+		return nil, &iofs.PathError{Op: "open", Path: name, Err: err}
+	}
+	return &ioFile{info: info, Reader: bytes.NewReader(data)}, nil
+}
+
+// ReadDir: Membaca isi direktori, terurut berdasarkan nama (implementasi fs.ReadDirFS).
+func (f *IOFS) ReadDir(name string) ([]iofs.DirEntry, error) {
+	entry, err := f.lookup("readdir", name)
+	if err != nil {
+		return nil, err
+	}
+	if entry.Type != TYPE_DIRECTORY {
+		return nil, &iofs.PathError{Op: "readdir", Path: name, Err: ErrNotDir}
+	}
+	return f.readDirEntries("readdir", name, entry)
+}
+
+// Stat: Informasi file atau direktori tanpa membukanya (implementasi fs.StatFS).
+func (f *IOFS) Stat(name string) (iofs.FileInfo, error) {
+	entry, err := f.lookup("stat", name)
+	if err != nil {
+		return nil, err
+	}
+	return &fileInfo{name: path.Base(name), entry: entry}, nil
+}
+
+// ReadFile: Membaca seluruh isi file (implementasi fs.ReadFileFS).
+// Slice yang dikembalikan selalu salinan baru, jadi boleh diubah pemanggil.
+func (f *IOFS) ReadFile(name string) ([]byte, error) {
+	entry, err := f.lookup("readfile", name)
+	if err != nil {
+		return nil, err
+	}
+	if entry.Type == TYPE_DIRECTORY {
+		return nil, &iofs.PathError{Op: "readfile", Path: name, Err: ErrIsDir}
+	}
+	data, err := f.fs.ReadFile("/" + name)
+	if err != nil {
+		return nil, &iofs.PathError{Op: "readfile", Path: name, Err: err}
+	}
+	return data, nil
+}
+
+// fileInfo: fs.FileInfo yang diturunkan dari DirectoryEntry.
+type fileInfo struct {
+	name  string
+	entry DirectoryEntry
+}
+
+func (fi *fileInfo) Name() string       { return fi.name }
+func (fi *fileInfo) Size() int64        { return fi.entry.Size }
+func (fi *fileInfo) ModTime() time.Time { return time.Unix(0, fi.entry.ModTime) }
+func (fi *fileInfo) IsDir() bool        { return fi.entry.Type == TYPE_DIRECTORY }
+func (fi *fileInfo) Sys() any           { return fi.entry } // DirectoryEntry aslinya
+
+// Mode: Bit izin entri (lihat permission.go), ditambah ModeDir untuk direktori atau ModeSymlink untuk symlink.
+func (fi *fileInfo) Mode() iofs.FileMode {
+	mode := iofs.FileMode(fi.entry.Mode & MODE_PERM_MASK)
+	switch fi.entry.Type {
+	case TYPE_DIRECTORY:
+		return iofs.ModeDir | mode
+	case TYPE_SYMLINK:
+		return iofs.ModeSymlink | mode
+	}
+	return mode
+}
+
+// ioFile: File yang dibuka lewat IOFS. Isinya dibaca sekali saat Open,
+// jadi Read/Seek/ReadAt disediakan oleh bytes.Reader.
+type ioFile struct {
+	info *fileInfo
+	*bytes.Reader
+}
+
+func (f *ioFile) Stat() (iofs.FileInfo, error) { return f.info, nil }
+func (f *ioFile) Close() error                 { return nil }
+
+// ioDir: Direktori yang dibuka lewat IOFS (implementasi fs.ReadDirFile).
+type ioDir struct {
+	info    *fileInfo
+	path    string
+	entries []iofs.DirEntry
+	offset  int // Jumlah entri yang sudah dikembalikan ReadDir
+}
+
+func (d *ioDir) Stat() (iofs.FileInfo, error) { return d.info, nil }
+func (d *ioDir) Close() error                 { return nil }
+
+func (d *ioDir) Read([]byte) (int, error) {
+	return 0, &iofs.PathError{Op: "read", Path: d.path, Err: ErrIsDir}
+}
+
+// ReadDir: Jika n > 0, mengembalikan paling banyak n entri berikutnya dan io.EOF jika sudah habis.
+// Jika n <= 0, mengembalikan semua entri yang tersisa sekaligus.
+func (d *ioDir) ReadDir(n int) ([]iofs.DirEntry, error) {
+	remaining := d.entries[d.offset:]
+	if n <= 0 {
+		d.offset = len(d.entries)
+		return remaining, nil
+	}
+	if len(remaining) == 0 {
+		return nil, io.EOF
+	}
+	if n > len(remaining) {
+		n = len(remaining)
+	}
+	d.offset += n
+	return remaining[:n], nil
+}
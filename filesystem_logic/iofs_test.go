@@ -0,0 +1,60 @@
+package filesystem_logic
+
+import (
+	"bytes"
+	"fmt"
+	iofs "io/fs"
+	"testing"
+	"testing/fstest"
+)
+
+// IOFS harus lolos fstest.TestFS di setiap metode alokasi, dengan dan tanpa tabel inode.
+func TestIOFSConformance(t *testing.T) {
+	for _, alloc := range AllocationMethods {
+		for _, inodes := range []int{0, 32} {
+			t.Run(fmt.Sprintf("%s/inodes=%d", alloc, inodes), func(t *testing.T) {
+				fs, err := NewFileSystem(FileSystemOptions{Geometry: Geometry{Allocation: alloc, Inodes: inodes}})
+				if err != nil {
+					t.Fatal(err)
+				}
+				for _, dir := range []string{"/a", "/a/b", "/empty"} {
+					if err := fs.Mkdir(dir); err != nil {
+						t.Fatal(err)
+					}
+				}
+				files := map[string][]byte{
+					"/a/b/c.txt":                         []byte("hello world"),
+					"/x.txt":                             bytes.Repeat([]byte("0123456789"), 100), // Lebih dari satu blok
+					"/Laporan Tahunan Keuangan 2024.txt": []byte("nama panjang"),                  // Lebih dari 28 karakter: disimpan dengan slot LFN
+				}
+				for name, data := range files {
+					if err := fs.WriteFile(name, data); err != nil {
+						t.Fatal(err)
+					}
+				}
+				if err := fs.Create("/zero"); err != nil {
+					t.Fatal(err)
+				}
+				for i := 0; i < 8; i++ { // Cukup banyak entri agar direktori /a memakai lebih dari satu blok
+					if err := fs.Create(fmt.Sprintf("/a/f%d", i)); err != nil {
+						t.Fatal(err)
+					}
+				}
+
+				fsys := NewIOFS(fs)
+				if err := fstest.TestFS(fsys, "a/b/c.txt", "x.txt", "Laporan Tahunan Keuangan 2024.txt", "zero", "empty", "a/f7"); err != nil {
+					t.Fatal(err)
+				}
+				for name, want := range files {
+					got, err := iofs.ReadFile(fsys, name[1:])
+					if err != nil {
+						t.Fatal(err)
+					}
+					if !bytes.Equal(got, want) {
+						t.Errorf("isi %s: %q, seharusnya %q", name, got, want)
+					}
+				}
+			})
+		}
+	}
+}
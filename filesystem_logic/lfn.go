@@ -0,0 +1,376 @@
+// lfn.go
+package filesystem_logic
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// Nama panjang (Long File Name) gaya VFAT. Nama yang tidak muat sebagai nama pendek (lebih panjang dari
+// Geometry.MaxFilenameLen atau berisi karakter non-ASCII) disimpan dalam beberapa slot LFN berurutan
+// tepat sebelum entri pendeknya. Field Name entri pendek berisi alias 8.3 yang unik, misalnya "LONGFI~1.TXT".
+//
+// Layout satu slot LFN (DIRECTORY_ENTRY_SIZE byte):
+//
+//	[0]      nomor urut 1..N; slot pertama di disk (nomor N) ditandai LFN_LAST
+//	[1:27]   13 karakter UTF-16 pertama
+//	[28]     TYPE_LFN di posisi byte tipe, sehingga slot ini tidak pernah terbaca sebagai entri biasa
+//	[29]     checksum alias (shortNameChecksum), mengikat slot ke entri pendeknya
+//	[30:80]  25 karakter UTF-16 berikutnya
+//
+// Nama diakhiri 0x0000 lalu sisa slot diisi 0xFFFF. Slot LFN yang urutannya rusak atau checksum-nya
+// tidak cocok dengan entri pendek berikutnya diabaikan, sehingga entri tetap terbaca dengan aliasnya.
+
+const (
+	TYPE_LFN            FileType = 0x0F // Penanda slot LFN di byte tipe
+	LFN_LAST                     = 0x40 // Bit di nomor urut: slot LFN terakhir (yang tersimpan paling depan)
+	LFN_SEQ_MASK                 = 0x3F
+	LFN_TYPE_OFFSET              = MAX_FILENAME_LEN
+	LFN_CHECKSUM_OFFSET          = MAX_FILENAME_LEN + 1
+	LFN_PART1_OFFSET             = 1
+	LFN_PART1_CHARS              = (MAX_FILENAME_LEN - LFN_PART1_OFFSET) / 2     // 13 karakter sebelum byte tipe
+	LFN_PART2_OFFSET             = MAX_FILENAME_LEN + 2                          // Setelah byte tipe dan checksum
+	LFN_PART2_CHARS              = (DIRECTORY_ENTRY_SIZE - LFN_PART2_OFFSET) / 2 // 25 karakter sampai akhir slot
+	LFN_CHARS_PER_SLOT           = LFN_PART1_CHARS + LFN_PART2_CHARS             // 38 karakter UTF-16 per slot
+	MAX_LONG_NAME_LEN            = 255                                           // Panjang nama maksimum dalam karakter UTF-16, seperti VFAT
+)
+
+// ShortName: Isi field Name apa adanya: nama pendek, atau alias 8.3 jika entri punya nama panjang.
+func (de *DirectoryEntry) ShortName() string {
+	if idx := bytes.IndexByte(de.Name[:], 0); idx != -1 {
+		return string(de.Name[:idx])
+	}
+	return string(de.Name[:])
+}
+
+// sameName: Perbandingan dua nama entri. Semua pencarian entri berdasarkan nama lewat fungsi ini
+// (langsung atau lewat matchesName) agar aturan perbandingan nama hanya ada di satu tempat.
+// Nama dibandingkan persis (case-sensitive), seperti di Unix.
+func sameName(a, b string) bool {
+	return a == b
+}
+
+// matchesName: true jika name sama dengan nama panjang entri atau dengan alias pendeknya.
+func (de *DirectoryEntry) matchesName(name string) bool {
+	return sameName(de.NameString(), name) || (de.LongName != "" && sameName(de.ShortName(), name))
+}
+
+// validateName: Memeriksa nama entri baru: tidak kosong, UTF-8 valid, tanpa '/' atau NUL,
+// dan paling panjang MAX_LONG_NAME_LEN karakter UTF-16.
+func validateName(name string) error {
+	switch {
+	case name == "":
+		return errors.New("nama tidak boleh kosong")
+	case strings.ContainsAny(name, "/\x00"):
+		return fmt.Errorf("nama '%s' tidak boleh mengandung '/' atau karakter NUL", name)
+	case !utf8.ValidString(name):
+		return fmt.Errorf("nama %q bukan UTF-8 yang valid", name)
+	case len(utf16.Encode([]rune(name))) > MAX_LONG_NAME_LEN:
+		return fmt.Errorf("nama '%s' terlalu panjang (maks %d karakter)", name, MAX_LONG_NAME_LEN)
+	}
+	return nil
+}
+
+// fitsShortName: true jika name bisa disimpan langsung di field Name tanpa slot LFN
+// (ASCII yang bisa dicetak dan tidak lebih panjang dari Geometry.MaxFilenameLen).
+func (fs *FileSystem) fitsShortName(name string) bool {
+	if len(name) > fs.Geometry.MaxFilenameLen {
+		return false
+	}
+	for i := 0; i < len(name); i++ {
+		if name[i] < 0x20 || name[i] > 0x7E {
+			return false
+		}
+	}
+	return true
+}
+
+// slotsForName: Jumlah slot direktori yang dipakai entri bernama name (slot LFN ditambah entri pendeknya).
+func (fs *FileSystem) slotsForName(name string) int {
+	if fs.fitsShortName(name) {
+		return 1
+	}
+	units := len(utf16.Encode([]rune(name)))
+	return (units+LFN_CHARS_PER_SLOT-1)/LFN_CHARS_PER_SLOT + 1
+}
+
+// shortAlias: Alias 8.3 ke-n untuk nama panjang: huruf besar, karakter yang tidak valid di nama 8.3 diganti '_',
+// spasi dan titik dibuang, enam karakter pertama ditambah "~n", lalu tiga karakter ekstensi
+// ("longfilename.txt" menjadi "LONGFI~1.TXT").
+func shortAlias(name string, n int) string {
+	base, ext := name, ""
+	if idx := strings.LastIndexByte(name, '.'); idx > 0 {
+		base, ext = name[:idx], name[idx+1:]
+	}
+	clean := func(s string, max int) string {
+		var sb strings.Builder
+		for _, r := range strings.ToUpper(s) {
+			if sb.Len() >= max {
+				break
+			}
+			switch {
+			case r == ' ' || r == '.':
+			case r < utf8.RuneSelf && (unicode.IsLetter(r) || unicode.IsDigit(r) || strings.ContainsRune("$%'-_@~`!(){}^#&", r)):
+				sb.WriteRune(r)
+			default:
+				sb.WriteByte('_')
+			}
+		}
+		return sb.String()
+	}
+	tail := fmt.Sprintf("~%d", n)
+	alias := clean(base, 8-len(tail))
+	if alias == "" {
+		alias = "_"
+	}
+	alias += tail
+	if extension := clean(ext, 3); extension != "" {
+		alias += "." + extension
+	}
+	return alias
+}
+
+// assignName: Mengisi Name dan LongName entri yang akan disimpan di direktori dirBlock. Nama yang muat sebagai
+// nama pendek disimpan langsung di Name; nama lain menjadi LongName dengan alias unik di Name.
+func (fs *FileSystem) assignName(entry *DirectoryEntry, dirBlock BlockID, name string) error {
+	if err := validateName(name); err != nil {
+		return err
+	}
+	entry.Name = [MAX_FILENAME_LEN]byte{}
+	entry.LongName = ""
+	if fs.fitsShortName(name) {
+		copy(entry.Name[:], name)
+		return nil
+	}
+	entries, err := fs.listEntries(dirBlock)
+	if err != nil {
+		return fmt.Errorf("gagal membaca direktori (Blok %d): %w", dirBlock, err)
+	}
+	// Paling banyak len(entries) alias yang sudah terpakai, jadi salah satu dari len(entries)+1 alias pertama pasti bebas
+	for n := 1; n <= len(entries)+1; n++ {
+		alias := shortAlias(name, n)
+		taken := false
+		for i := range entries {
+			if entries[i].matchesName(alias) {
+				taken = true
+				break
+			}
+		}
+		if !taken {
+			copy(entry.Name[:], alias)
+			entry.LongName = name
+			return nil
+		}
+	}
+	return fmt.Errorf("tidak ada alias pendek yang bebas untuk '%s'", name)
+}
+
+// shortNameChecksum: Checksum VFAT atas field Name entri pendek (rotasi kanan satu bit lalu tambah byte berikutnya).
+func shortNameChecksum(name [MAX_FILENAME_LEN]byte) byte {
+	var sum byte
+	for _, c := range name {
+		sum = (sum&1)<<7 + sum>>1 + c
+	}
+	return sum
+}
+
+// lfnCharOffset: Posisi byte karakter UTF-16 ke-k di dalam slot LFN.
+func lfnCharOffset(k int) int {
+	if k < LFN_PART1_CHARS {
+		return LFN_PART1_OFFSET + 2*k
+	}
+	return LFN_PART2_OFFSET + 2*(k-LFN_PART1_CHARS)
+}
+
+// encodeLFNSlots: Slot-slot LFN untuk name dalam urutan penyimpanan di disk (nomor urut tertinggi lebih dulu).
+func encodeLFNSlots(name string, checksum byte) [][]byte {
+	units := utf16.Encode([]rune(name))
+	count := (len(units) + LFN_CHARS_PER_SLOT - 1) / LFN_CHARS_PER_SLOT
+	slots := make([][]byte, 0, count)
+	for seq := count; seq >= 1; seq-- {
+		raw := make([]byte, DIRECTORY_ENTRY_SIZE)
+		raw[0] = byte(seq)
+		if seq == count {
+			raw[0] |= LFN_LAST
+		}
+		raw[LFN_TYPE_OFFSET] = byte(TYPE_LFN)
+		raw[LFN_CHECKSUM_OFFSET] = checksum
+		chunk := units[(seq-1)*LFN_CHARS_PER_SLOT : min(seq*LFN_CHARS_PER_SLOT, len(units))]
+		for k := 0; k < LFN_CHARS_PER_SLOT; k++ {
+			unit := uint16(0xFFFF) // Pengisi setelah terminator
+			if k < len(chunk) {
+				unit = chunk[k]
+			} else if k == len(chunk) {
+				unit = 0 // Terminator nama
+			}
+			binary.LittleEndian.PutUint16(raw[lfnCharOffset(k):], unit)
+		}
+		slots = append(slots, raw)
+	}
+	return slots
+}
+
+// lfnRun: Slot-slot LFN yang sedang dikumpulkan saat membaca direktori, menunggu entri pendeknya.
+type lfnRun struct {
+	slots    []dirSlot
+	parts    [][]uint16 // Karakter setiap slot, dalam urutan di disk (nomor urut menurun)
+	next     int        // Nomor urut yang diharapkan untuk slot berikutnya (0 = tidak ada run)
+	checksum byte
+}
+
+// add: Menambahkan slot LFN mentah ke run; run dibuang jika nomor urut atau checksum-nya tidak berurutan.
+func (r *lfnRun) add(slot dirSlot, raw []byte) {
+	seq := int(raw[0] & LFN_SEQ_MASK)
+	if raw[0]&LFN_LAST != 0 {
+		*r = lfnRun{next: seq, checksum: raw[LFN_CHECKSUM_OFFSET]}
+	}
+	if seq == 0 || seq != r.next || raw[LFN_CHECKSUM_OFFSET] != r.checksum {
+		*r = lfnRun{}
+		return
+	}
+	var part []uint16
+	for k := 0; k < LFN_CHARS_PER_SLOT; k++ {
+		unit := binary.LittleEndian.Uint16(raw[lfnCharOffset(k):])
+		if unit == 0 {
+			break
+		}
+		part = append(part, unit)
+	}
+	r.slots = append(r.slots, slot)
+	r.parts = append(r.parts, part)
+	r.next--
+}
+
+// name: Nama panjang hasil run jika run lengkap dan checksum-nya cocok dengan entri pendek.
+func (r *lfnRun) name(checksum byte) (string, bool) {
+	if len(r.slots) == 0 || r.next != 0 || r.checksum != checksum {
+		return "", false
+	}
+	var units []uint16
+	for i := len(r.parts) - 1; i >= 0; i-- {
+		units = append(units, r.parts[i]...)
+	}
+	return string(utf16.Decode(units)), true
+}
+
+// dirRecord: Satu entri logis di direktori: slot entri pendeknya beserta slot LFN di depannya (jika ada).
+type dirRecord struct {
+	Slot     dirSlot
+	LFNSlots []dirSlot
+	Entry    DirectoryEntry // LongName terisi jika slot LFN-nya valid
+}
+
+// scanDirectory: Membaca semua entri di rantai direktori secara berurutan, merakit nama panjang dari slot LFN,
+// lalu memanggil visit untuk setiap entri sampai visit mengembalikan false.
+func (fs *FileSystem) scanDirectory(dirStartBlock BlockID, visit func(rec dirRecord) bool) error {
+	blocks, err := fs.chainBlocks(dirStartBlock)
+	fs.scanBlocks(blocks, visit)
+	return err
+}
+
+// scanBlocks: Isi scanDirectory untuk daftar blok direktori yang sudah diketahui (lihat juga check.go).
+func (fs *FileSystem) scanBlocks(blocks []BlockID, visit func(rec dirRecord) bool) {
+	var run lfnRun
+	for _, block := range blocks {
+		for offset := 0; offset+DIRECTORY_ENTRY_SIZE <= fs.Geometry.BlockSize; offset += DIRECTORY_ENTRY_SIZE {
+			raw := fs.Disk[block][offset : offset+DIRECTORY_ENTRY_SIZE]
+			slot := dirSlot{Block: block, Offset: offset}
+			if raw[0] == 0 { // Slot kosong memutus run LFN
+				run = lfnRun{}
+				continue
+			}
+			if FileType(raw[LFN_TYPE_OFFSET]) == TYPE_LFN {
+				run.add(slot, raw)
+				continue
+			}
+			entry, errEntry := fs.decodeSlot(raw)
+			if errEntry != nil {
+				fmt.Printf("Warning: Gagal deserialize entri di blok %d offset %d: %v\n", block, offset, errEntry)
+				run = lfnRun{}
+				continue
+			}
+			rec := dirRecord{Slot: slot, Entry: entry}
+			if longName, ok := run.name(shortNameChecksum(entry.Name)); ok {
+				rec.Entry.LongName = longName
+				rec.LFNSlots = run.slots
+			}
+			run = lfnRun{}
+			if !visit(rec) {
+				return
+			}
+		}
+	}
+}
+
+// findRecord: Mencari entri bernama name (nama panjang atau alias) di direktori beserta semua slotnya.
+func (fs *FileSystem) findRecord(dirStartBlock BlockID, name string) (dirRecord, error) {
+	var found dirRecord
+	ok := false
+	err := fs.scanDirectory(dirStartBlock, func(rec dirRecord) bool {
+		if rec.Entry.matchesName(name) {
+			found, ok = rec, true
+			return false
+		}
+		return true
+	})
+	if ok {
+		return found, nil
+	}
+	if err != nil {
+		return dirRecord{}, fmt.Errorf("gagal membaca direktori (Blok %d): %w", dirStartBlock, err)
+	}
+	return dirRecord{}, fmt.Errorf("entri '%s' %w di direktori (Blok %d)", name, ErrNotExist, dirStartBlock)
+}
+
+// clearRecord: Mengosongkan slot entri pendek dan semua slot LFN-nya.
+func (fs *FileSystem) clearRecord(rec dirRecord) {
+	for _, slot := range append(rec.LFNSlots, rec.Slot) {
+		fs.Disk[slot.Block][slot.Offset] = 0
+	}
+}
+
+// findFreeRun: Mencari count slot kosong berurutan di rantai direktori (boleh menyeberang batas blok).
+// Jika tidak ada, rantai direktori diperpanjang (lihat growDirectory) sampai run-nya cukup.
+func (fs *FileSystem) findFreeRun(dirStartBlock BlockID, count int) ([]dirSlot, error) {
+	blocks, err := fs.chainBlocks(dirStartBlock)
+	if err != nil {
+		return nil, err
+	}
+	var run []dirSlot
+	scan := func(block BlockID) bool {
+		for offset := 0; offset+DIRECTORY_ENTRY_SIZE <= fs.Geometry.BlockSize; offset += DIRECTORY_ENTRY_SIZE {
+			if fs.Disk[block][offset] != 0 {
+				run = nil
+				continue
+			}
+			run = append(run, dirSlot{Block: block, Offset: offset})
+			if len(run) == count {
+				return true
+			}
+		}
+		return false
+	}
+	for _, block := range blocks {
+		if scan(block) {
+			return run, nil
+		}
+	}
+	lastBlock := blocks[len(blocks)-1]
+	for {
+		newBlock, errGrow := fs.growDirectory(lastBlock)
+		if errGrow != nil {
+			fs.compactDirectory(dirStartBlock) // Lepas lagi blok yang sempat ditambahkan
+			return nil, fmt.Errorf("direktori penuh dan tidak dapat diperluas: %w", errGrow)
+		}
+		if scan(newBlock) {
+			return run, nil
+		}
+		lastBlock = newBlock
+	}
+}
@@ -0,0 +1,122 @@
+package filesystem_logic
+
+import (
+	"errors"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// Nama panjang mendapat alias 8.3 yang unik (~1, ~2, ...); file bisa dicari lewat keduanya, dan alias
+// yang dilepas bisa dipakai lagi.
+func TestLongNameAliases(t *testing.T) {
+	fs := newTestDisk(t, Geometry{MaxFilenameLen: 12})
+	names := []string{"Laporan Keuangan 2024.txt", "Laporan Keuangan 2025.txt", "laporan.keuangan.txt"}
+	aliases := []string{"LAPORA~1.TXT", "LAPORA~2.TXT", "LAPORA~3.TXT"}
+	for i, name := range names {
+		if err := fs.WriteFile("/"+name, []byte(name)); err != nil {
+			t.Fatal(err)
+		}
+		entry := mustLookup(t, fs, "/"+name)
+		if entry.NameString() != name || entry.ShortName() != aliases[i] {
+			t.Fatalf("'%s' tersimpan sebagai '%s' dengan alias '%s', seharusnya alias '%s'",
+				name, entry.NameString(), entry.ShortName(), aliases[i])
+		}
+		if data, err := fs.ReadFile("/" + aliases[i]); err != nil || string(data) != name {
+			t.Fatalf("ReadFile lewat alias '%s': %q, %v", aliases[i], data, err)
+		}
+	}
+
+	if err := fs.Remove("/" + aliases[0]); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := fs.Lookup("/" + names[0]); !errors.Is(err, ErrNotExist) {
+		t.Fatalf("'%s' masih ada setelah dihapus lewat aliasnya: %v", names[0], err)
+	}
+	if err := fs.Create("/Laporan Keuangan 2026.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if entry := mustLookup(t, fs, "/Laporan Keuangan 2026.txt"); entry.ShortName() != aliases[0] {
+		t.Fatalf("alias yang dilepas tidak dipakai lagi: '%s'", entry.ShortName())
+	}
+
+	// Nama yang muat sebagai nama pendek tidak memakai slot LFN, sekalipun bentuknya sama dengan alias
+	if err := fs.Create("/LAPORA~9.TXT"); err != nil {
+		t.Fatal(err)
+	}
+	if entry := mustLookup(t, fs, "/LAPORA~9.TXT"); entry.LongName != "" {
+		t.Fatalf("nama pendek disimpan dengan nama panjang '%s'", entry.LongName)
+	}
+}
+
+// Nama non-ASCII dan nama yang lebih panjang dari MaxFilenameLen tersimpan di slot LFN dan ikut terbaca
+// setelah image dimuat ulang; nama di atas MAX_LONG_NAME_LEN ditolak.
+func TestLongNamesImageRoundTrip(t *testing.T) {
+	fs := newTestDisk(t, Geometry{MaxFilenameLen: 12})
+	names := []string{"café.txt", "nama.terlalu.panjang", strings.Repeat("x", MAX_LONG_NAME_LEN), "pendek.txt"}
+	fs.Mkdir("/d")
+	for _, name := range names {
+		if err := fs.WriteFile("/d/"+name, []byte(name)); err != nil {
+			t.Fatalf("'%s': %v", name, err)
+		}
+	}
+	if entry := mustLookup(t, fs, "/d/café.txt"); entry.ShortName() != "CAF_~1.TXT" {
+		t.Fatalf("alias 'café.txt' = '%s'", entry.ShortName())
+	}
+	if err := fs.Create("/d/" + strings.Repeat("x", MAX_LONG_NAME_LEN+1)); err == nil {
+		t.Fatal("nama lebih panjang dari MAX_LONG_NAME_LEN seharusnya ditolak")
+	}
+
+	image := filepath.Join(t.TempDir(), "disk.img")
+	if err := fs.SaveImage(image); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := NewFileSystem(FileSystemOptions{ImagePath: image})
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := mustLookup(t, loaded, "/d")
+	entries, err := loaded.ListEntries(dir.StartBlock)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, entry := range entries {
+		got = append(got, entry.NameString())
+	}
+	if want := append([]string{".", ".."}, names...); !slices.Equal(got, want) {
+		t.Fatalf("isi /d setelah dimuat: %q, seharusnya %q", got, want)
+	}
+	for _, name := range names {
+		if data, err := loaded.ReadFile("/d/" + name); err != nil || string(data) != name {
+			t.Fatalf("ReadFile('%s'): %v", name, err)
+		}
+	}
+}
+
+// Slot LFN yang checksum-nya tidak cocok diabaikan: entri tetap terbaca dengan aliasnya.
+func TestLongNameBadChecksum(t *testing.T) {
+	fs := newTestDisk(t, Geometry{MaxFilenameLen: 12})
+	name := "dokumen penting sekali.txt"
+	if err := fs.WriteFile("/"+name, []byte("isi")); err != nil {
+		t.Fatal(err)
+	}
+	rec, err := fs.findRecord(fs.RootDirBlock, name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rec.LFNSlots) != 1 {
+		t.Fatalf("%d slot LFN untuk %d karakter", len(rec.LFNSlots), len(name))
+	}
+	slot := rec.LFNSlots[0]
+	fs.Disk[slot.Block][slot.Offset+LFN_CHECKSUM_OFFSET] ^= 0xff
+
+	if _, err := fs.Lookup("/" + name); !errors.Is(err, ErrNotExist) {
+		t.Fatalf("nama panjang dengan checksum rusak masih ditemukan: %v", err)
+	}
+	alias := rec.Entry.ShortName()
+	if data, err := fs.ReadFile("/" + alias); err != nil || string(data) != "isi" {
+		t.Fatalf("ReadFile lewat alias '%s': %q, %v", alias, data, err)
+	}
+}
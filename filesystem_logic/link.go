@@ -0,0 +1,223 @@
+// link.go
+package filesystem_logic
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Dua jenis link seperti di Unix:
+//
+//   - Symbolic link (TYPE_SYMLINK): entri tersendiri yang blok datanya berisi path tujuan. Path tujuan
+//     diikuti saat penelusuran path (lihat walkComponents dan Lookup); relatif terhadap direktori tempat
+//     symlink berada, atau dari root jika diawali "/". Tujuan boleh belum ada (symlink menggantung).
+//   - Hard link: beberapa entri di direktori mana pun yang menunjuk ke rantai blok yang sama. Setiap entri
+//     menyimpan salinan metadata yang sama (ukuran, waktu, izin) beserta Nlink, jumlah entri dalam grupnya.
+//     Karena tidak ada inode, perubahan pada satu entri disalin ke entri lain lewat writeSlot (syncLinks),
+//     dan blok data baru dibebaskan DeleteEntry saat link terakhir dihapus.
+//
+// Entri dalam satu grup hard link dikenali dari StartBlock dan BirthTime yang sama (lihat sameFile).
+// Pada disk dengan tabel inode (inode.go), semua link cukup menunjuk inode yang sama.
+// Hard link ke direktori tidak diizinkan, sama seperti di Unix.
+
+// hasLinks: true jika entri adalah bagian dari grup hard link dengan lebih dari satu entri.
+func (de *DirectoryEntry) hasLinks() bool {
+	return de.Type != TYPE_DIRECTORY && de.Nlink > 1
+}
+
+// sameFile: true jika a dan b adalah dua hard link ke file yang sama (pada layout inode: inode yang sama).
+func sameFile(a, b DirectoryEntry) bool {
+	if a.Inode != NO_INODE {
+		return a.hasLinks() && a.Inode == b.Inode
+	}
+	return a.hasLinks() && b.hasLinks() && a.StartBlock == b.StartBlock && a.BirthTime == b.BirthTime
+}
+
+// followLink: Membaca path tujuan symlink dan menambah hitungan hops. ErrLoop jika sudah lebih dari
+// MAX_SYMLINK_FOLLOW symlink diikuti dalam satu penelusuran (misalnya symlink yang menunjuk dirinya sendiri).
+func (fs *FileSystem) followLink(entry DirectoryEntry, hops *int) (string, error) {
+	*hops++
+	if *hops > MAX_SYMLINK_FOLLOW {
+		return "", ErrLoop
+	}
+	data, err := fs.ReadFromFile(entry)
+	if err != nil {
+		return "", fmt.Errorf("gagal membaca symlink '%s': %w", entry.NameString(), err)
+	}
+	if len(data) == 0 {
+		return "", fmt.Errorf("symlink '%s' tidak punya tujuan: %w", entry.NameString(), ErrNotExist)
+	}
+	return string(data), nil
+}
+
+// Symlink: Membuat symbolic link di linkPath yang menunjuk ke target. target tidak diperiksa keberadaannya.
+func (fs *FileSystem) Symlink(target, linkPath string) error {
+	if target == "" {
+		return errors.New("tujuan symlink tidak boleh kosong")
+	}
+	parentBlock, name, err := fs.resolveParent(linkPath)
+	if err != nil {
+		return err
+	}
+	if err := fs.CreateFile(parentBlock, name); err != nil {
+		return err
+	}
+	// Isi symlink ditulis selagi entrinya masih FILE, lalu tipenya diganti. Izin symlink selalu rwxrwxrwx;
+	// yang diperiksa adalah izin tujuannya.
+	entry, err := fs.findEntry(parentBlock, name)
+	if err == nil {
+		err = fs.WriteToFile(&entry, parentBlock, []byte(target))
+	}
+	if err == nil {
+		entry.Type = TYPE_SYMLINK
+		entry.Mode = MODE_PERM_MASK
+		err = fs.updateEntryInDirectory(parentBlock, entry)
+	}
+	if err != nil {
+		if errDel := fs.DeleteEntry(parentBlock, name); errDel != nil {
+			fmt.Printf("Warning: Gagal membatalkan symlink '%s': %v\n", linkPath, errDel)
+		}
+		return fmt.Errorf("gagal membuat symlink '%s': %w", linkPath, err)
+	}
+	fmt.Printf("Symlink '%s' -> '%s' dibuat.\n", linkPath, target)
+	return nil
+}
+
+// Readlink: Path tujuan symlink di path (symlink itu sendiri tidak diikuti).
+func (fs *FileSystem) Readlink(path string) (string, error) {
+	entry, err := fs.Lstat(path)
+	if err != nil {
+		return "", err
+	}
+	if entry.Type != TYPE_SYMLINK {
+		return "", fmt.Errorf("'%s' bukan symlink", path)
+	}
+	data, err := fs.ReadFromFile(entry)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// Link: Membuat hard link newPath ke file di oldPath. Kedua entri memakai rantai blok yang sama dan
+// Nlink seluruh grup bertambah satu. Jika oldPath adalah symlink, yang di-link adalah symlink itu sendiri.
+func (fs *FileSystem) Link(oldPath, newPath string) error {
+	oldParent, oldName, err := fs.resolveParent(oldPath)
+	if err != nil {
+		return err
+	}
+	oldRec, err := fs.findRecord(oldParent, oldName)
+	if err != nil {
+		return err
+	}
+	entry := oldRec.Entry
+	if entry.Type == TYPE_DIRECTORY {
+		return fmt.Errorf("hard link ke '%s': %w", oldPath, ErrIsDir)
+	}
+	if entry.Nlink == ^uint16(0) {
+		return fmt.Errorf("'%s' sudah mencapai batas jumlah link (%d)", oldPath, entry.Nlink)
+	}
+
+	newParent, newName, err := fs.resolveParent(newPath)
+	if err != nil {
+		return err
+	}
+	if err := fs.checkDirAccess("link", newParent, ACCESS_WRITE|ACCESS_EXEC); err != nil {
+		return err
+	}
+	if _, err := fs.findEntry(newParent, newName); err == nil {
+		return fmt.Errorf("'%s' %w", newPath, ErrExist)
+	}
+
+	// Entri baru adalah salinan entri lama dengan nama baru; keduanya mendapat Nlink dan ctime yang baru
+	entry.Nlink++
+	entry.ChangeTime = time.Now().UnixNano()
+	linked := entry
+	if err := fs.assignName(&linked, newParent, newName); err != nil {
+		return err
+	}
+	if err := fs.addEntryToDirectory(newParent, linked); err != nil {
+		return fmt.Errorf("gagal menambahkan hard link '%s': %w", newPath, err)
+	}
+	if err := fs.writeSlot(oldRec.Slot, entry); err != nil {
+		return err
+	}
+	fs.touchDirectory(newParent)
+	fmt.Printf("Hard link '%s' -> '%s' dibuat (%d link).\n", newPath, oldPath, entry.Nlink)
+	return nil
+}
+
+// linkSlots: Slot semua entri di seluruh pohon direktori yang merupakan hard link ke file yang sama dengan entry.
+func (fs *FileSystem) linkSlots(entry DirectoryEntry) ([]dirSlot, error) {
+	var slots []dirSlot
+	var walk func(dirBlock BlockID) error
+	walk = func(dirBlock BlockID) error {
+		var subdirs []BlockID
+		err := fs.scanDirectory(dirBlock, func(rec dirRecord) bool {
+			name := rec.Entry.ShortName()
+			switch {
+			case name == "." || name == "..":
+			case rec.Entry.Type == TYPE_DIRECTORY:
+				subdirs = append(subdirs, rec.Entry.StartBlock)
+			case sameFile(rec.Entry, entry):
+				slots = append(slots, rec.Slot)
+			}
+			return true
+		})
+		if err != nil {
+			return err
+		}
+		for _, sub := range subdirs {
+			if err := walk(sub); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	err := walk(fs.RootDirBlock)
+	return slots, err
+}
+
+// syncLinks: Menyalin metadata entry (semua field kecuali nama) ke hard link lain milik file old,
+// selain slot except yang baru saja ditulis.
+func (fs *FileSystem) syncLinks(old DirectoryEntry, except dirSlot, entry DirectoryEntry) error {
+	slots, err := fs.linkSlots(old)
+	if err != nil {
+		return fmt.Errorf("gagal mencari hard link '%s': %w", old.NameString(), err)
+	}
+	for _, slot := range slots {
+		if slot == except {
+			continue
+		}
+		other, err := fs.readSlot(slot)
+		if err != nil {
+			return err
+		}
+		synced := entry
+		synced.Name = other.Name
+		entryBytes, err := synced.Serialize()
+		if err != nil {
+			return err
+		}
+		copy(fs.Disk[slot.Block][slot.Offset:], entryBytes)
+	}
+	return nil
+}
+
+// dropLink: Dipanggil DeleteEntry setelah slot removed (salah satu hard link entry) dikosongkan.
+// Nlink link yang tersisa dikurangi satu, dan handle terbuka lewat link yang dihapus pindah ke link lain.
+func (fs *FileSystem) dropLink(entry DirectoryEntry, removed dirSlot) error {
+	slots, err := fs.linkSlots(entry)
+	if err != nil {
+		return err
+	}
+	if len(slots) == 0 {
+		return fmt.Errorf("hard link lain untuk '%s' tidak ditemukan", entry.NameString())
+	}
+	fs.FileTable.moveNode(removed, slots[0])
+	return fs.updateSlots(slots[:1], func(stored *DirectoryEntry) {
+		stored.Nlink = entry.Nlink - 1
+		stored.ChangeTime = time.Now().UnixNano()
+	})
+}
@@ -0,0 +1,133 @@
+package filesystem_logic
+
+import (
+	"errors"
+	"testing"
+)
+
+// checkLinks: Semua path adalah hard link ke file yang sama dengan Nlink, ukuran, dan isi yang sama.
+func checkLinks(t *testing.T, fs *FileSystem, want string, paths ...string) {
+	t.Helper()
+	first := mustLookup(t, fs, paths[0])
+	for _, p := range paths {
+		entry := mustLookup(t, fs, p)
+		if int(entry.Nlink) != len(paths) || entry.Size != int64(len(want)) || entry.ModTime != first.ModTime || (len(paths) > 1 && !sameFile(entry, first)) {
+			t.Fatalf("%s: Nlink %d, ukuran %d, mtime %d; seharusnya %d link, %d byte, mtime %d",
+				p, entry.Nlink, entry.Size, entry.ModTime, len(paths), len(want), first.ModTime)
+		}
+		if data, err := fs.ReadFile(p); err != nil || string(data) != want {
+			t.Fatalf("ReadFile('%s') = %q, %v; seharusnya %q", p, data, err, want)
+		}
+	}
+}
+
+// Menulis lewat salah satu hard link terlihat di link lain, Nlink selalu sama di semua link,
+// dan blok data baru dibebaskan setelah link terakhir dihapus.
+func TestHardLinks(t *testing.T) {
+	fs := newTestDisk(t, Geometry{})
+	free := fs.countFreeBlocks()
+	fs.Mkdir("/d")
+	if err := fs.WriteFile("/a", []byte("awal")); err != nil {
+		t.Fatal(err)
+	}
+	if err := fs.Link("/a", "/d/b"); err != nil {
+		t.Fatal(err)
+	}
+	checkLinks(t, fs, "awal", "/a", "/d/b")
+
+	// Tulis lewat link kedua sampai rantai blok bertambah
+	long := string(make([]byte, 2*fs.Geometry.BlockSize))
+	if err := fs.Append("/d/b", []byte(long)); err != nil {
+		t.Fatal(err)
+	}
+	checkLinks(t, fs, "awal"+long, "/a", "/d/b")
+	if err := fs.Link("/d/b", "/c"); err != nil {
+		t.Fatal(err)
+	}
+	if err := fs.Truncate("/c", 3); err != nil {
+		t.Fatal(err)
+	}
+	checkLinks(t, fs, "awa", "/a", "/d/b", "/c")
+
+	if err := fs.Remove("/a"); err != nil {
+		t.Fatal(err)
+	}
+	checkLinks(t, fs, "awa", "/d/b", "/c")
+	if err := fs.Remove("/c"); err != nil {
+		t.Fatal(err)
+	}
+	checkLinks(t, fs, "awa", "/d/b")
+
+	rejected := []struct {
+		name      string
+		err, want error
+	}{
+		{"link ke direktori", fs.Link("/d", "/e"), ErrIsDir},
+		{"tujuan sudah ada", fs.Link("/d/b", "/d/b"), ErrExist},
+		{"sumber tidak ada", fs.Link("/nope", "/e"), ErrNotExist},
+	}
+	for _, c := range rejected {
+		if !errors.Is(c.err, c.want) {
+			t.Errorf("%s: %v, seharusnya %v", c.name, c.err, c.want)
+		}
+	}
+
+	if err := fs.Remove("/d/b"); err != nil {
+		t.Fatal(err)
+	}
+	fs.Remove("/d")
+	if fs.countFreeBlocks() != free {
+		t.Fatalf("%d blok kosong setelah semua link dihapus, seharusnya %d", fs.countFreeBlocks(), free)
+	}
+}
+
+// Symlink diikuti saat penelusuran path (relatif terhadap direktorinya), boleh menggantung,
+// dan rantai symlink yang berputar dihentikan dengan ErrLoop.
+func TestSymlinks(t *testing.T) {
+	fs := newTestDisk(t, Geometry{})
+	fs.Mkdir("/d")
+	if err := fs.Symlink("f", "/d/rel"); err != nil {
+		t.Fatal(err)
+	}
+	if err := fs.Symlink("/d", "/ld"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := fs.ReadFile("/d/rel"); !errors.Is(err, ErrNotExist) {
+		t.Fatalf("symlink menggantung: %v", err)
+	}
+	if entry, err := fs.Lstat("/d/rel"); err != nil || entry.Type != TYPE_SYMLINK {
+		t.Fatalf("Lstat symlink menggantung: %+v, %v", entry, err)
+	}
+
+	if err := fs.WriteFile("/ld/rel", []byte("lewat symlink")); err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range []string{"/d/f", "/d/rel", "/ld/f", "/ld/rel"} {
+		if data, err := fs.ReadFile(p); err != nil || string(data) != "lewat symlink" {
+			t.Fatalf("ReadFile('%s') = %q, %v", p, data, err)
+		}
+	}
+	if target, err := fs.Readlink("/ld/rel"); err != nil || target != "f" {
+		t.Fatalf("Readlink = '%s', %v", target, err)
+	}
+	if err := fs.ChangeDirectory("/ld"); err != nil {
+		t.Fatal(err)
+	}
+	if wd, _ := fs.Getwd(); wd != "/d" {
+		t.Fatalf("Getwd setelah cd lewat symlink = '%s'", wd)
+	}
+
+	// Menghapus symlink tidak menyentuh tujuannya
+	if err := fs.Remove("/d/rel"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := fs.ReadFile("/d/f"); err != nil {
+		t.Fatal(err)
+	}
+
+	fs.Symlink("/b", "/a")
+	fs.Symlink("/a", "/b")
+	if _, err := fs.Lookup("/a"); !errors.Is(err, ErrLoop) {
+		t.Fatalf("Lookup symlink berputar: %v, seharusnya %v", err, ErrLoop)
+	}
+}
@@ -0,0 +1,331 @@
+// path.go
+package filesystem_logic
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Error yang bisa dicek dengan errors.Is oleh pemanggil API berbasis path.
+var (
+	ErrNotExist = errors.New("tidak ditemukan")
+	ErrNotDir   = errors.New("bukan direktori")
+	ErrIsDir    = errors.New("adalah direktori")
+	ErrLoop     = errors.New("terlalu banyak tingkat symbolic link")
+)
+
+// MAX_SYMLINK_FOLLOW: Batas symlink yang diikuti dalam satu penelusuran path (seperti ELOOP di Unix).
+const MAX_SYMLINK_FOLLOW = 8
+
+// NameString: Nama entri sebagai string: nama panjangnya jika ada (lihat lfn.go), jika tidak isi field Name.
+func (de *DirectoryEntry) NameString() string {
+	if de.LongName != "" {
+		return de.LongName
+	}
+	return de.ShortName()
+}
+
+// findEntry: Mencari entri dengan nama tertentu di sebuah direktori.
+func (fs *FileSystem) findEntry(dirStartBlock BlockID, name string) (DirectoryEntry, error) {
+	entries, err := fs.listEntries(dirStartBlock)
+	if err != nil {
+		return DirectoryEntry{}, fmt.Errorf("gagal membaca direktori (Blok %d): %w", dirStartBlock, err)
+	}
+	for _, entry := range entries {
+		if entry.matchesName(name) {
+			return entry, nil
+		}
+	}
+	return DirectoryEntry{}, fmt.Errorf("entri '%s' %w di direktori (Blok %d)", name, ErrNotExist, dirStartBlock)
+}
+
+// splitPath: Memecah path menjadi komponen-komponennya dan menentukan direktori awal penelusuran.
+// Path absolut ("/a/b") dimulai dari root, path relatif ("a/b", "../a") dari direktori kerja saat ini.
+// Slash berulang dan slash di akhir diabaikan ("/a//b/" sama dengan "/a/b").
+func (fs *FileSystem) splitPath(path string) (BlockID, []string, error) {
+	if path == "" {
+		return -1, nil, errors.New("path tidak boleh kosong")
+	}
+	startBlock, components := fs.pathComponents(path, fs.CurrentDirectoryBlock)
+	return startBlock, components, nil
+}
+
+// pathComponents: Inti splitPath. Path relatif dimulai dari dirBlock; dipakai juga untuk tujuan symlink,
+// yang relatif terhadap direktori tempat symlink itu berada.
+func (fs *FileSystem) pathComponents(path string, dirBlock BlockID) (BlockID, []string) {
+	if strings.HasPrefix(path, "/") {
+		dirBlock = fs.RootDirBlock
+	}
+	var components []string
+	for _, component := range strings.Split(path, "/") {
+		if component != "" {
+			components = append(components, component)
+		}
+	}
+	return dirBlock, components
+}
+
+// walkComponents: Menelusuri komponen path mulai dari dirBlock dan mengembalikan blok direktori terakhir.
+// "." tetap di direktori yang sama, ".." mengikuti entri ".." direktori tersebut (di root tetap root).
+// Setiap komponen harus berupa direktori, dan setiap direktori yang dilewati butuh izin eksekusi (x).
+// Komponen berupa symlink diikuti (lihat followLink); hops menghitung symlink yang sudah diikuti.
+func (fs *FileSystem) walkComponents(dirBlock BlockID, components []string, hops *int) (BlockID, error) {
+	for i, component := range components {
+		if component == "." {
+			continue
+		}
+		if err := fs.checkDirAccess("search", dirBlock, ACCESS_EXEC); err != nil {
+			return -1, err
+		}
+		entry, err := fs.findEntry(dirBlock, component)
+		if err != nil {
+			return -1, fmt.Errorf("'%s': %w", strings.Join(components[:i+1], "/"), err)
+		}
+		if entry.Type == TYPE_SYMLINK {
+			target, err := fs.followLink(entry, hops)
+			if err != nil {
+				return -1, fmt.Errorf("'%s': %w", strings.Join(components[:i+1], "/"), err)
+			}
+			targetStart, targetComponents := fs.pathComponents(target, dirBlock)
+			if dirBlock, err = fs.walkComponents(targetStart, targetComponents, hops); err != nil {
+				return -1, err
+			}
+			continue
+		}
+		if entry.Type != TYPE_DIRECTORY {
+			return -1, fmt.Errorf("'%s' %w", strings.Join(components[:i+1], "/"), ErrNotDir)
+		}
+		dirBlock = entry.StartBlock
+	}
+	return dirBlock, nil
+}
+
+// rootEntry: Entri sintetis untuk root directory (root tidak punya entri di direktori induk).
+// Diambil dari entri "." root lalu diberi nama "/".
+func (fs *FileSystem) rootEntry() (DirectoryEntry, error) {
+	entry, err := fs.findEntry(fs.RootDirBlock, ".")
+	if err != nil {
+		return DirectoryEntry{}, err
+	}
+	entry.Name = [MAX_FILENAME_LEN]byte{}
+	copy(entry.Name[:], "/")
+	return entry, nil
+}
+
+// resolveParent: Memisahkan path menjadi blok direktori induk dan nama komponen terakhir.
+// Dipakai oleh operasi yang membuat atau menghapus entri (Create, Mkdir, Remove, WriteFile).
+func (fs *FileSystem) resolveParent(path string) (BlockID, string, error) {
+	startBlock, components, err := fs.splitPath(path)
+	if err != nil {
+		return -1, "", err
+	}
+	return fs.resolveParentIn(startBlock, components, path, new(int))
+}
+
+// resolveParentIn: resolveParent untuk komponen yang sudah dipecah, mulai dari startBlock.
+func (fs *FileSystem) resolveParentIn(startBlock BlockID, components []string, path string, hops *int) (BlockID, string, error) {
+	if len(components) == 0 {
+		return -1, "", fmt.Errorf("path '%s' tidak menunjuk ke sebuah nama", path)
+	}
+	name := components[len(components)-1]
+	if name == "." || name == ".." {
+		return -1, "", fmt.Errorf("path '%s' tidak boleh diakhiri '.' atau '..'", path)
+	}
+	parentBlock, err := fs.walkComponents(startBlock, components[:len(components)-1], hops)
+	if err != nil {
+		return -1, "", err
+	}
+	if err := fs.checkDirAccess("search", parentBlock, ACCESS_EXEC); err != nil {
+		return -1, "", err
+	}
+	return parentBlock, name, nil
+}
+
+// resolveTarget: Seperti resolveParent, tetapi jika komponen terakhir adalah symlink, tujuannya diikuti
+// sampai ketemu nama yang bukan symlink. Nama yang dikembalikan boleh belum ada (symlink menggantung),
+// sehingga Open dengan O_CREATE dan WriteFile membuat file tujuannya seperti di Unix.
+func (fs *FileSystem) resolveTarget(path string) (BlockID, string, error) {
+	hops := 0
+	parentBlock, name, err := fs.resolveParent(path)
+	for err == nil {
+		entry, errFind := fs.findEntry(parentBlock, name)
+		if errFind != nil || entry.Type != TYPE_SYMLINK {
+			break
+		}
+		target, errLink := fs.followLink(entry, &hops)
+		if errLink != nil {
+			return -1, "", fmt.Errorf("'%s': %w", path, errLink)
+		}
+		startBlock, components := fs.pathComponents(target, parentBlock)
+		parentBlock, name, err = fs.resolveParentIn(startBlock, components, target, &hops)
+	}
+	return parentBlock, name, err
+}
+
+// Lookup: Mencari entri untuk sebuah path absolut atau relatif, misalnya "/a/b/c.txt", "docs/../x", atau "./a".
+// Untuk path yang menunjuk ke root ("/", "/.."), dikembalikan entri sintetis bernama "/".
+// Symlink di komponen terakhir diikuti (seperti stat); gunakan Lstat untuk entri symlink itu sendiri.
+func (fs *FileSystem) Lookup(path string) (DirectoryEntry, error) {
+	return fs.lookup(path, true)
+}
+
+// Lstat: Seperti Lookup, tetapi symlink di komponen terakhir tidak diikuti (seperti lstat).
+func (fs *FileSystem) Lstat(path string) (DirectoryEntry, error) {
+	return fs.lookup(path, false)
+}
+
+func (fs *FileSystem) lookup(path string, follow bool) (DirectoryEntry, error) {
+	startBlock, components, err := fs.splitPath(path)
+	if err != nil {
+		return DirectoryEntry{}, err
+	}
+	return fs.lookupIn(startBlock, components, path, follow, new(int))
+}
+
+// lookupIn: Inti lookup untuk komponen yang sudah dipecah, mulai dari startBlock.
+func (fs *FileSystem) lookupIn(startBlock BlockID, components []string, path string, follow bool, hops *int) (DirectoryEntry, error) {
+	// Buang "." di akhir agar "a/." diperlakukan sama dengan "a"
+	for len(components) > 0 && components[len(components)-1] == "." {
+		components = components[:len(components)-1]
+	}
+	if len(components) == 0 {
+		if startBlock == fs.RootDirBlock {
+			return fs.rootEntry()
+		}
+		return fs.findEntry(startBlock, ".")
+	}
+
+	parentBlock, err := fs.walkComponents(startBlock, components[:len(components)-1], hops)
+	if err != nil {
+		return DirectoryEntry{}, err
+	}
+	if err := fs.checkDirAccess("search", parentBlock, ACCESS_EXEC); err != nil {
+		return DirectoryEntry{}, err
+	}
+	last := components[len(components)-1]
+	entry, err := fs.findEntry(parentBlock, last)
+	if err != nil {
+		return DirectoryEntry{}, fmt.Errorf("'%s': %w", path, err)
+	}
+	if last == ".." && entry.StartBlock == fs.RootDirBlock {
+		return fs.rootEntry()
+	}
+	if follow && entry.Type == TYPE_SYMLINK {
+		target, err := fs.followLink(entry, hops)
+		if err != nil {
+			return DirectoryEntry{}, fmt.Errorf("'%s': %w", path, err)
+		}
+		targetStart, targetComponents := fs.pathComponents(target, parentBlock)
+		return fs.lookupIn(targetStart, targetComponents, path, true, hops)
+	}
+	return entry, nil
+}
+
+// Create: Membuat file kosong baru di path (direktori induknya harus sudah ada).
+func (fs *FileSystem) Create(path string) error {
+	parentBlock, name, err := fs.resolveParent(path)
+	if err != nil {
+		return err
+	}
+	return fs.CreateFile(parentBlock, name)
+}
+
+// Mkdir: Membuat direktori baru di path (direktori induknya harus sudah ada).
+func (fs *FileSystem) Mkdir(path string) error {
+	parentBlock, name, err := fs.resolveParent(path)
+	if err != nil {
+		return err
+	}
+	return fs.CreateDirectory(parentBlock, name)
+}
+
+// Remove: Menghapus file atau direktori kosong di path.
+func (fs *FileSystem) Remove(path string) error {
+	parentBlock, name, err := fs.resolveParent(path)
+	if err != nil {
+		return err
+	}
+	return fs.DeleteEntry(parentBlock, name)
+}
+
+// ReadFile: Membaca seluruh isi file di path lalu memperbarui atime-nya (lihat MountOptions).
+func (fs *FileSystem) ReadFile(path string) ([]byte, error) {
+	entry, err := fs.Lookup(path)
+	if err != nil {
+		return nil, err
+	}
+	if entry.Type == TYPE_DIRECTORY {
+		return nil, fmt.Errorf("'%s' %w", path, ErrIsDir)
+	}
+	data, err := fs.ReadFromFile(entry)
+	if err != nil {
+		return nil, err
+	}
+	if slots, _, errSlots := fs.entrySlots(path); errSlots == nil {
+		fs.noteAccess(slots) // Perbarui atime sesuai MountOptions
+	}
+	return data, nil
+}
+
+// WriteFile: Menimpa isi file di path dengan data. File dibuat dulu jika belum ada.
+// Jika path adalah symlink, yang ditulis adalah file tujuannya.
+func (fs *FileSystem) WriteFile(path string, data []byte) error {
+	parentBlock, name, err := fs.resolveTarget(path)
+	if err != nil {
+		return err
+	}
+	entry, err := fs.findEntry(parentBlock, name)
+	if errors.Is(err, ErrNotExist) {
+		if err := fs.CreateFile(parentBlock, name); err != nil {
+			return err
+		}
+		entry, err = fs.findEntry(parentBlock, name)
+	}
+	if err != nil {
+		return err
+	}
+	if entry.Type == TYPE_DIRECTORY {
+		return fmt.Errorf("'%s' %w", path, ErrIsDir)
+	}
+	return fs.WriteToFile(&entry, parentBlock, data)
+}
+
+// Getwd: Path absolut direktori kerja saat ini, dihitung dari pohon direktori di disk.
+// Mulai dari CurrentDirectoryBlock, naik lewat entri ".." dan di setiap induk dicari entri
+// yang StartBlock-nya sama dengan direktori anak untuk mendapatkan namanya.
+func (fs *FileSystem) Getwd() (string, error) {
+	return fs.pathOfDirectory(fs.CurrentDirectoryBlock)
+}
+
+// pathOfDirectory: Path absolut untuk direktori yang blok pertamanya dirBlock.
+func (fs *FileSystem) pathOfDirectory(dirBlock BlockID) (string, error) {
+	var names []string
+	for depth := 0; dirBlock != fs.RootDirBlock; depth++ {
+		if depth > fs.Geometry.TotalBlocks { // Pengaman jika entri ".." membentuk siklus
+			return "", fmt.Errorf("entri '..' mulai blok %d membentuk siklus", dirBlock)
+		}
+		parent, err := fs.findEntry(dirBlock, "..")
+		if err != nil {
+			return "", err
+		}
+		siblings, err := fs.listEntries(parent.StartBlock)
+		if err != nil {
+			return "", err
+		}
+		found := false
+		for _, entry := range siblings {
+			name := entry.NameString()
+			if entry.Type == TYPE_DIRECTORY && entry.StartBlock == dirBlock && name != "." && name != ".." {
+				names = append([]string{name}, names...)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return "", fmt.Errorf("direktori (Blok %d) tidak ditemukan di induknya (Blok %d)", dirBlock, parent.StartBlock)
+		}
+		dirBlock = parent.StartBlock
+	}
+	return "/" + strings.Join(names, "/"), nil
+}
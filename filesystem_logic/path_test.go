@@ -0,0 +1,148 @@
+package filesystem_logic
+
+import (
+	"errors"
+	"testing"
+)
+
+// Path absolut, relatif, ".", "..", dan slash berlebih menunjuk ke entri yang sama.
+func TestLookupPaths(t *testing.T) {
+	fs := newTestDisk(t, Geometry{})
+	for _, dir := range []string{"/docs", "/docs/arsip"} {
+		if err := fs.Mkdir(dir); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := fs.WriteFile("/docs/arsip/a.txt", []byte("isi a")); err != nil {
+		t.Fatal(err)
+	}
+	if err := fs.ChangeDirectory("docs"); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, path := range []string{"/docs/arsip/a.txt", "arsip/a.txt", "./arsip/../arsip/a.txt", "//docs//arsip/a.txt", "../docs/arsip/a.txt"} {
+		data, err := fs.ReadFile(path)
+		if err != nil {
+			t.Fatalf("ReadFile('%s'): %v", path, err)
+		}
+		if string(data) != "isi a" {
+			t.Fatalf("ReadFile('%s') = %q", path, data)
+		}
+	}
+	for _, path := range []string{"/", "/..", "..", "../.."} {
+		entry, err := fs.Lookup(path)
+		if err != nil {
+			t.Fatalf("Lookup('%s'): %v", path, err)
+		}
+		if entry.NameString() != "/" || entry.StartBlock != fs.RootDirBlock {
+			t.Fatalf("Lookup('%s') = '%s' (blok %d), seharusnya root", path, entry.NameString(), entry.StartBlock)
+		}
+	}
+	entry, err := fs.Lookup("arsip/")
+	if err != nil || entry.Type != TYPE_DIRECTORY {
+		t.Fatalf("Lookup('arsip/') = %+v, %v", entry, err)
+	}
+}
+
+// Kesalahan path bisa dibedakan dengan errors.Is.
+func TestPathErrors(t *testing.T) {
+	fs := newTestDisk(t, Geometry{})
+	fs.Mkdir("/d")
+	if err := fs.WriteFile("/d/f", []byte("x")); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{"tidak ada", func() error { _, err := fs.Lookup("/d/nope"); return err }(), ErrNotExist},
+		{"induk tidak ada", fs.Create("/nope/f"), ErrNotExist},
+		{"file di tengah path", func() error { _, err := fs.Lookup("/d/f/x"); return err }(), ErrNotDir},
+		{"baca direktori", func() error { _, err := fs.ReadFile("/d"); return err }(), ErrIsDir},
+		{"tulis direktori", fs.WriteFile("/d", []byte("x")), ErrIsDir},
+	}
+	for _, c := range cases {
+		if !errors.Is(c.err, c.want) {
+			t.Errorf("%s: %v, seharusnya %v", c.name, c.err, c.want)
+		}
+	}
+	for _, path := range []string{"", "/", "/d/..", "/d/."} {
+		if err := fs.Create(path); err == nil {
+			t.Errorf("Create('%s') seharusnya ditolak", path)
+		}
+	}
+}
+
+// WriteFile membuat file jika belum ada lalu menimpa isinya; Remove menghapusnya lagi.
+func TestWriteFileAndRemove(t *testing.T) {
+	fs := newTestDisk(t, Geometry{})
+	free := fs.countFreeBlocks()
+	fs.Mkdir("/d")
+	big := make([]byte, 3*fs.Geometry.BlockSize)
+	for _, data := range [][]byte{big, []byte("kecil")} {
+		if err := fs.WriteFile("/d/f", data); err != nil {
+			t.Fatal(err)
+		}
+		got, err := fs.ReadFile("/d/f")
+		if err != nil || string(got) != string(data) {
+			t.Fatalf("ReadFile setelah WriteFile %d byte: %d byte, %v", len(data), len(got), err)
+		}
+	}
+	if err := fs.Remove("/d"); err == nil {
+		t.Fatal("direktori yang tidak kosong seharusnya tidak bisa dihapus")
+	}
+	if err := fs.Remove("/d/f"); err != nil {
+		t.Fatal(err)
+	}
+	if err := fs.Remove("/d"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := fs.Lookup("/d"); !errors.Is(err, ErrNotExist) {
+		t.Fatalf("'/d' masih ada setelah dihapus: %v", err)
+	}
+	if fs.countFreeBlocks() != free {
+		t.Fatalf("%d blok kosong, seharusnya %d", fs.countFreeBlocks(), free)
+	}
+}
+
+// Getwd dihitung dari pohon direktori, dan ChangeDirectory yang gagal tidak mengubah direktori kerja.
+func TestChangeDirectoryAndGetwd(t *testing.T) {
+	fs := newTestDisk(t, Geometry{})
+	for _, dir := range []string{"/a", "/a/b", "/a/b/c", "/x"} {
+		if err := fs.Mkdir(dir); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := fs.WriteFile("/a/f", []byte("f")); err != nil {
+		t.Fatal(err)
+	}
+
+	steps := []struct{ path, want string }{
+		{"/a/b/c", "/a/b/c"},
+		{"..", "/a/b"},
+		{"../../x", "/x"},
+		{"/", "/"},
+		{"..", "/"},
+		{"a//b/", "/a/b"},
+		{".", "/a/b"},
+	}
+	for _, step := range steps {
+		if err := fs.ChangeDirectory(step.path); err != nil {
+			t.Fatalf("ChangeDirectory('%s'): %v", step.path, err)
+		}
+		if wd, err := fs.Getwd(); err != nil || wd != step.want {
+			t.Fatalf("Getwd setelah cd '%s' = '%s', %v; seharusnya '%s'", step.path, wd, err, step.want)
+		}
+	}
+
+	for _, path := range []string{"/a/f", "/nope", "../nope/b"} {
+		if err := fs.ChangeDirectory(path); err == nil {
+			t.Fatalf("ChangeDirectory('%s') seharusnya gagal", path)
+		}
+		if wd, _ := fs.Getwd(); wd != "/a/b" {
+			t.Fatalf("direktori kerja berubah menjadi '%s' setelah cd '%s' gagal", wd, path)
+		}
+	}
+}
@@ -0,0 +1,290 @@
+// permission.go
+package filesystem_logic
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Izin akses gaya Unix. Setiap DirectoryEntry menyimpan Mode (bit rwx untuk pemilik, grup, dan lainnya),
+// UID pemilik, dan GID grupnya. Izin direktori dibaca dari entri "." di slot pertama blok direktori itu,
+// karena "." selalu ada (termasuk untuk root yang tidak punya entri di direktori induk); Chmod dan Chown
+// memperbarui entri di induk dan entri "." sekaligus.
+//
+// Aturan yang diperiksa terhadap FileSystem.CurrentUser:
+//   - r pada file: ReadFromFile dan Open untuk baca; r pada direktori: ListEntries
+//   - w pada file: WriteToFile dan Open untuk tulis
+//   - x pada direktori: melewatinya saat menelusuri path dan ChangeDirectory
+//   - w dan x pada direktori induk: membuat, menghapus, dan mengganti nama entri di dalamnya
+//
+// Pengguna dengan UID 0 (root) selalu lolos pemeriksaan.
+
+// FileMode: Bit izin rwx untuk pemilik, grup, dan lainnya (misalnya 0o755).
+type FileMode uint16
+
+const (
+	MODE_PERM_MASK    FileMode = 0o777
+	DEFAULT_FILE_MODE FileMode = 0o644 // File baru: pemilik baca/tulis, lainnya baca saja
+	DEFAULT_DIR_MODE  FileMode = 0o755 // Direktori baru: hanya pemilik yang boleh menambah/menghapus isinya
+	ROOT_DIR_MODE     FileMode = 0o777 // Root directory bisa ditulisi semua pengguna, seperti disk bersama
+	ROOT_UID                   = uint16(0)
+)
+
+// String: Bentuk "rwxr-xr-x" seperti ls -l.
+func (m FileMode) String() string {
+	const letters = "rwxrwxrwx"
+	var sb strings.Builder
+	for i := 0; i < 9; i++ {
+		if m&(1<<uint(8-i)) != 0 {
+			sb.WriteByte(letters[i])
+		} else {
+			sb.WriteByte('-')
+		}
+	}
+	return sb.String()
+}
+
+// ModeString: Mode entri dengan penanda tipe di depan, misalnya "drwxr-xr-x", "lrwxrwxrwx", atau "-rw-r--r--".
+func (de *DirectoryEntry) ModeString() string {
+	switch de.Type {
+	case TYPE_DIRECTORY:
+		return "d" + de.Mode.String()
+	case TYPE_SYMLINK:
+		return "l" + de.Mode.String()
+	}
+	return "-" + de.Mode.String()
+}
+
+// Access: Jenis akses yang diminta sebuah operasi (boleh digabung, misalnya ACCESS_WRITE|ACCESS_EXEC).
+type Access uint8
+
+const (
+	ACCESS_EXEC  Access = 1
+	ACCESS_WRITE Access = 2
+	ACCESS_READ  Access = 4
+)
+
+func (a Access) String() string {
+	s := ""
+	if a&ACCESS_READ != 0 {
+		s += "r"
+	}
+	if a&ACCESS_WRITE != 0 {
+		s += "w"
+	}
+	if a&ACCESS_EXEC != 0 {
+		s += "x"
+	}
+	return s
+}
+
+// User: Pengguna simulasi. Groups berisi grup tambahan selain grup utama GID.
+type User struct {
+	Name   string
+	UID    uint16
+	GID    uint16
+	Groups []uint16
+}
+
+// InGroup: true jika gid adalah grup utama atau salah satu grup tambahan pengguna.
+func (u User) InGroup(gid uint16) bool {
+	if u.GID == gid {
+		return true
+	}
+	for _, g := range u.Groups {
+		if g == gid {
+			return true
+		}
+	}
+	return false
+}
+
+// Group: Grup simulasi.
+type Group struct {
+	Name string
+	GID  uint16
+}
+
+// DefaultUsers: Pengguna bawaan simulator. alice dan bob sama-sama anggota grup "users"
+// sehingga izin grup bisa dicoba di antara keduanya.
+func DefaultUsers() []User {
+	return []User{
+		{Name: "root", UID: 0, GID: 0},
+		{Name: "alice", UID: 1000, GID: 1000, Groups: []uint16{100}},
+		{Name: "bob", UID: 1001, GID: 1001, Groups: []uint16{100}},
+		{Name: "guest", UID: 1002, GID: 1002},
+	}
+}
+
+// DefaultGroups: Grup bawaan simulator (grup pribadi setiap pengguna ditambah "users").
+func DefaultGroups() []Group {
+	return []Group{
+		{Name: "root", GID: 0},
+		{Name: "users", GID: 100},
+		{Name: "alice", GID: 1000},
+		{Name: "bob", GID: 1001},
+		{Name: "guest", GID: 1002},
+	}
+}
+
+// PermissionError: Operasi ditolak karena pengguna saat ini tidak punya izin yang dibutuhkan.
+// errors.Is(err, ErrPermission) bernilai true untuk error ini.
+type PermissionError struct {
+	Op    string // Operasi yang ditolak, misalnya "read", "list", "delete"
+	Path  string // Path atau nama entri yang diperiksa
+	User  string
+	UID   uint16
+	Need  Access   // Izin yang dibutuhkan
+	Mode  FileMode // Mode entri saat diperiksa
+	Owner uint16
+	Group uint16
+}
+
+func (e *PermissionError) Error() string {
+	return fmt.Sprintf("%s '%s': pengguna '%s' (uid %d) tidak punya izin %s (mode %s, pemilik %d:%d)",
+		e.Op, e.Path, e.User, e.UID, e.Need, e.Mode, e.Owner, e.Group)
+}
+
+func (e *PermissionError) Unwrap() error { return ErrPermission }
+
+// SetUser: Mengganti pengguna yang menjalankan operasi berikutnya.
+func (fs *FileSystem) SetUser(name string) error {
+	for _, u := range fs.Users {
+		if u.Name == name {
+			fs.CurrentUser = u
+			return nil
+		}
+	}
+	return fmt.Errorf("pengguna '%s' %w", name, ErrNotExist)
+}
+
+// UserName: Nama pengguna untuk uid, atau angkanya jika tidak dikenal.
+func (fs *FileSystem) UserName(uid uint16) string {
+	for _, u := range fs.Users {
+		if u.UID == uid {
+			return u.Name
+		}
+	}
+	return fmt.Sprint(uid)
+}
+
+// GroupName: Nama grup untuk gid, atau angkanya jika tidak dikenal.
+func (fs *FileSystem) GroupName(gid uint16) string {
+	for _, g := range fs.Groups {
+		if g.GID == gid {
+			return g.Name
+		}
+	}
+	return fmt.Sprint(gid)
+}
+
+// newEntryOwner: Mengisi mode dan pemilik entri baru dari pengguna saat ini.
+func (fs *FileSystem) newEntryOwner(entry *DirectoryEntry, mode FileMode) {
+	entry.Mode = mode
+	entry.UID = fs.CurrentUser.UID
+	entry.GID = fs.CurrentUser.GID
+}
+
+// checkAccess: Memeriksa apakah pengguna saat ini punya izin need pada entry.
+func (fs *FileSystem) checkAccess(op, path string, entry DirectoryEntry, need Access) error {
+	user := fs.CurrentUser
+	if user.UID == ROOT_UID {
+		return nil
+	}
+	var granted Access
+	switch {
+	case entry.UID == user.UID:
+		granted = Access(entry.Mode>>6) & 7
+	case user.InGroup(entry.GID):
+		granted = Access(entry.Mode>>3) & 7
+	default:
+		granted = Access(entry.Mode) & 7
+	}
+	if granted&need == need {
+		return nil
+	}
+	return &PermissionError{Op: op, Path: path, User: fs.UserName(user.UID), UID: user.UID, Need: need,
+		Mode: entry.Mode, Owner: entry.UID, Group: entry.GID}
+}
+
+// directoryMeta: Entri "." sebuah direktori, sumber izin dan pemilik direktori tersebut.
+func (fs *FileSystem) directoryMeta(dirBlock BlockID) (DirectoryEntry, error) {
+	if dirBlock < 0 || dirBlock >= BlockID(fs.Geometry.TotalBlocks) {
+		return DirectoryEntry{}, fmt.Errorf("blok direktori tidak valid: %d", dirBlock)
+	}
+	entry, err := fs.readSlot(dirSlot{Block: dirBlock, Offset: 0})
+	if err != nil || entry.NameString() != "." {
+		return DirectoryEntry{}, fmt.Errorf("direktori (Blok %d) tidak punya entri '.'", dirBlock)
+	}
+	return entry, nil
+}
+
+// checkDirAccess: checkAccess untuk direktori yang blok pertamanya dirBlock.
+func (fs *FileSystem) checkDirAccess(op string, dirBlock BlockID, need Access) error {
+	if fs.CurrentUser.UID == ROOT_UID {
+		return nil
+	}
+	meta, err := fs.directoryMeta(dirBlock)
+	if err != nil {
+		return err
+	}
+	err = fs.checkAccess(op, "", meta, need)
+	var permErr *PermissionError
+	if errors.As(err, &permErr) {
+		permErr.Path = fmt.Sprintf("Blok %d", dirBlock)
+		if p, errPath := fs.pathOfDirectory(dirBlock); errPath == nil {
+			permErr.Path = p
+		}
+	}
+	return err
+}
+
+// Chmod: Mengubah bit izin entri di path. Hanya pemilik atau root yang boleh.
+func (fs *FileSystem) Chmod(path string, mode FileMode) error {
+	return fs.updateMeta(path, "chmod", func(entry *DirectoryEntry) error {
+		if fs.CurrentUser.UID != ROOT_UID && fs.CurrentUser.UID != entry.UID {
+			return &PermissionError{Op: "chmod", Path: path, User: fs.UserName(fs.CurrentUser.UID), UID: fs.CurrentUser.UID,
+				Need: ACCESS_WRITE, Mode: entry.Mode, Owner: entry.UID, Group: entry.GID}
+		}
+		entry.Mode = mode & MODE_PERM_MASK
+		return nil
+	})
+}
+
+// Chown: Mengubah pemilik dan grup entri di path. Seperti di Unix, hanya root yang boleh mengganti pemilik;
+// pemilik entri boleh mengganti grupnya ke grup yang ia ikuti.
+func (fs *FileSystem) Chown(path string, uid, gid uint16) error {
+	return fs.updateMeta(path, "chown", func(entry *DirectoryEntry) error {
+		user := fs.CurrentUser
+		if user.UID != ROOT_UID && (uid != entry.UID || user.UID != entry.UID || !user.InGroup(gid)) {
+			return &PermissionError{Op: "chown", Path: path, User: fs.UserName(user.UID), UID: user.UID,
+				Need: ACCESS_WRITE, Mode: entry.Mode, Owner: entry.UID, Group: entry.GID}
+		}
+		entry.UID, entry.GID = uid, gid
+		return nil
+	})
+}
+
+// updateMeta: Mengubah mode/pemilik entri di path lewat change, lalu menulisnya ke semua slot entri
+// (lihat entrySlots) dan memperbarui ctime-nya.
+func (fs *FileSystem) updateMeta(path, op string, change func(entry *DirectoryEntry) error) error {
+	slots, entry, err := fs.entrySlots(path)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if err := change(&entry); err != nil {
+		return err
+	}
+	now := time.Now().UnixNano()
+	err = fs.updateSlots(slots, func(stored *DirectoryEntry) {
+		stored.Mode, stored.UID, stored.GID = entry.Mode, entry.UID, entry.GID
+		stored.ChangeTime = now
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s '%s': mode %s, pemilik %s:%s.\n", op, path, entry.Mode, fs.UserName(entry.UID), fs.GroupName(entry.GID))
+	return nil
+}
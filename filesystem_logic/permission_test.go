@@ -0,0 +1,125 @@
+package filesystem_logic
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+// Izin pemilik, grup, dan lainnya diperiksa terhadap pengguna saat ini; root selalu lolos.
+func TestPermissions(t *testing.T) {
+	fs := newTestDisk(t, Geometry{})
+	as := func(name string) {
+		t.Helper()
+		if err := fs.SetUser(name); err != nil {
+			t.Fatal(err)
+		}
+	}
+	as("alice")
+	if err := fs.Mkdir("/a"); err != nil {
+		t.Fatal(err)
+	}
+	if err := fs.WriteFile("/a/f", []byte("rahasia")); err != nil {
+		t.Fatal(err)
+	}
+	if entry, _ := fs.Lookup("/a/f"); entry.Mode != DEFAULT_FILE_MODE || entry.UID != 1000 || entry.GID != 1000 {
+		t.Fatalf("entri baru milik alice: mode %s, pemilik %d:%d", entry.Mode, entry.UID, entry.GID)
+	}
+
+	as("bob")
+	if _, err := fs.ReadFile("/a/f"); err != nil {
+		t.Fatalf("bob seharusnya boleh membaca lewat izin lainnya: %v", err)
+	}
+	denied := []struct {
+		name string
+		err  error
+	}{
+		{"tulis file alice", fs.WriteFile("/a/f", []byte("x"))},
+		{"buat file di direktori alice", fs.Create("/a/g")},
+		{"hapus file alice", fs.Remove("/a/f")},
+		{"chmod file alice", fs.Chmod("/a/f", 0o777)},
+		{"chown ke bob", fs.Chown("/a/f", 1001, 1001)},
+	}
+	for _, c := range denied {
+		if !errors.Is(c.err, ErrPermission) {
+			t.Errorf("%s: %v, seharusnya %v", c.name, c.err, ErrPermission)
+		}
+	}
+
+	// Grup "users" diikuti alice dan bob: izin grup berlaku setelah alice mengganti grup file
+	as("alice")
+	if err := fs.Chown("/a/f", 1000, 100); err != nil {
+		t.Fatal(err)
+	}
+	if err := fs.Chmod("/a/f", 0o660); err != nil {
+		t.Fatal(err)
+	}
+	if err := fs.Chown("/a/f", 1001, 100); !errors.Is(err, ErrPermission) {
+		t.Fatalf("alice mengganti pemilik file: %v", err)
+	}
+	as("bob")
+	if err := fs.WriteFile("/a/f", []byte("dari bob")); err != nil {
+		t.Fatalf("bob seharusnya boleh menulis lewat izin grup: %v", err)
+	}
+	as("guest")
+	if _, err := fs.ReadFile("/a/f"); !errors.Is(err, ErrPermission) {
+		t.Fatalf("guest membaca file 0660: %v", err)
+	}
+
+	// Tanpa x pada direktori, isinya tidak bisa dicapai sama sekali
+	as("alice")
+	if err := fs.Chmod("/a", 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if entry, err := fs.directoryMeta(mustLookup(t, fs, "/a").StartBlock); err != nil || entry.Mode != 0o700 {
+		t.Fatalf("entri '.' tidak ikut diperbarui Chmod: mode %s, %v", entry.Mode, err)
+	}
+	as("bob")
+	if _, err := fs.Lookup("/a/f"); !errors.Is(err, ErrPermission) {
+		t.Fatalf("bob menelusuri direktori 0700 milik alice: %v", err)
+	}
+	if err := fs.ChangeDirectory("/a"); !errors.Is(err, ErrPermission) {
+		t.Fatalf("bob masuk ke direktori 0700 milik alice: %v", err)
+	}
+
+	as("root")
+	if data, err := fs.ReadFile("/a/f"); err != nil || string(data) != "dari bob" {
+		t.Fatalf("root membaca /a/f: %q, %v", data, err)
+	}
+	if err := fs.SetUser("mallory"); !errors.Is(err, ErrNotExist) {
+		t.Fatalf("SetUser pengguna yang tidak dikenal: %v", err)
+	}
+}
+
+// Mode dan pemilik ikut tersimpan di image.
+func TestPermissionsImageRoundTrip(t *testing.T) {
+	fs := newTestDisk(t, Geometry{})
+	fs.Mkdir("/d")
+	if err := fs.Chown("/d", 1001, 100); err != nil {
+		t.Fatal(err)
+	}
+	if err := fs.Chmod("/d", 0o750); err != nil {
+		t.Fatal(err)
+	}
+	image := filepath.Join(t.TempDir(), "disk.img")
+	if err := fs.SaveImage(image); err != nil {
+		t.Fatal(err)
+	}
+	loaded := newTestDisk(t, Geometry{})
+	if err := loaded.LoadImage(image); err != nil {
+		t.Fatal(err)
+	}
+	entry := mustLookup(t, loaded, "/d")
+	if entry.Mode != 0o750 || entry.UID != 1001 || entry.GID != 100 || entry.ModeString() != "drwxr-x---" {
+		t.Fatalf("setelah LoadImage: %s %d:%d", entry.ModeString(), entry.UID, entry.GID)
+	}
+}
+
+func mustLookup(t *testing.T, fs *FileSystem, path string) DirectoryEntry {
+	t.Helper()
+	entry, err := fs.Lookup(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return entry
+}
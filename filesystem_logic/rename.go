@@ -0,0 +1,143 @@
+// rename.go
+package filesystem_logic
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Rename: Mengganti nama dan/atau memindahkan entri dari oldPath ke newPath tanpa menyalin blok data.
+// Jika direktori induknya sama dan nama lama maupun baru sama-sama nama pendek, hanya nama di slot entri
+// yang diganti. Selain itu entri (beserta slot LFN untuk nama panjangnya) ditambahkan ke direktori tujuan
+// lalu slot lamanya dikosongkan; untuk direktori, entri ".." ikut diarahkan ke induk barunya. Memindahkan direktori ke dalam dirinya sendiri atau subdirektorinya ditolak,
+// begitu juga jika newPath sudah ada.
+func (fs *FileSystem) Rename(oldPath, newPath string) error {
+	oldParent, oldName, err := fs.resolveParent(oldPath)
+	if err != nil {
+		return err
+	}
+	newParent, newName, err := fs.resolveParent(newPath)
+	if err != nil {
+		return err
+	}
+	if err := validateName(newName); err != nil {
+		return err
+	}
+
+	// Mengganti nama atau memindahkan entri butuh izin w dan x di direktori asal dan tujuan
+	for _, parent := range []BlockID{oldParent, newParent} {
+		if err := fs.checkDirAccess("rename", parent, ACCESS_WRITE|ACCESS_EXEC); err != nil {
+			return err
+		}
+	}
+
+	oldRec, err := fs.findRecord(oldParent, oldName)
+	if err != nil {
+		return err
+	}
+	entry := oldRec.Entry
+	if oldParent == newParent && sameName(oldName, newName) {
+		return nil // Tidak ada yang berubah
+	}
+	if _, _, errExist := fs.findEntrySlot(newParent, newName); errExist == nil {
+		return fmt.Errorf("'%s' %w", newPath, ErrExist)
+	} else if !errors.Is(errExist, ErrNotExist) {
+		return errExist
+	}
+
+	// Direktori tidak boleh dipindah ke dalam subtree-nya sendiri (akan membuat siklus yang terputus dari root)
+	if entry.Type == TYPE_DIRECTORY && oldParent != newParent {
+		inside, err := fs.isInsideDirectory(newParent, entry.StartBlock)
+		if err != nil {
+			return err
+		}
+		if inside {
+			return fmt.Errorf("tidak dapat memindahkan direktori '%s' ke dalam dirinya sendiri atau subdirektorinya ('%s')", oldPath, newPath)
+		}
+	}
+
+	renamed := entry
+	if err := fs.assignName(&renamed, newParent, newName); err != nil {
+		return err
+	}
+	renamed.ChangeTime = time.Now().UnixNano() // Rename mengubah ctime entri, bukan mtime-nya
+
+	// 1. Direktori induk sama dan tanpa slot LFN: cukup tulis ulang nama di slot yang sama
+	if oldParent == newParent && entry.LongName == "" && renamed.LongName == "" {
+		if err := fs.writeSlot(oldRec.Slot, renamed); err != nil {
+			return err
+		}
+		fs.touchRenamed(renamed, oldParent, newParent)
+		fmt.Printf("Entri '%s' diganti namanya menjadi '%s'.\n", oldName, newName)
+		return nil
+	}
+
+	// 2. Pindah direktori atau nama panjang: tambahkan entri di induk baru dulu, baru kosongkan slot lama
+	if err := fs.addEntryToDirectory(newParent, renamed); err != nil {
+		return fmt.Errorf("gagal menambahkan '%s' ke direktori tujuan: %w", newName, err)
+	}
+	newSlot, _, err := fs.findEntrySlot(newParent, newName)
+	if err != nil {
+		return err
+	}
+	fs.clearRecord(oldRec)
+	fs.FileTable.moveNode(oldRec.Slot, newSlot) // File yang sedang terbuka tetap menunjuk ke entri yang benar
+
+	// 3. Untuk direktori yang pindah, ".." harus menunjuk ke induk yang baru
+	if entry.Type == TYPE_DIRECTORY && oldParent != newParent {
+		dotDot, err := fs.findEntry(entry.StartBlock, "..")
+		if err != nil {
+			return fmt.Errorf("direktori '%s' tidak punya entri '..': %w", newPath, err)
+		}
+		dotDot.StartBlock = newParent
+		if parentMeta, errMeta := fs.directoryMeta(newParent); errMeta == nil {
+			dotDot.Inode = parentMeta.Inode // Pada layout inode, ".." menunjuk inode induk yang baru
+		}
+		if err := fs.updateEntryInDirectory(entry.StartBlock, dotDot); err != nil {
+			return fmt.Errorf("gagal memperbarui '..' di '%s': %w", newPath, err)
+		}
+	}
+
+	if err := fs.compactDirectory(oldParent); err != nil {
+		fmt.Printf("Warning: Gagal memadatkan direktori asal (Blok %d): %v\n", oldParent, err)
+	}
+	fs.touchRenamed(renamed, oldParent, newParent)
+	fmt.Printf("Entri '%s' dipindahkan ke direktori (Blok %d) sebagai '%s'.\n", oldName, newParent, newName)
+	return nil
+}
+
+// isInsideDirectory: true jika dirBlock sama dengan ancestorBlock atau berada di dalam subtree-nya.
+// Ditelusuri ke atas lewat entri ".." sampai root.
+func (fs *FileSystem) isInsideDirectory(dirBlock, ancestorBlock BlockID) (bool, error) {
+	for depth := 0; ; depth++ {
+		if dirBlock == ancestorBlock {
+			return true, nil
+		}
+		if dirBlock == fs.RootDirBlock {
+			return false, nil
+		}
+		if depth > fs.Geometry.TotalBlocks { // Pengaman jika entri ".." membentuk siklus
+			return false, fmt.Errorf("entri '..' mulai blok %d membentuk siklus", dirBlock)
+		}
+		parent, err := fs.findEntry(dirBlock, "..")
+		if err != nil {
+			return false, err
+		}
+		dirBlock = parent.StartBlock
+	}
+}
+
+// touchRenamed: Memperbarui timestamp setelah Rename: mtime/ctime direktori asal dan tujuan, dan untuk
+// direktori yang diganti nama, ctime di entri "." miliknya agar sama dengan entri di induknya.
+func (fs *FileSystem) touchRenamed(renamed DirectoryEntry, oldParent, newParent BlockID) {
+	fs.touchDirectory(oldParent)
+	if newParent != oldParent {
+		fs.touchDirectory(newParent)
+	}
+	if renamed.Type == TYPE_DIRECTORY {
+		fs.updateSlots([]dirSlot{{Block: renamed.StartBlock, Offset: 0}}, func(entry *DirectoryEntry) {
+			entry.ChangeTime = renamed.ChangeTime
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package filesystem_logic
+
+import (
+	"errors"
+	"testing"
+)
+
+// Rename di direktori yang sama hanya mengganti nama; memindahkan direktori mengarahkan ".." ke induk
+// barunya. Blok data tidak disalin.
+func TestRenameAndMove(t *testing.T) {
+	fs := newTestDisk(t, Geometry{})
+	for _, dir := range []string{"/src", "/src/sub", "/dst"} {
+		if err := fs.Mkdir(dir); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := fs.WriteFile("/src/sub/f.txt", []byte("isi")); err != nil {
+		t.Fatal(err)
+	}
+	before, _ := fs.Lookup("/src/sub/f.txt")
+	free := fs.countFreeBlocks()
+
+	if err := fs.Rename("/src/sub/f.txt", "/src/sub/g.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if err := fs.Rename("/src/sub", "/dst/moved"); err != nil {
+		t.Fatal(err)
+	}
+	after, err := fs.Lookup("/dst/moved/g.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if after.StartBlock != before.StartBlock || fs.countFreeBlocks() != free {
+		t.Fatalf("blok data berpindah: %d -> %d, blok kosong %d -> %d", before.StartBlock, after.StartBlock, free, fs.countFreeBlocks())
+	}
+	if _, err := fs.Lookup("/src/sub"); !errors.Is(err, ErrNotExist) {
+		t.Fatalf("'/src/sub' masih ada setelah dipindah: %v", err)
+	}
+
+	// ".." direktori yang dipindah menunjuk ke /dst, jadi path relatif dan Getwd mengikuti lokasi barunya
+	dst, _ := fs.Lookup("/dst")
+	if dotDot, _ := fs.Lookup("/dst/moved/.."); dotDot.StartBlock != dst.StartBlock {
+		t.Fatalf("'..' di direktori yang dipindah menunjuk ke blok %d, seharusnya %d", dotDot.StartBlock, dst.StartBlock)
+	}
+	if err := fs.ChangeDirectory("/dst/moved"); err != nil {
+		t.Fatal(err)
+	}
+	if wd, _ := fs.Getwd(); wd != "/dst/moved" {
+		t.Fatalf("Getwd = '%s'", wd)
+	}
+	if data, err := fs.ReadFile("../moved/g.txt"); err != nil || string(data) != "isi" {
+		t.Fatalf("ReadFile lewat '..': %q, %v", data, err)
+	}
+}
+
+// Memindahkan direktori ke dalam subtree-nya sendiri dan menimpa entri yang sudah ada ditolak tanpa
+// mengubah apa pun.
+func TestRenameRejected(t *testing.T) {
+	fs := newTestDisk(t, Geometry{})
+	for _, dir := range []string{"/a", "/a/b", "/a/b/c"} {
+		if err := fs.Mkdir(dir); err != nil {
+			t.Fatal(err)
+		}
+	}
+	fs.WriteFile("/x", []byte("x"))
+	fs.WriteFile("/y", []byte("y"))
+
+	for _, target := range []string{"/a/in", "/a/b/in", "/a/b/c/in"} {
+		if err := fs.Rename("/a", target); err == nil {
+			t.Fatalf("memindahkan /a ke '%s' seharusnya ditolak", target)
+		}
+	}
+	if err := fs.Rename("/x", "/y"); !errors.Is(err, ErrExist) {
+		t.Fatalf("rename ke nama yang sudah ada: %v", err)
+	}
+	if err := fs.Rename("/nope", "/z"); !errors.Is(err, ErrNotExist) {
+		t.Fatalf("rename entri yang tidak ada: %v", err)
+	}
+	for path, want := range map[string]string{"/x": "x", "/y": "y"} {
+		if data, _ := fs.ReadFile(path); string(data) != want {
+			t.Fatalf("isi %s berubah: %q", path, data)
+		}
+	}
+	if _, err := fs.Lookup("/a/b/c"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// File yang sedang terbuka tetap bisa ditulis lewat handle-nya setelah dipindah ke direktori lain.
+func TestRenameOpenFile(t *testing.T) {
+	fs := newTestDisk(t, Geometry{})
+	fs.Mkdir("/d")
+	f, err := fs.Open("/f", O_RDWR|O_CREATE)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := fs.Rename("/f", "/d/f"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.Write([]byte("setelah pindah")); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+	if data, _ := fs.ReadFile("/d/f"); string(data) != "setelah pindah" {
+		t.Fatalf("isi /d/f: %q", data)
+	}
+}
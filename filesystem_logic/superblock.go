@@ -0,0 +1,212 @@
+// superblock.go
+package filesystem_logic
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"strings"
+	"time"
+)
+
+const (
+	SUPERBLOCK_BLOCK     = BlockID(0) // Blok 0 selalu berisi superblock
+	SUPERBLOCK_MAGIC     = "GOFATSIM" // Penanda bahwa disk ini diformat oleh simulator kita
+	FORMAT_VERSION       = uint16(10) // Naikkan setiap kali format on-disk berubah (v2: FAT di disk, v3: geometri lengkap, v4: izin, v5: atime/ctime/btime, v6: slot LFN, v7: symlink dan Nlink, v8: tabel inode opsional, v9: metode alokasi, v10: manajemen ruang kosong)
+	VOLUME_LABEL_LEN     = 16         // Panjang tetap label volume di superblock
+	DEFAULT_VOLUME_LABEL = "SIMDISK"  // Label yang dipakai FormatDisk
+	SUPERBLOCK_SIZE      = 8 + 2 + 4 + 4 + 4 + 4 + 4 + 4 + 4 + 4 + 4 + 4 + 4 + 4 + 4 + 4 + VOLUME_LABEL_LEN + 8 + 4
+)
+
+// Superblock: Informasi geometri dan metadata volume yang disimpan di blok 0.
+// Dengan superblock, image disk bisa "menjelaskan dirinya sendiri" dan image asing/rusak bisa ditolak saat dimuat.
+type Superblock struct {
+	Magic          [8]byte                // Harus sama dengan SUPERBLOCK_MAGIC
+	Version        uint16                 // Versi format on-disk (FORMAT_VERSION)
+	BlockSize      int32                  // Geometry.BlockSize saat diformat
+	TotalBlocks    int32                  // Geometry.TotalBlocks saat diformat
+	MaxFilenameLen int32                  // Geometry.MaxFilenameLen saat diformat
+	ReservedBlocks int32                  // Geometry.ReservedBlocks (termasuk superblock)
+	RootBlock      BlockID                // Blok pertama root directory
+	FATStart       BlockID                // Blok pertama FAT (salinan pertama) di disk
+	FATBlocks      int32                  // Jumlah blok yang dipakai satu salinan FAT
+	FATCopies      int32                  // Jumlah salinan FAT (1, atau 2 jika memakai mirror)
+	InodeCount     int32                  // Geometry.Inodes (0 jika metadata disimpan di entri direktori)
+	InodeStart     BlockID                // Blok pertama tabel inode (sama dengan RootBlock jika tidak ada tabel inode)
+	Allocation     int32                  // Geometry.Allocation (lihat alloc.go)
+	FreeSpace      int32                  // Geometry.FreeSpace (lihat freespace.go)
+	FreeHead       BlockID                // Kepala free list, blok grup pertama, atau run kosong pertama (FAT_EOF jika tidak dipakai)
+	FreeBlocks     int32                  // Jumlah blok kosong menurut FAT
+	VolumeLabel    [VOLUME_LABEL_LEN]byte // Label volume (diisi 0 di belakang)
+	CreatedAt      int64                  // Waktu format (Unix nanoseconds)
+	Checksum       uint32                 // CRC32 dari semua field sebelumnya
+}
+
+// Label: Mengembalikan label volume sebagai string.
+func (sb *Superblock) Label() string {
+	return strings.TrimRight(string(sb.VolumeLabel[:]), "\x00")
+}
+
+// Serialize: Mengubah superblock menjadi byte, sekaligus menghitung checksum-nya.
+func (sb *Superblock) Serialize() ([]byte, error) {
+	buf := new(bytes.Buffer)
+	// Semua field kecuali Checksum ditulis dulu agar checksum bisa dihitung darinya
+	fields := []interface{}{sb.Magic, sb.Version, sb.BlockSize, sb.TotalBlocks, sb.MaxFilenameLen, sb.ReservedBlocks, sb.RootBlock,
+		sb.FATStart, sb.FATBlocks, sb.FATCopies, sb.InodeCount, sb.InodeStart, sb.Allocation, sb.FreeSpace, sb.FreeHead, sb.FreeBlocks, sb.VolumeLabel, sb.CreatedAt}
+	for _, field := range fields {
+		if err := binary.Write(buf, binary.LittleEndian, field); err != nil {
+			return nil, fmt.Errorf("serialize superblock: %w", err)
+		}
+	}
+	sb.Checksum = crc32.ChecksumIEEE(buf.Bytes())
+	if err := binary.Write(buf, binary.LittleEndian, sb.Checksum); err != nil {
+		return nil, fmt.Errorf("serialize superblock checksum: %w", err)
+	}
+
+	serializedData := buf.Bytes()
+	if len(serializedData) != SUPERBLOCK_SIZE {
+		return nil, fmt.Errorf("serialized superblock length %d does not match SUPERBLOCK_SIZE %d", len(serializedData), SUPERBLOCK_SIZE)
+	}
+	return serializedData, nil
+}
+
+// DeserializeSuperblock: Membaca superblock dari data blok 0 dan memvalidasinya.
+// Error yang dikembalikan sengaja dibuat jelas agar pengguna tahu kenapa image ditolak.
+func DeserializeSuperblock(data []byte) (Superblock, error) {
+	var sb Superblock
+	if len(data) < SUPERBLOCK_SIZE {
+		return sb, errors.New("insufficient data to deserialize superblock")
+	}
+	if err := binary.Read(bytes.NewReader(data[:SUPERBLOCK_SIZE]), binary.LittleEndian, &sb); err != nil {
+		return sb, fmt.Errorf("deserialize superblock: %w", err)
+	}
+
+	// 1. Magic: membedakan image simulator dengan file lain
+	if string(sb.Magic[:]) != SUPERBLOCK_MAGIC {
+		return sb, fmt.Errorf("superblock tidak valid: magic %q bukan %q (bukan disk simulator ini)", sb.Magic[:], SUPERBLOCK_MAGIC)
+	}
+	// 2. Checksum: mendeteksi superblock yang rusak
+	if sum := crc32.ChecksumIEEE(data[:SUPERBLOCK_SIZE-4]); sum != sb.Checksum {
+		return sb, fmt.Errorf("superblock rusak: checksum 0x%08x tidak cocok dengan 0x%08x", sum, sb.Checksum)
+	}
+	// 3. Versi format
+	if sb.Version != FORMAT_VERSION {
+		return sb, fmt.Errorf("versi format disk %d tidak didukung (simulator memakai versi %d)", sb.Version, FORMAT_VERSION)
+	}
+	return sb, nil
+}
+
+// Geometry: Geometri disk seperti yang tercatat di superblock.
+func (sb *Superblock) Geometry() Geometry {
+	return Geometry{
+		BlockSize:      int(sb.BlockSize),
+		TotalBlocks:    int(sb.TotalBlocks),
+		MaxFilenameLen: int(sb.MaxFilenameLen),
+		ReservedBlocks: int(sb.ReservedBlocks),
+		FATCopies:      int(sb.FATCopies),
+		Inodes:         int(sb.InodeCount),
+		Allocation:     AllocationMethod(sb.Allocation),
+		FreeSpace:      FreeSpaceMethod(sb.FreeSpace),
+	}
+}
+
+// validateGeometry: Memastikan geometri di superblock masuk akal dan konsisten dengan letak FAT/root.
+func (sb *Superblock) validateGeometry() error {
+	geo := sb.Geometry()
+	if err := geo.Validate(); err != nil {
+		return fmt.Errorf("superblock tidak valid: %w", err)
+	}
+	if sb.FATStart != geo.FATStart() || int(sb.FATBlocks) != geo.FATBlocksPerCopy() {
+		return fmt.Errorf("superblock tidak valid: area FAT (mulai blok %d, %d blok x %d salinan) tidak sesuai geometri",
+			sb.FATStart, sb.FATBlocks, sb.FATCopies)
+	}
+	if sb.InodeStart != geo.InodeStart() {
+		return fmt.Errorf("superblock tidak valid: tabel inode mulai blok %d tidak sesuai geometri", sb.InodeStart)
+	}
+	if sb.RootBlock != geo.RootBlock() {
+		return fmt.Errorf("superblock tidak valid: root block %d tidak sesuai geometri", sb.RootBlock)
+	}
+	if sb.FreeBlocks < 0 || sb.FreeBlocks > sb.TotalBlocks {
+		return fmt.Errorf("superblock tidak valid: jumlah blok kosong %d", sb.FreeBlocks)
+	}
+	if sb.FreeHead != FAT_EOF && (sb.FreeHead < 0 || sb.FreeHead >= BlockID(sb.TotalBlocks)) {
+		return fmt.Errorf("superblock tidak valid: kepala struktur ruang kosong %d", sb.FreeHead)
+	}
+	return nil
+}
+
+// countFreeBlocks: Menghitung blok yang bertanda FAT_FREE.
+func (fs *FileSystem) countFreeBlocks() int32 {
+	var free int32
+	for _, next := range fs.FAT {
+		if next == FAT_FREE {
+			free++
+		}
+	}
+	return free
+}
+
+// ReadSuperblock: Membaca dan memvalidasi superblock dari fs.Disk[SUPERBLOCK_BLOCK].
+func (fs *FileSystem) ReadSuperblock() (Superblock, error) {
+	if fs.Disk == nil {
+		return Superblock{}, errors.New("disk belum diformat")
+	}
+	sb, err := DeserializeSuperblock(fs.Disk[SUPERBLOCK_BLOCK])
+	if err != nil {
+		return sb, err
+	}
+	return sb, sb.validateGeometry()
+}
+
+// writeSuperblock: Menulis superblock ke fs.Disk[SUPERBLOCK_BLOCK].
+func (fs *FileSystem) writeSuperblock(sb *Superblock) error {
+	sbBytes, err := sb.Serialize()
+	if err != nil {
+		return err
+	}
+	if len(sbBytes) > len(fs.Disk[SUPERBLOCK_BLOCK]) {
+		return errors.New("block size too small for superblock")
+	}
+	for i := range fs.Disk[SUPERBLOCK_BLOCK] {
+		fs.Disk[SUPERBLOCK_BLOCK][i] = 0
+	}
+	copy(fs.Disk[SUPERBLOCK_BLOCK], sbBytes)
+	return nil
+}
+
+// newSuperblock: Membuat superblock baru untuk disk yang sedang diformat (FreeBlocks diisi FormatDisk setelah FAT final).
+// Root directory diletakkan tepat setelah area FAT, tabel inode, dan bitmap.
+func newSuperblock(volumeLabel string, geo Geometry) Superblock {
+	var sb Superblock
+	copy(sb.Magic[:], SUPERBLOCK_MAGIC)
+	sb.Version = FORMAT_VERSION
+	sb.BlockSize = int32(geo.BlockSize)
+	sb.TotalBlocks = int32(geo.TotalBlocks)
+	sb.MaxFilenameLen = int32(geo.MaxFilenameLen)
+	sb.ReservedBlocks = int32(geo.ReservedBlocks)
+	sb.FATStart = geo.FATStart()
+	sb.FATBlocks = int32(geo.FATBlocksPerCopy())
+	sb.FATCopies = int32(geo.FATCopies)
+	sb.InodeCount = int32(geo.Inodes)
+	sb.InodeStart = geo.InodeStart()
+	sb.Allocation = int32(geo.Allocation)
+	sb.FreeSpace = int32(geo.FreeSpace)
+	sb.FreeHead = FAT_EOF // Diisi FormatDisk setelah FAT final (lihat rebuildFreeSpace)
+	sb.RootBlock = geo.RootBlock()
+	copy(sb.VolumeLabel[:], volumeLabel)
+	sb.CreatedAt = time.Now().UnixNano()
+	return sb
+}
+
+// SyncSuperblock: Menghitung ulang field superblock yang berubah selama disk dipakai (jumlah blok kosong)
+// lalu menulisnya kembali ke blok 0. setFAT sudah menjaga nilainya, tapi ini dipanggil lagi
+// sebelum disk disimpan ke image sebagai pengaman.
+func (fs *FileSystem) SyncSuperblock() error {
+	if _, err := fs.ReadSuperblock(); err != nil {
+		return fmt.Errorf("gagal membaca superblock untuk sinkronisasi: %w", err)
+	}
+	fs.superblock.FreeBlocks = fs.countFreeBlocks()
+	return fs.writeSuperblock(&fs.superblock)
+}
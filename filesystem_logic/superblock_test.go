@@ -0,0 +1,77 @@
+package filesystem_logic
+
+import (
+	"encoding/binary"
+	"hash/crc32"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// Disk yang baru diformat punya superblock valid di blok 0, dan jumlah blok kosongnya diperbarui saat
+// disk disimpan ke image.
+func TestSuperblockFormatAndSync(t *testing.T) {
+	fs := newTestDisk(t, Geometry{})
+	sb, err := fs.ReadSuperblock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sb.Label() != DEFAULT_VOLUME_LABEL || sb.RootBlock != fs.RootDirBlock || sb.FreeBlocks != fs.countFreeBlocks() {
+		t.Fatalf("superblock setelah format: %+v", sb)
+	}
+	if fs.FAT[SUPERBLOCK_BLOCK] == FAT_FREE {
+		t.Fatal("blok superblock bertanda kosong di FAT")
+	}
+
+	writeTestFile(t, fs, "isi.txt", make([]byte, 2*fs.Geometry.BlockSize))
+	if err := fs.SaveImage(filepath.Join(t.TempDir(), "disk.img")); err != nil {
+		t.Fatal(err)
+	}
+	if sb, err = fs.ReadSuperblock(); err != nil {
+		t.Fatal(err)
+	}
+	if sb.FreeBlocks != fs.countFreeBlocks() {
+		t.Fatalf("superblock mencatat %d blok kosong, FAT %d", sb.FreeBlocks, fs.countFreeBlocks())
+	}
+}
+
+// Image dengan superblock asing, rusak (checksum tidak cocok), atau versi format lain ditolak LoadImage.
+func TestLoadImageRejectsBadSuperblock(t *testing.T) {
+	dir := t.TempDir()
+	image := filepath.Join(dir, "disk.img")
+	fs := newTestDisk(t, Geometry{})
+	if err := fs.SaveImage(image); err != nil {
+		t.Fatal(err)
+	}
+	valid, err := os.ReadFile(image)
+	if err != nil {
+		t.Fatal(err)
+	}
+	block0 := int(SUPERBLOCK_BLOCK) * fs.Geometry.BlockSize
+
+	cases := []struct {
+		name, want string
+		patch      func(sb []byte)
+	}{
+		{"magic", "magic", func(sb []byte) { copy(sb, "NOTAFSIM") }},
+		{"crc", "checksum", func(sb []byte) { sb[len(SUPERBLOCK_MAGIC)+2] ^= 0xff }},
+		{"versi", "versi format", func(sb []byte) {
+			binary.LittleEndian.PutUint16(sb[len(SUPERBLOCK_MAGIC):], FORMAT_VERSION+1)
+			binary.LittleEndian.PutUint32(sb[SUPERBLOCK_SIZE-4:], crc32.ChecksumIEEE(sb[:SUPERBLOCK_SIZE-4]))
+		}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			data := append([]byte{}, valid...)
+			c.patch(data[block0 : block0+fs.Geometry.BlockSize])
+			path := filepath.Join(dir, c.name+".img")
+			if err := os.WriteFile(path, data, 0o644); err != nil {
+				t.Fatal(err)
+			}
+			if err := fs.LoadImage(path); err == nil || !strings.Contains(err.Error(), c.want) {
+				t.Fatalf("LoadImage seharusnya gagal dengan '%s', dapat: %v", c.want, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,171 @@
+// times.go
+package filesystem_logic
+
+import (
+	"fmt"
+	"time"
+)
+
+// Setiap entri menyimpan empat timestamp (Unix nanoseconds), mengikuti stat(2):
+//
+//	ModTime    (mtime): isi berubah; untuk direktori, entri di dalamnya ditambah, dihapus, atau diganti nama
+//	AccessTime (atime): isi dibaca (baca file, ListEntries direktori), tergantung MountOptions.Atime
+//	ChangeTime (ctime): isi atau metadata berubah (tulis, chmod/chown, rename)
+//	BirthTime  (btime): waktu entri dibuat, tidak pernah berubah
+//
+// Seperti izin, timestamp direktori ada di dua tempat: entri "." miliknya dan entrinya di direktori
+// induk. Keduanya selalu diperbarui bersamaan lewat directorySlots.
+
+// AtimeMode: Kapan atime diperbarui saat file atau direktori dibaca (meniru opsi mount Linux).
+type AtimeMode int
+
+const (
+	ATIME_RELATIME AtimeMode = iota // Bawaan: hanya jika atime <= mtime/ctime atau sudah lebih dari RELATIME_INTERVAL
+	ATIME_STRICT                    // strictatime: setiap pembacaan memperbarui atime
+	ATIME_NOATIME                   // noatime: atime tidak pernah diperbarui
+)
+
+// RELATIME_INTERVAL: Batas umur atime pada mode relatime, sama seperti di Linux.
+const RELATIME_INTERVAL = 24 * time.Hour
+
+func (m AtimeMode) String() string {
+	switch m {
+	case ATIME_STRICT:
+		return "strictatime"
+	case ATIME_NOATIME:
+		return "noatime"
+	default:
+		return "relatime"
+	}
+}
+
+// MountOptions: Opsi yang berlaku selama disk di-mount. Tidak disimpan di image.
+type MountOptions struct {
+	Atime AtimeMode
+	Fit   FitStrategy // Cara memilih run blok kosong saat alokasi (lihat contiguous.go)
+}
+
+// stampNew: Mengisi keempat timestamp entri yang baru dibuat beserta link count awalnya (satu link).
+func stampNew(entry *DirectoryEntry, now int64) {
+	entry.ModTime, entry.AccessTime, entry.ChangeTime, entry.BirthTime = now, now, now, now
+	entry.Nlink = 1
+}
+
+// directorySlots: Slot entri "." direktori dan slot entrinya di direktori induk (root hanya punya ".").
+func (fs *FileSystem) directorySlots(dirBlock BlockID) ([]dirSlot, error) {
+	slots := []dirSlot{{Block: dirBlock, Offset: 0}}
+	if dirBlock == fs.RootDirBlock {
+		return slots, nil
+	}
+	dotDot, err := fs.findEntry(dirBlock, "..")
+	if err != nil {
+		return nil, err
+	}
+	found := false
+	err = fs.scanDirectory(dotDot.StartBlock, func(rec dirRecord) bool {
+		name := rec.Entry.ShortName()
+		if rec.Entry.Type == TYPE_DIRECTORY && rec.Entry.StartBlock == dirBlock && name != "." && name != ".." {
+			slots, found = append(slots, rec.Slot), true
+			return false
+		}
+		return true
+	})
+	if found {
+		return slots, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return nil, fmt.Errorf("direktori (Blok %d) tidak ditemukan di induknya (Blok %d)", dirBlock, dotDot.StartBlock)
+}
+
+// entrySlots: Semua slot yang menyimpan metadata entri di path beserta entrinya (symlink diikuti).
+// Untuk file dengan beberapa hard link cukup satu slot; writeSlot menyalin perubahannya ke link lain.
+func (fs *FileSystem) entrySlots(path string) ([]dirSlot, DirectoryEntry, error) {
+	entry, err := fs.Lookup(path)
+	if err != nil {
+		return nil, entry, err
+	}
+	if entry.Type == TYPE_DIRECTORY {
+		slots, err := fs.directorySlots(entry.StartBlock)
+		return slots, entry, err
+	}
+	parentBlock, name, err := fs.resolveTarget(path)
+	if err != nil {
+		return nil, entry, err
+	}
+	slot, entry, err := fs.findEntrySlot(parentBlock, name)
+	if err != nil {
+		return nil, entry, err
+	}
+	return []dirSlot{slot}, entry, nil
+}
+
+// updateSlots: Menerapkan change pada entri di setiap slot lalu menulisnya kembali.
+func (fs *FileSystem) updateSlots(slots []dirSlot, change func(entry *DirectoryEntry)) error {
+	for _, slot := range slots {
+		entry, err := fs.readSlot(slot)
+		if err != nil {
+			return err
+		}
+		change(&entry)
+		if err := fs.writeSlot(slot, entry); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// touchDirectory: Memperbarui mtime dan ctime direktori setelah entri di dalamnya ditambah, dihapus, atau diganti nama.
+func (fs *FileSystem) touchDirectory(dirBlock BlockID) {
+	slots, err := fs.directorySlots(dirBlock)
+	if err == nil {
+		now := time.Now().UnixNano()
+		err = fs.updateSlots(slots, func(entry *DirectoryEntry) {
+			entry.ModTime, entry.ChangeTime = now, now
+		})
+	}
+	if err != nil {
+		fmt.Printf("Warning: Gagal memperbarui waktu direktori (Blok %d): %v\n", dirBlock, err)
+	}
+}
+
+// atimeDue: true jika pembacaan sekarang harus memperbarui atime entry menurut MountOptions.Atime.
+func (fs *FileSystem) atimeDue(entry DirectoryEntry, now int64) bool {
+	switch fs.Mount.Atime {
+	case ATIME_NOATIME:
+		return false
+	case ATIME_STRICT:
+		return true
+	default:
+		return entry.AccessTime <= entry.ModTime || entry.AccessTime <= entry.ChangeTime ||
+			now-entry.AccessTime >= int64(RELATIME_INTERVAL)
+	}
+}
+
+// noteAccess: Memperbarui atime entri di slots (jika perlu) setelah isinya dibaca.
+func (fs *FileSystem) noteAccess(slots []dirSlot) {
+	if len(slots) == 0 || fs.Mount.Atime == ATIME_NOATIME {
+		return
+	}
+	now := time.Now().UnixNano()
+	entry, err := fs.readSlot(slots[0])
+	if err != nil || !fs.atimeDue(entry, now) {
+		return
+	}
+	fs.updateSlots(slots, func(entry *DirectoryEntry) { entry.AccessTime = now })
+}
+
+// noteDirectoryAccess: noteAccess untuk direktori yang isinya baru saja dibaca (ListEntries).
+func (fs *FileSystem) noteDirectoryAccess(dirBlock BlockID) {
+	if fs.Mount.Atime == ATIME_NOATIME {
+		return
+	}
+	meta, err := fs.directoryMeta(dirBlock)
+	if err != nil || !fs.atimeDue(meta, time.Now().UnixNano()) {
+		return
+	}
+	if slots, err := fs.directorySlots(dirBlock); err == nil {
+		fs.noteAccess(slots)
+	}
+}
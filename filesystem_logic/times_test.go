@@ -0,0 +1,94 @@
+package filesystem_logic
+
+import (
+	"testing"
+	"time"
+)
+
+// age: Memundurkan keempat timestamp entri di path ke waktu then, agar perubahan sesudahnya terlihat.
+func age(t *testing.T, fs *FileSystem, path string, then int64) {
+	t.Helper()
+	slots, _, err := fs.entrySlots(path)
+	if err == nil {
+		err = fs.updateSlots(slots, func(entry *DirectoryEntry) { stampNew(entry, then) })
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// Menulis mengubah mtime dan ctime, Chmod hanya ctime, dan btime tidak pernah berubah. Entri baru di
+// direktori memperbarui mtime direktori itu di entri "." maupun di induknya.
+func TestTimestamps(t *testing.T) {
+	fs := newTestDisk(t, Geometry{})
+	start := time.Now().UnixNano()
+	fs.Mkdir("/d")
+	if err := fs.WriteFile("/d/f", []byte("a")); err != nil {
+		t.Fatal(err)
+	}
+	if entry := mustLookup(t, fs, "/d/f"); entry.BirthTime < start || entry.ModTime < entry.BirthTime {
+		t.Fatalf("timestamp file baru: btime %d, mtime %d, mulai %d", entry.BirthTime, entry.ModTime, start)
+	}
+
+	then := start - int64(time.Hour)
+	age(t, fs, "/d/f", then)
+	if err := fs.Chmod("/d/f", 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if entry := mustLookup(t, fs, "/d/f"); entry.ChangeTime <= then || entry.ModTime != then || entry.BirthTime != then {
+		t.Fatalf("setelah Chmod: %+v", entry)
+	}
+	if err := fs.WriteFile("/d/f", []byte("b")); err != nil {
+		t.Fatal(err)
+	}
+	if entry := mustLookup(t, fs, "/d/f"); entry.ModTime <= then || entry.BirthTime != then {
+		t.Fatalf("setelah WriteFile: %+v", entry)
+	}
+
+	age(t, fs, "/d", then)
+	if err := fs.Create("/d/g"); err != nil {
+		t.Fatal(err)
+	}
+	dir := mustLookup(t, fs, "/d")
+	dot, err := fs.directoryMeta(dir.StartBlock)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dir.ModTime <= then || dot.ModTime != dir.ModTime || dot.BirthTime != then {
+		t.Fatalf("mtime direktori setelah Create: induk %d, '.' %d", dir.ModTime, dot.ModTime)
+	}
+}
+
+// Kapan membaca file memperbarui atime tergantung MountOptions.Atime.
+func TestAtimeModes(t *testing.T) {
+	then := time.Now().Add(-time.Hour).UnixNano()
+	cases := []struct {
+		mode             AtimeMode
+		first, secondNew bool // atime berubah pada pembacaan pertama / kedua
+	}{
+		{ATIME_STRICT, true, true},
+		{ATIME_RELATIME, true, false},
+		{ATIME_NOATIME, false, false},
+	}
+	for _, c := range cases {
+		t.Run(c.mode.String(), func(t *testing.T) {
+			fs := newTestDisk(t, Geometry{})
+			fs.Mount.Atime = c.mode
+			if err := fs.WriteFile("/f", []byte("isi")); err != nil {
+				t.Fatal(err)
+			}
+			age(t, fs, "/f", then)
+
+			fs.ReadFile("/f")
+			first := mustLookup(t, fs, "/f").AccessTime
+			if (first != then) != c.first {
+				t.Fatalf("atime setelah baca pertama: %d (sebelumnya %d)", first, then)
+			}
+			time.Sleep(time.Millisecond)
+			fs.ReadFile("/f")
+			if second := mustLookup(t, fs, "/f").AccessTime; (second != first) != c.secondNew {
+				t.Fatalf("atime setelah baca kedua: %d (sebelumnya %d)", second, first)
+			}
+		})
+	}
+}
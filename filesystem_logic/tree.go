@@ -0,0 +1,135 @@
+// tree.go
+package filesystem_logic
+
+import (
+	"errors"
+	"fmt"
+	"path"
+)
+
+// ProgressFunc: Dipanggil setelah setiap entri selesai diproses oleh operasi rekursif (RemoveAll, CopyTo).
+// doneBytes/totalBytes menghitung isi file; current adalah path entri yang baru selesai.
+type ProgressFunc func(doneBytes, totalBytes int64, current string)
+
+// TreeSize: Ringkasan isi sebuah file atau pohon direktori.
+type TreeSize struct {
+	Files       int
+	Directories int // Termasuk direktori teratas
+	Bytes       int64
+}
+
+// walkTree: Menelusuri entry dan seluruh isinya secara depth-first. visit dipanggil untuk setiap
+// entri (direktori sebelum isinya); relPath adalah path relatif terhadap entry ("" untuk entry itu sendiri).
+func (fs *FileSystem) walkTree(entry DirectoryEntry, relPath string, visit func(relPath string, entry DirectoryEntry) error) error {
+	if err := visit(relPath, entry); err != nil {
+		return err
+	}
+	if entry.Type != TYPE_DIRECTORY {
+		return nil
+	}
+	children, err := fs.ListEntries(entry.StartBlock)
+	if err != nil {
+		return err
+	}
+	for _, child := range children {
+		name := child.NameString()
+		if name == "." || name == ".." {
+			continue
+		}
+		if err := fs.walkTree(child, path.Join(relPath, name), visit); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// MeasureTree: Menghitung jumlah file, direktori, dan total byte di path (file atau direktori beserta isinya).
+// Dipakai GUI untuk meminta konfirmasi sebelum RemoveAll atau CopyTo.
+func (fs *FileSystem) MeasureTree(p string) (TreeSize, error) {
+	var size TreeSize
+	entry, err := fs.Lstat(p)
+	if err != nil {
+		return size, err
+	}
+	err = fs.walkTree(entry, "", func(_ string, e DirectoryEntry) error {
+		if e.Type == TYPE_DIRECTORY {
+			size.Directories++
+		} else {
+			size.Files++
+			size.Bytes += e.Size
+		}
+		return nil
+	})
+	return size, err
+}
+
+// RemoveAll: Menghapus file atau direktori beserta seluruh isinya. Path yang tidak ada bukan error.
+func (fs *FileSystem) RemoveAll(p string) error {
+	return fs.RemoveAllProgress(p, nil)
+}
+
+// RemoveAllProgress: Seperti RemoveAll, dengan progress dipanggil setelah setiap entri terhapus.
+// Isi direktori dihapus lebih dulu (post-order) lewat DeleteEntry, sehingga blok setiap entri
+// dibebaskan (lewat Allocator disk untuk file) dan file yang masih terbuka mengikuti aturan unlink.
+func (fs *FileSystem) RemoveAllProgress(p string, progress ProgressFunc) error {
+	parentBlock, name, err := fs.resolveParent(p)
+	if errors.Is(err, ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	entry, err := fs.findEntry(parentBlock, name)
+	if errors.Is(err, ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	total, err := fs.MeasureTree(p)
+	if err != nil {
+		return err
+	}
+	if entry.Type == TYPE_DIRECTORY {
+		inside, err := fs.isInsideDirectory(fs.CurrentDirectoryBlock, entry.StartBlock)
+		if err != nil {
+			return err
+		}
+		if inside {
+			return fmt.Errorf("tidak dapat menghapus '%s' karena berisi direktori kerja saat ini", p)
+		}
+	}
+
+	var done int64
+	var removeTree func(parentBlock BlockID, entry DirectoryEntry, entryPath string) error
+	removeTree = func(parentBlock BlockID, entry DirectoryEntry, entryPath string) error {
+		name := entry.NameString()
+		if entry.Type == TYPE_DIRECTORY {
+			children, err := fs.ListEntries(entry.StartBlock)
+			if err != nil {
+				return err
+			}
+			for _, child := range children {
+				childName := child.NameString()
+				if childName == "." || childName == ".." {
+					continue
+				}
+				if err := removeTree(entry.StartBlock, child, path.Join(entryPath, childName)); err != nil {
+					return err
+				}
+			}
+		}
+		if err := fs.DeleteEntry(parentBlock, name); err != nil {
+			return fmt.Errorf("gagal menghapus '%s': %w", entryPath, err)
+		}
+		if entry.Type != TYPE_DIRECTORY {
+			done += entry.Size
+		}
+		if progress != nil {
+			progress(done, total.Bytes, entryPath)
+		}
+		return nil
+	}
+	return removeTree(parentBlock, entry, p)
+}
@@ -0,0 +1,72 @@
+package filesystem_logic
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// RemoveAll menghapus seluruh pohon dan membebaskan bloknya; file yang masih terbuka mengikuti aturan unlink.
+func TestRemoveAll(t *testing.T) {
+	fs := newTestDisk(t, Geometry{})
+	free := fs.countFreeBlocks()
+	for _, dir := range []string{"/a", "/a/b", "/a/b/c", "/a/d"} {
+		if err := fs.Mkdir(dir); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i := 0; i < 12; i++ {
+		dir := []string{"/a", "/a/b", "/a/b/c", "/a/d"}[i%4]
+		if err := fs.WriteFile(fmt.Sprintf("%s/f%d", dir, i), make([]byte, i*40)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	size, err := fs.MeasureTree("/a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	open, err := fs.Open("/a/b/c/f2", O_RDONLY)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var calls int
+	var done int64
+	if err := fs.RemoveAllProgress("/a", func(d, total int64, _ string) { calls++; done = d }); err != nil {
+		t.Fatal(err)
+	}
+	if calls != size.Files+size.Directories || done != size.Bytes {
+		t.Fatalf("progress dipanggil %d kali sampai %d byte, seharusnya %d kali dan %d byte", calls, done, size.Files+size.Directories, size.Bytes)
+	}
+	if _, err := fs.Lookup("/a"); !errors.Is(err, ErrNotExist) {
+		t.Fatalf("/a masih ada: %v", err)
+	}
+	if data := make([]byte, 80); true {
+		if n, err := open.Read(data); err != nil || n != 80 {
+			t.Fatalf("file terbuka yang ikut terhapus tidak bisa dibaca: %d, %v", n, err)
+		}
+	}
+	open.Close()
+	if fs.countFreeBlocks() != free {
+		t.Fatalf("%d blok kosong setelah RemoveAll, seharusnya %d", fs.countFreeBlocks(), free)
+	}
+	if err := fs.RemoveAll("/tidak/ada"); err != nil {
+		t.Fatalf("RemoveAll path yang tidak ada: %v", err)
+	}
+}
+
+// Direktori yang berisi direktori kerja saat ini tidak boleh dihapus.
+func TestRemoveAllRefusesWorkingDirectory(t *testing.T) {
+	fs := newTestDisk(t, Geometry{})
+	fs.Mkdir("/a")
+	fs.Mkdir("/a/b")
+	if err := fs.ChangeDirectory("/a/b"); err != nil {
+		t.Fatal(err)
+	}
+	if err := fs.RemoveAll("/a"); err == nil {
+		t.Fatal("RemoveAll direktori induk dari direktori kerja seharusnya ditolak")
+	}
+	if wd, err := fs.Getwd(); err != nil || wd != "/a/b" {
+		t.Fatalf("Getwd = '%s', %v", wd, err)
+	}
+}
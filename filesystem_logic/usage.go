@@ -0,0 +1,111 @@
+// usage.go
+package filesystem_logic
+
+import (
+	"fmt"
+)
+
+// DiskUsage: Ringkasan pemakaian disk, termasuk internal fragmentation
+// (byte yang terbuang di blok terakhir setiap file karena blok selalu dialokasikan utuh).
+type DiskUsage struct {
+	Geometry     Geometry
+	FreeBlocks   int   // Blok bertanda FAT_FREE
+	SystemBlocks int   // Blok bertanda FAT_RESERVED (superblock, reserved, area FAT)
+	Files        int   // Jumlah file (termasuk symlink) di seluruh pohon direktori; hard link dihitung sekali
+	Directories  int   // Jumlah direktori (termasuk root)
+	FileBytes    int64 // Total ukuran isi file (jumlah Size)
+	FileBlocks   int   // Total blok yang dipakai data file
+	IndexBlocks  int   // Blok metadata alokasi milik file (blok indeks dan pointer pada alokasi berindeks)
+	SlackBytes   int64 // Internal fragmentation: FileBlocks*BlockSize - FileBytes
+	FreeRuns     int   // Jumlah run blok kosong berurutan (lihat contiguous.go)
+	LargestRun   int   // Panjang run blok kosong terpanjang
+}
+
+// FragmentationPercent: Persentase ruang blok file yang terbuang.
+func (u DiskUsage) FragmentationPercent() float64 {
+	allocated := int64(u.FileBlocks) * int64(u.Geometry.BlockSize)
+	if allocated == 0 {
+		return 0
+	}
+	return float64(u.SlackBytes) * 100 / float64(allocated)
+}
+
+// ExternalFragmentationPercent: Persentase blok kosong yang berada di luar run kosong terpanjang, yaitu
+// ruang kosong yang tidak bisa dipakai untuk satu extent sepanjang mungkin pada alokasi berurutan.
+func (u DiskUsage) ExternalFragmentationPercent() float64 {
+	if u.FreeBlocks == 0 {
+		return 0
+	}
+	return float64(u.FreeBlocks-u.LargestRun) * 100 / float64(u.FreeBlocks)
+}
+
+// ComputeDiskUsage: Menelusuri seluruh pohon direktori dari root dan menghitung pemakaian disk.
+func (fs *FileSystem) ComputeDiskUsage() (DiskUsage, error) {
+	usage := DiskUsage{Geometry: fs.Geometry}
+	for _, run := range fs.freeRuns() {
+		usage.FreeRuns++
+		usage.LargestRun = max(usage.LargestRun, run.Length)
+	}
+	for _, next := range fs.FAT {
+		switch next {
+		case FAT_FREE:
+			usage.FreeBlocks++
+		case FAT_RESERVED:
+			usage.SystemBlocks++
+		}
+	}
+
+	// Grup hard link memakai rantai blok yang sama, jadi blok dan ukurannya hanya dihitung sekali
+	type fileID struct {
+		start BlockID
+		birth int64
+	}
+	seen := map[fileID]bool{}
+
+	var walk func(dirBlock BlockID) error
+	walk = func(dirBlock BlockID) error {
+		usage.Directories++
+		entries, err := fs.listEntries(dirBlock)
+		if err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			name := entry.NameString()
+			if name == "." || name == ".." {
+				continue
+			}
+			if entry.Type == TYPE_DIRECTORY {
+				if err := walk(entry.StartBlock); err != nil {
+					return err
+				}
+				continue
+			}
+			if entry.hasLinks() {
+				id := fileID{entry.StartBlock, entry.BirthTime}
+				if seen[id] {
+					continue
+				}
+				seen[id] = true
+			}
+			blocks, err := fs.allocator().Blocks(entry)
+			if err != nil {
+				return fmt.Errorf("file '%s': %w", name, err)
+			}
+			meta, err := fs.allocator().MetaBlocks(entry)
+			if err != nil {
+				return fmt.Errorf("file '%s': %w", name, err)
+			}
+			usage.Files++
+			usage.FileBytes += entry.Size
+			usage.FileBlocks += len(blocks)
+			usage.IndexBlocks += len(meta)
+		}
+		return nil
+	}
+	if err := walk(fs.RootDirBlock); err != nil {
+		return usage, fmt.Errorf("gagal menghitung pemakaian disk: %w", err)
+	}
+
+	usage.SlackBytes = int64(usage.FileBlocks)*int64(fs.Geometry.BlockSize) - usage.FileBytes
+	return usage, nil
+}
@@ -0,0 +1,240 @@
+package main
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"filesystemsimulator/filesystem_logic"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// Jumlah byte per baris tampilan hex
+const hexBytesPerLine = 16
+
+// Jendela View > Block Inspector: isi mentah satu blok disk di panel aktif dalam bentuk hex dan ASCII, ditambah
+// decode setiap slot jika blok itu milik direktori. Mode expert mengizinkan menimpa byte secara langsung
+// (filesystem_logic.PatchBlock) untuk menyuntikkan kerusakan lalu melihat reaksi ListEntries dan pemeriksa disk.
+// Jika jendela sudah terbuka, blok yang ditampilkan diganti dengan block.
+func (p *diskPane) showBlockInspectorWindow(block filesystem_logic.BlockID) {
+	p.inspectedBlock = block
+	if p.inspectorWindow != nil {
+		p.refreshInspector()
+		p.inspectorWindow.RequestFocus()
+		return
+	}
+
+	w := fyne.CurrentApp().NewWindow("Block Inspector - " + p.title)
+	w.Resize(fyne.NewSize(980, 560))
+	p.inspectorWindow = w
+
+	blockEntry := widget.NewEntry()
+	infoLabel := widget.NewLabel("")
+	problemLabel := widget.NewLabel("") // Kerusakan yang ditemukan BlockMap, seperti di jendela Block Map
+	problemLabel.Importance = widget.DangerImportance
+	problemLabel.Wrapping = fyne.TextWrapWord
+	problemLabel.Hide()
+	hexLabel := widget.NewLabel("")
+	hexLabel.TextStyle = fyne.TextStyle{Monospace: true}
+	slotsLabel := widget.NewLabel("")
+	slotsLabel.TextStyle = fyne.TextStyle{Monospace: true}
+
+	refresh := func() {
+		block := p.inspectedBlock
+		blockEntry.SetText(fmt.Sprint(block))
+		data, err := p.fs.ReadBlock(block)
+		if err != nil {
+			infoLabel.SetText(err.Error())
+			hexLabel.SetText("")
+			slotsLabel.SetText("")
+			return
+		}
+		hexLabel.SetText(hexDump(data))
+
+		blocks, errMap := p.fs.BlockMap()
+		if errMap != nil {
+			problemLabel.SetText(errMap.Error()) // Bukan dialog: refresh dipanggil lagi setiap kali byte ditulis
+			problemLabel.Show()
+		} else {
+			problemLabel.Hide()
+		}
+		info := blocks[block]
+		owner := info.Owner
+		if owner == "" {
+			owner = "-"
+		}
+		infoLabel.SetText(fmt.Sprintf("Block %d • %s • %s • FAT[%d] = %s", block, info.Kind, owner, block, fatValueText(info.FAT)))
+
+		if info.Kind != filesystem_logic.BLOCK_DIRECTORY {
+			slotsLabel.SetText("Not a directory block: no entries to decode.")
+			return
+		}
+		slots, errSlots := p.fs.DirectorySlots(block)
+		if errSlots != nil {
+			slotsLabel.SetText(errSlots.Error())
+			return
+		}
+		slotsLabel.SetText(slotsText(slots))
+	}
+	p.refreshInspector = refresh
+
+	showBlock := func(block filesystem_logic.BlockID) {
+		if block < 0 || int(block) >= p.fs.Geometry.TotalBlocks {
+			dialog.ShowError(fmt.Errorf("nomor blok harus 0 sampai %d", p.fs.Geometry.TotalBlocks-1), w)
+			return
+		}
+		p.inspectedBlock = block
+		refresh()
+	}
+	blockEntry.OnSubmitted = func(text string) {
+		block, err := strconv.Atoi(strings.TrimSpace(text))
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("nomor blok tidak valid: %q", text), w)
+			return
+		}
+		showBlock(filesystem_logic.BlockID(block))
+	}
+	prevButton := widget.NewButton("◀", func() { showBlock(p.inspectedBlock - 1) })
+	nextButton := widget.NewButton("▶", func() { showBlock(p.inspectedBlock + 1) })
+	goButton := widget.NewButton("Go", func() { blockEntry.OnSubmitted(blockEntry.Text) })
+
+	// Mode expert: offset (desimal atau 0x..) dan byte hex yang akan ditulis ke blok
+	offsetEntry := widget.NewEntry()
+	offsetEntry.SetPlaceHolder("Offset (e.g. 29 or 0x1D)")
+	bytesEntry := widget.NewEntry()
+	bytesEntry.SetPlaceHolder("Hex bytes (e.g. FF FF FF 7F)")
+	writeButton := widget.NewButton("Write Bytes", func() {
+		offset, err := strconv.ParseInt(strings.TrimSpace(offsetEntry.Text), 0, 32)
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("offset tidak valid: %q", offsetEntry.Text), w)
+			return
+		}
+		data, err := hex.DecodeString(strings.Join(strings.Fields(bytesEntry.Text), ""))
+		if err != nil || len(data) == 0 {
+			dialog.ShowError(fmt.Errorf("byte hex tidak valid: %q", bytesEntry.Text), w)
+			return
+		}
+		if err := p.fs.PatchBlock(p.inspectedBlock, int(offset), data); err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		fmt.Printf("Inspector: %d byte ditulis ke blok %d offset %d.\n", len(data), p.inspectedBlock, offset)
+		p.refreshUI() // Daftar file, status bar, dan jendela lain ikut membaca disk yang sudah diubah
+	})
+	expertControls := []fyne.Disableable{offsetEntry, bytesEntry, writeButton}
+	setExpert := func(on bool) {
+		for _, control := range expertControls {
+			if on {
+				control.Enable()
+			} else {
+				control.Disable()
+			}
+		}
+	}
+	setExpert(false)
+	var expertCheck *widget.Check
+	expertCheck = widget.NewCheck("Expert mode (edit raw bytes)", func(on bool) {
+		if !on {
+			setExpert(false)
+			return
+		}
+		dialog.ShowConfirm("Expert Mode",
+			"Edits are written straight to the disk, bypassing the file system.\nThey can corrupt directories and the FAT. Continue?",
+			func(ok bool) {
+				if !ok {
+					expertCheck.SetChecked(false)
+					return
+				}
+				setExpert(true)
+			}, w)
+	})
+
+	refresh()
+
+	w.SetOnClosed(func() {
+		p.inspectorWindow = nil
+		p.refreshInspector = nil
+	})
+	top := container.NewVBox(
+		container.NewBorder(nil, nil, widget.NewLabel("Block:"), container.NewHBox(prevButton, nextButton, goButton), blockEntry),
+		infoLabel,
+		problemLabel,
+	)
+	bottom := container.NewVBox(
+		widget.NewSeparator(),
+		expertCheck,
+		container.NewBorder(nil, nil, nil, writeButton, container.NewGridWithColumns(2, offsetEntry, bytesEntry)),
+	)
+	split := container.NewHSplit(
+		widget.NewCard("Raw Bytes", "", container.NewScroll(hexLabel)),
+		widget.NewCard("Directory Entries", fmt.Sprintf("%d-byte slots", filesystem_logic.DIRECTORY_ENTRY_SIZE), container.NewScroll(slotsLabel)),
+	)
+	split.Offset = 0.55
+	w.SetContent(container.NewBorder(top, bottom, nil, nil, split))
+	w.Show()
+}
+
+// defaultInspectedBlock: Blok pertama entri yang dipilih di daftar file, atau blok direktori saat ini jika
+// tidak ada yang dipilih (atau entri belum punya blok).
+func (p *diskPane) defaultInspectedBlock() filesystem_logic.BlockID {
+	if targetPath, ok := p.selectedPath(); ok {
+		if entry, err := p.fs.Lstat(targetPath); err == nil && entry.StartBlock >= 0 {
+			return entry.StartBlock
+		}
+	}
+	return p.fs.CurrentDirectoryBlock
+}
+
+// hexDump: Baris "offset  byte hex  |ASCII|" untuk isi blok; byte yang tidak bisa dicetak ditampilkan sebagai titik.
+func hexDump(data []byte) string {
+	var sb strings.Builder
+	for line := 0; line < len(data); line += hexBytesPerLine {
+		chunk := data[line:min(line+hexBytesPerLine, len(data))]
+		fmt.Fprintf(&sb, "%04X  ", line)
+		for i := 0; i < hexBytesPerLine; i++ {
+			if i < len(chunk) {
+				fmt.Fprintf(&sb, "%02X ", chunk[i])
+			} else {
+				sb.WriteString("   ")
+			}
+			if i == hexBytesPerLine/2-1 {
+				sb.WriteByte(' ')
+			}
+		}
+		sb.WriteString(" |")
+		for _, c := range chunk {
+			if c >= 0x20 && c < 0x7F {
+				sb.WriteByte(c)
+			} else {
+				sb.WriteByte('.')
+			}
+		}
+		sb.WriteString("|\n")
+	}
+	return sb.String()
+}
+
+// slotsText: Hasil decode slot direktori, satu field per baris dengan offset absolut di dalam blok
+// (sama dengan kolom offset tampilan hex) agar bisa langsung dipakai di mode expert.
+func slotsText(slots []filesystem_logic.DirectorySlot) string {
+	var sb strings.Builder
+	for i, slot := range slots {
+		fmt.Fprintf(&sb, "Slot %d @ %04X: %s\n", i, slot.Offset, slot.Kind)
+		for _, field := range slot.Fields {
+			where := "inode"
+			if field.Offset >= 0 {
+				where = fmt.Sprintf("%04X", slot.Offset+field.Offset)
+			}
+			fmt.Fprintf(&sb, "  %-12s %s +%-2d %s\n", field.Name, where, field.Size, field.Value)
+		}
+		if slot.Err != nil {
+			fmt.Fprintf(&sb, "  error: %v\n", slot.Err)
+		}
+	}
+	return sb.String()
+}
@@ -4,9 +4,11 @@ package main
 import (
 	"bytes"
 	"fmt"
+	"io/fs"
 	"log"
-	"strings" // Import package strings
-	"time"    // For time formatting
+	"path"
+	"strconv"
+	"time" // For time formatting
 
 	"filesystemsimulator/filesystem_logic" // SESUAIKAN NAMA MODULMU
 
@@ -20,70 +22,315 @@ import (
 	"fyne.io/fyne/v2/widget"
 )
 
+// diskPane: Satu panel explorer untuk satu disk. GUI menampilkan dua panel berdampingan
+// sehingga dua disk bisa dibuka bersamaan dan file bisa disalin di antara keduanya.
+type diskPane struct {
+	title            string
+	fs               *filesystem_logic.FileSystem
+	currentEntries   []filesystem_logic.DirectoryEntry
+	currentImagePath string                   // Image disk di host yang di-mount saat startup
+	titleLabel       *widget.Label            // Nama panel, ditandai jika sedang aktif
+	pathLabel        *widget.Label            // Path direktori saat ini
+	diskInfoLabel    *widget.Label            // Status bar: geometri disk, blok kosong, internal fragmentation
+	fileListWidget   *widget.List             // Daftar isi direktori saat ini
+	selectedItemID   widget.ListItemID        // Track selected item ID
+	other            *diskPane                // Panel di sebelahnya (tujuan "Copy to Other Disk")
+	handlesWindow    fyne.Window              // Jendela Open Handles untuk disk ini (nil jika tidak dibuka)
+	refreshHandles   func()                   // Memperbarui jendela Open Handles, nil jika tidak dibuka
+	blockMapWindow   fyne.Window              // Jendela Block Map untuk disk ini (nil jika tidak dibuka)
+	refreshBlockMap  func()                   // Memperbarui jendela Block Map, nil jika tidak dibuka
+	inspectorWindow  fyne.Window              // Jendela Block Inspector untuk disk ini (nil jika tidak dibuka)
+	refreshInspector func()                   // Memperbarui jendela Block Inspector, nil jika tidak dibuka
+	inspectedBlock   filesystem_logic.BlockID // Blok yang ditampilkan di Block Inspector
+	timeColumn       string                   // Timestamp yang ditampilkan di daftar file (lihat timeColumns)
+}
+
 // Variabel global
-var fsInstance *filesystem_logic.FileSystem
-var currentEntries []filesystem_logic.DirectoryEntry
 var myWindow fyne.Window
-var currentPathString string = "/"        // Menyimpan path string saat ini, mulai dari root
-var pathLabel *widget.Label               // Jadikan pathLabel global agar mudah diupdate
-var fileListWidget *widget.List           // Jadikan fileListWidget global
-var selectedItemID widget.ListItemID = -1 // Track selected item ID
-
-// Fungsi untuk mengupdate global currentPathString setelah cd berhasil
-func updateGlobalPathString(targetName string) {
-	if targetName == "/" {
-		currentPathString = "/"
-		return
+var panes []*diskPane
+var activePane *diskPane // Panel yang terakhir dipakai, tujuan aksi menu File
+
+// Menandai panel sebagai aktif agar menu File bekerja pada disk yang sedang dilihat pengguna
+func setActivePane(p *diskPane) {
+	activePane = p
+	for _, pane := range panes {
+		if pane == p {
+			pane.titleLabel.SetText(pane.title + " (active)")
+		} else {
+			pane.titleLabel.SetText(pane.title)
+		}
 	}
+}
 
-	if targetName == ".." {
-		if currentPathString == "/" { // Jika sudah di root, ".." tidak mengubah path
-			return
-		}
-		lastSlash := strings.LastIndex(currentPathString, "/")
-		if lastSlash == 0 { // Contoh: dari "/folderA" menjadi "/"
-			currentPathString = "/"
-		} else if lastSlash > 0 { // Contoh: dari "/folderA/folderB" menjadi "/folderA"
-			currentPathString = currentPathString[:lastSlash]
-		}
-		return
+// Fungsi untuk me-refresh tampilan daftar file dan path label
+func (p *diskPane) refreshUI() {
+	var err error
+	p.currentEntries, err = p.fs.ListEntries(p.fs.CurrentDirectoryBlock)
+	if err != nil {
+		dialog.ShowError(fmt.Errorf("gagal membaca direktori (Blok %d): %w", p.fs.CurrentDirectoryBlock, err), myWindow)
+		p.currentEntries = []filesystem_logic.DirectoryEntry{}
+	}
+	// Update path label dengan style seperti Finder di Mac. Path dihitung dari pohon direktori di disk
+	// (fs.Getwd), jadi label selalu sesuai dengan direktori kerja yang sebenarnya.
+	cwd, errWd := p.fs.Getwd()
+	if errWd != nil {
+		cwd = "?"
+	}
+	pathText := fmt.Sprintf("%s • Block %d", cwd, p.fs.CurrentDirectoryBlock)
+	p.pathLabel.SetText(pathText)
+	p.updateDiskInfo()
+	if p.refreshHandles != nil {
+		p.refreshHandles()
+	}
+	if p.refreshBlockMap != nil {
+		p.refreshBlockMap()
+	}
+	if p.refreshInspector != nil {
+		p.refreshInspector()
 	}
 
-	if targetName == "." { // "." tidak mengubah path
+	p.fileListWidget.Refresh() // Memberitahu Fyne untuk merender ulang list widget
+}
+
+// Kembali ke root dan membersihkan pilihan, dipakai setelah disk diganti (format/open image)
+func (p *diskPane) resetView() {
+	p.fileListWidget.UnselectAll()
+	p.selectedItemID = -1
+	p.refreshUI()
+}
+
+// Memperbarui status bar dengan geometri disk, internal fragmentation, dan biaya manajemen ruang kosong saat ini
+func (p *diskPane) updateDiskInfo() {
+	usage, err := p.fs.ComputeDiskUsage()
+	if err != nil {
+		p.diskInfoLabel.SetText(err.Error())
 		return
 	}
+	p.diskInfoLabel.SetText(fmt.Sprintf("%s • %d free blocks • %d files, %d bytes in %d blocks • Internal fragmentation: %d bytes (%.1f%%)",
+		usage.Geometry, usage.FreeBlocks, usage.Files, usage.FileBytes, usage.FileBlocks,
+		usage.SlackBytes, usage.FragmentationPercent()))
+	if usage.Geometry.Allocation == filesystem_logic.ALLOC_CONTIGUOUS {
+		p.diskInfoLabel.SetText(fmt.Sprintf("%s • External fragmentation: %.1f%% (%d free runs, largest %d blocks)",
+			p.diskInfoLabel.Text, usage.ExternalFragmentationPercent(), usage.FreeRuns, usage.LargestRun))
+	}
+	if usage.IndexBlocks > 0 {
+		p.diskInfoLabel.SetText(fmt.Sprintf("%s • Index blocks: %d", p.diskInfoLabel.Text, usage.IndexBlocks))
+	}
+	if used, total := p.fs.InodeUsage(); total > 0 {
+		p.diskInfoLabel.SetText(fmt.Sprintf("%s • Inodes: %d/%d used", p.diskInfoLabel.Text, used, total))
+	}
+	free := p.fs.FreeSpaceStats()
+	p.diskInfoLabel.SetText(fmt.Sprintf("%s • Free space: %s (%d metadata blocks), %.1f blocks read per search (last %d), %d read for %d updates",
+		p.diskInfoLabel.Text, free.Method, free.MetaBlocks, free.AvgSearchReads(), free.LastSearchReads, free.UpdateReads, free.Updates))
+}
 
-	// Menambahkan nama direktori baru ke path
-	if currentPathString == "/" {
-		currentPathString += targetName
-	} else {
-		currentPathString += "/" + targetName
+// Pilihan kolom waktu di daftar file, sesuai empat timestamp setiap entri
+var timeColumns = []string{"Modified", "Accessed", "Changed", "Created"}
+
+// Teks kolom waktu entri sesuai pilihan p.timeColumn
+func (p *diskPane) timeText(entry filesystem_logic.DirectoryEntry) string {
+	var nanos int64
+	switch p.timeColumn {
+	case "Accessed":
+		nanos = entry.AccessTime
+	case "Changed":
+		nanos = entry.ChangeTime
+	case "Created":
+		nanos = entry.BirthTime
+	default:
+		nanos = entry.ModTime
 	}
+	return fmt.Sprintf("%s: %s", p.timeColumn, time.Unix(0, nanos).Format("2006-01-02 15:04:05"))
 }
 
-// Fungsi untuk me-refresh tampilan daftar file dan path label
-func refreshUI() { // Menggantikan refreshFileList dan lebih fokus pada UI update
-	var err error
-	currentEntries, err = filesystem_logic.ListEntries(fsInstance.CurrentDirectoryBlock)
+// Mengambil nama entri sebagai string (nama panjang jika ada, lihat filesystem_logic/lfn.go)
+func entryName(entry filesystem_logic.DirectoryEntry) string {
+	return entry.NameString()
+}
+
+// Deskripsi singkat ukuran pohon untuk dialog konfirmasi
+func describeTree(size filesystem_logic.TreeSize) string {
+	return fmt.Sprintf("%d file(s), %d folder(s), %d bytes total", size.Files, size.Directories, size.Bytes)
+}
+
+// Menjalankan operasi rekursif (RemoveAll/CopyTo) di goroutine terpisah sambil menampilkan
+// dialog progress. done dipanggil di thread UI setelah operasi selesai.
+func runWithProgress(title string, op func(progress filesystem_logic.ProgressFunc) error, done func(error)) {
+	bar := widget.NewProgressBar()
+	current := widget.NewLabel("")
+	current.Truncation = fyne.TextTruncateEllipsis
+	progressDialog := dialog.NewCustomWithoutButtons(title, container.NewVBox(bar, current), myWindow)
+	progressDialog.Resize(fyne.NewSize(420, 120))
+	progressDialog.Show()
+
+	go func() {
+		err := op(func(doneBytes, totalBytes int64, entryPath string) {
+			fyne.Do(func() {
+				if totalBytes > 0 {
+					bar.SetValue(float64(doneBytes) / float64(totalBytes))
+				}
+				current.SetText(entryPath)
+			})
+		})
+		fyne.Do(func() {
+			progressDialog.Hide()
+			done(err)
+		})
+	}()
+}
+
+// Path absolut entri terpilih di direktori kerja panel
+func (p *diskPane) selectedPath() (string, bool) {
+	if p.selectedItemID < 0 || p.selectedItemID >= len(p.currentEntries) {
+		return "", false
+	}
+	cwd, err := p.fs.Getwd()
 	if err != nil {
-		dialog.ShowError(fmt.Errorf("gagal membaca direktori (Blok %d): %w", fsInstance.CurrentDirectoryBlock, err), myWindow)
-		currentEntries = []filesystem_logic.DirectoryEntry{}
+		return "", false
+	}
+	return path.Join(cwd, entryName(p.currentEntries[p.selectedItemID])), true
+}
+
+// Semua direktori di disk (path absolut), dipakai sebagai pilihan tujuan di dialog Move To
+func (p *diskPane) directoryPaths() []string {
+	dirs := []string{}
+	fs.WalkDir(filesystem_logic.NewIOFS(p.fs), ".", func(name string, d fs.DirEntry, err error) error {
+		if err == nil && d.IsDir() {
+			dirs = append(dirs, path.Join("/", name))
+		}
+		return nil
+	})
+	return dirs
+}
+
+// Dialog File > Format New Disk: memilih geometri disk lalu memformat ulang disk di panel aktif.
+// Berguna untuk membandingkan internal fragmentation dengan ukuran blok yang berbeda.
+func formatDiskDialog() {
+	p := activePane
+	def := filesystem_logic.DefaultGeometry()
+	blockSizeSelect := widget.NewSelect([]string{"256", "512", "1024", "2048", "4096"}, nil)
+	blockSizeSelect.SetSelected(strconv.Itoa(def.BlockSize))
+	totalBlocksSelect := widget.NewSelect([]string{"64", "128", "256", "512", "1024", "2048", "4096"}, nil)
+	totalBlocksSelect.SetSelected(strconv.Itoa(def.TotalBlocks))
+	nameLenSelect := widget.NewSelect([]string{"8", "12", "16", "20", "24", "28"}, nil)
+	nameLenSelect.SetSelected(strconv.Itoa(def.MaxFilenameLen))
+	reservedSelect := widget.NewSelect([]string{"1", "2", "4", "8"}, nil)
+	reservedSelect.SetSelected(strconv.Itoa(def.ReservedBlocks))
+	mirrorCheck := widget.NewCheck("Keep a mirror copy of the FAT", nil)
+	mirrorCheck.SetChecked(def.FATCopies == 2)
+	const noInodes = "None (metadata in entries)"
+	inodeSelect := widget.NewSelect([]string{noInodes, "64", "128", "256", "512", "1024"}, nil)
+	inodeSelect.SetSelected(noInodes)
+	var allocNames []string
+	for _, m := range filesystem_logic.AllocationMethods {
+		allocNames = append(allocNames, m.String())
+	}
+	allocSelect := widget.NewSelect(allocNames, nil)
+	allocSelect.SetSelected(def.Allocation.String())
+	var freeNames []string
+	for _, m := range filesystem_logic.FreeSpaceMethods {
+		freeNames = append(freeNames, m.String())
+	}
+	freeSelect := widget.NewSelect(freeNames, nil)
+	freeSelect.SetSelected(def.FreeSpace.String())
+
+	dialog.ShowForm("Format New Disk ("+p.title+")", "Format", "Cancel",
+		[]*widget.FormItem{
+			widget.NewFormItem("Block Size (bytes)", blockSizeSelect),
+			widget.NewFormItem("Total Blocks", totalBlocksSelect),
+			widget.NewFormItem("Max Short Name Length", nameLenSelect),
+			widget.NewFormItem("Reserved Blocks", reservedSelect),
+			widget.NewFormItem("FAT", mirrorCheck),
+			widget.NewFormItem("Inode Table", inodeSelect),
+			widget.NewFormItem("File Allocation", allocSelect),
+			widget.NewFormItem("Free Space", freeSelect),
+		},
+		func(format bool) {
+			if !format {
+				return
+			}
+			geo := filesystem_logic.Geometry{FATCopies: 1}
+			geo.BlockSize, _ = strconv.Atoi(blockSizeSelect.Selected)
+			geo.TotalBlocks, _ = strconv.Atoi(totalBlocksSelect.Selected)
+			geo.MaxFilenameLen, _ = strconv.Atoi(nameLenSelect.Selected)
+			geo.ReservedBlocks, _ = strconv.Atoi(reservedSelect.Selected)
+			if mirrorCheck.Checked {
+				geo.FATCopies = 2
+			}
+			if inodeSelect.Selected != noInodes {
+				geo.Inodes, _ = strconv.Atoi(inodeSelect.Selected)
+			}
+			for _, m := range filesystem_logic.AllocationMethods {
+				if m.String() == allocSelect.Selected {
+					geo.Allocation = m
+				}
+			}
+			for _, m := range filesystem_logic.FreeSpaceMethods {
+				if m.String() == freeSelect.Selected {
+					geo.FreeSpace = m
+				}
+			}
+
+			if errFormat := p.fs.FormatDisk(geo); errFormat != nil {
+				dialog.ShowError(errFormat, myWindow)
+				return
+			}
+			p.resetView()
+			dialog.ShowInformation("Success", "New disk formatted: "+geo.String(), myWindow)
+		}, myWindow)
+}
+
+// Dialog File > Mount Options: memilih kapan atime diperbarui dan strategi fit alokasi di disk panel aktif (tidak disimpan di image)
+func mountOptionsDialog() {
+	p := activePane
+	modes := []filesystem_logic.AtimeMode{filesystem_logic.ATIME_RELATIME, filesystem_logic.ATIME_STRICT, filesystem_logic.ATIME_NOATIME}
+	var names []string
+	for _, m := range modes {
+		names = append(names, m.String())
 	}
-	// Update path label menggunakan global currentPathString dengan style seperti Finder di Mac
-	pathText := fmt.Sprintf("%s • Block %d", currentPathString, fsInstance.CurrentDirectoryBlock)
-	pathLabel.SetText(pathText)
+	atimeRadio := widget.NewRadioGroup(names, nil)
+	atimeRadio.SetSelected(p.fs.Mount.Atime.String())
+	var fitNames []string
+	for _, f := range filesystem_logic.FitStrategies {
+		fitNames = append(fitNames, f.String())
+	}
+	fitRadio := widget.NewRadioGroup(fitNames, nil)
+	fitRadio.SetSelected(p.fs.Mount.Fit.String())
 
-	fileListWidget.Refresh() // Memberitahu Fyne untuk merender ulang list widget
+	dialog.ShowForm("Mount Options ("+p.title+")", "Apply", "Cancel",
+		[]*widget.FormItem{
+			widget.NewFormItem("Access Time", atimeRadio),
+			widget.NewFormItem("Free Block Search", fitRadio),
+		},
+		func(apply bool) {
+			if !apply {
+				return
+			}
+			for _, m := range modes {
+				if m.String() == atimeRadio.Selected {
+					p.fs.Mount.Atime = m
+				}
+			}
+			for _, f := range filesystem_logic.FitStrategies {
+				if f.String() == fitRadio.Selected {
+					p.fs.Mount.Fit = f
+				}
+			}
+			fmt.Printf("%s: atime mode sekarang %s, alokasi %s.\n", p.title, p.fs.Mount.Atime, p.fs.Mount.Fit)
+		}, myWindow)
 }
 
 // Function to read and display file content
-func fileContentDialog(entry filesystem_logic.DirectoryEntry) {
-	fileName := string(entry.Name[:bytes.IndexByte(entry.Name[:], 0)])
-	data, err := filesystem_logic.ReadFromFile(entry)
+func (p *diskPane) fileContentDialog(entry filesystem_logic.DirectoryEntry) {
+	fileName := entryName(entry)
+	cwd, _ := p.fs.Getwd()
+	filePath := path.Join(cwd, fileName)
+	data, err := p.fs.ReadFile(filePath) // Lewat path agar atime file ikut diperbarui
 	if err != nil {
 		dialog.ShowError(err, myWindow)
 		return
 	}
+	p.refreshUI()
 
 	// Create dialog for viewing and editing file content
 	contentEntry := widget.NewMultiLineEntry()
@@ -91,14 +338,24 @@ func fileContentDialog(entry filesystem_logic.DirectoryEntry) {
 	// Show dialog with file content and save button
 	// Define save action function
 	saveAction := func() {
-		// Save file content
+		// Save file content. Jika isi lama hanya ditambah di akhir atau dipotong, pakai Append/Truncate
+		// agar blok yang tidak berubah tidak ditulis ulang.
 		newData := []byte(contentEntry.Text)
-		err := filesystem_logic.WriteToFile(&entry, fsInstance.CurrentDirectoryBlock, newData)
+		var err error
+		switch {
+		case bytes.HasPrefix(newData, data):
+			err = p.fs.Append(filePath, newData[len(data):])
+		case bytes.HasPrefix(data, newData):
+			err = p.fs.Truncate(filePath, int64(len(newData)))
+		default:
+			err = p.fs.WriteFile(filePath, newData) // Lewat path agar symlink diikuti ke file tujuannya
+		}
 		if err != nil {
 			dialog.ShowError(err, myWindow)
 		} else {
+			data = newData
 			dialog.ShowInformation("Success", "File content saved successfully", myWindow)
-			refreshUI()
+			p.refreshUI()
 		}
 	}
 
@@ -123,23 +380,78 @@ func fileContentDialog(entry filesystem_logic.DirectoryEntry) {
 	fileDialog.Show()
 }
 
-func main() {
-	var err error
-	fsInstance, err = filesystem_logic.NewFileSystem()
+// Dialog File > Open Image: memuat image disk dari host ke panel aktif, lalu kembali ke root
+func openImageDialog() {
+	p := activePane
+	dialog.ShowFileOpen(func(reader fyne.URIReadCloser, err error) {
+		if err != nil {
+			dialog.ShowError(err, myWindow)
+			return
+		}
+		if reader == nil { // Dibatalkan
+			return
+		}
+		imagePath := reader.URI().Path()
+		reader.Close()
+
+		if errLoad := p.fs.LoadImage(imagePath); errLoad != nil {
+			dialog.ShowError(errLoad, myWindow)
+			return
+		}
+		p.currentImagePath = imagePath
+		p.resetView()
+		dialog.ShowInformation("Success", "Disk image '"+imagePath+"' has been opened.", myWindow)
+	}, myWindow)
+}
+
+// Dialog File > Save Image: menyimpan seluruh disk di panel aktif ke file image di host
+func saveImageDialog() {
+	p := activePane
+	dialog.ShowFileSave(func(writer fyne.URIWriteCloser, err error) {
+		if err != nil {
+			dialog.ShowError(err, myWindow)
+			return
+		}
+		if writer == nil { // Dibatalkan
+			return
+		}
+		imagePath := writer.URI().Path()
+		writer.Close()
+
+		if errSave := p.fs.SaveImage(imagePath); errSave != nil {
+			dialog.ShowError(errSave, myWindow)
+			return
+		}
+		p.currentImagePath = imagePath
+		dialog.ShowInformation("Success", "Disk image saved to '"+imagePath+"'.", myWindow)
+	}, myWindow)
+}
+
+// Membuat panel explorer untuk satu disk. Disk di-mount dari imagePath jika file-nya ada,
+// jika tidak disk baru diformat.
+func newDiskPane(title string, imagePath string) (*diskPane, error) {
+	fsInstance, err := filesystem_logic.NewFileSystem(filesystem_logic.FileSystemOptions{ImagePath: imagePath})
 	if err != nil {
-		log.Fatalf("FATAL: Gagal inisialisasi File System: %v", err)
+		return nil, err
 	}
-	fmt.Println("File System Berhasil Diinisialisasi.")
-	myApp := app.New()
-	// Set our custom Mac-like theme
-	myApp.Settings().SetTheme(&MacTheme{})
+	return &diskPane{
+		title:            title,
+		fs:               fsInstance,
+		currentImagePath: imagePath,
+		selectedItemID:   -1,
+		timeColumn:       timeColumns[0],
+	}, nil
+}
 
-	myWindow = myApp.NewWindow("Go File System Explorer")
-	myWindow.Resize(fyne.NewSize(900, 600))
-	myWindow.SetPadded(true)
-	// Inisialisasi widget global
-	pathLabel = widget.NewLabel(currentPathString) // Inisialisasi awal dengan path global
-	pathLabel.TextStyle = fyne.TextStyle{Bold: true}
+// Menyusun widget untuk satu panel: header path, toolbar, daftar file, dan status bar
+func (p *diskPane) buildUI() fyne.CanvasObject {
+	// Inisialisasi widget panel
+	p.titleLabel = widget.NewLabel(p.title)
+	p.titleLabel.TextStyle = fyne.TextStyle{Bold: true}
+	p.pathLabel = widget.NewLabel("/") // Diisi ulang oleh refreshUI
+	p.pathLabel.TextStyle = fyne.TextStyle{Bold: true}
+	p.diskInfoLabel = widget.NewLabel("")
+	p.diskInfoLabel.Truncation = fyne.TextTruncateEllipsis
 
 	// Create a styled header with Mac-like appearance
 	headerBg := canvas.NewRectangle(theme.BackgroundColor())
@@ -147,8 +459,12 @@ func main() {
 
 	// Add padding and styling to the path display
 	pathContainer := container.NewHBox(
+		widget.NewIcon(theme.StorageIcon()),
+		p.titleLabel,
 		widget.NewIcon(theme.FolderIcon()),
-		pathLabel,
+		p.pathLabel,
+		widget.NewIcon(theme.AccountIcon()),
+		p.newUserSelect(),
 	)
 
 	headerContent := container.NewCenter(pathContainer)
@@ -157,54 +473,63 @@ func main() {
 
 	// --- Tombol UP (cd ..) ---
 	upButton := widget.NewButtonWithIcon("Up", theme.NavigateBackIcon(), func() {
+		setActivePane(p)
 		fmt.Println("Tombol Up (..) ditekan.")
-		targetDirName := ".." // Nama direktori yang akan dioper ke ChangeDirectory dan updateGlobalPathString
-		errCd := filesystem_logic.ChangeDirectory(fsInstance, targetDirName)
+		errCd := p.fs.ChangeDirectory("..")
 		if errCd != nil {
 			dialog.ShowError(errCd, myWindow)
-		} else {
-			updateGlobalPathString(targetDirName) // Update path string jika cd berhasil
 		}
-		refreshUI()
+		p.refreshUI()
 	})
 
 	// --- Tombol Open Folder ---
 	openButton := widget.NewButtonWithIcon("Open", theme.FolderOpenIcon(), func() {
-		if selectedItemID < 0 || selectedItemID >= len(currentEntries) {
+		setActivePane(p)
+		if p.selectedItemID < 0 || p.selectedItemID >= len(p.currentEntries) {
 			dialog.ShowInformation("Info", "Select a folder to open first", myWindow)
 			return
 		}
 
-		selectedEntry := currentEntries[selectedItemID]
-		if selectedEntry.Type == filesystem_logic.TYPE_DIRECTORY {
-			targetDirName := string(selectedEntry.Name[:bytes.IndexByte(selectedEntry.Name[:], 0)])
-			if targetDirName == "." || targetDirName == ".." {
-				// Handle special directories
-				errCd := filesystem_logic.ChangeDirectory(fsInstance, targetDirName)
-				if errCd != nil {
-					dialog.ShowError(errCd, myWindow)
-				} else {
-					updateGlobalPathString(targetDirName) // Update path string
-				}
-			} else {
-				// Normal directory
-				errCd := filesystem_logic.ChangeDirectory(fsInstance, targetDirName)
-				if errCd != nil {
-					dialog.ShowError(errCd, myWindow)
-				} else {
-					updateGlobalPathString(targetDirName) // Update path string
-				}
+		selectedEntry := p.currentEntries[p.selectedItemID]
+		if selectedEntry.Type == filesystem_logic.TYPE_DIRECTORY || selectedEntry.Type == filesystem_logic.TYPE_SYMLINK {
+			targetDirName := entryName(selectedEntry) // Symlink diikuti oleh ChangeDirectory
+			errCd := p.fs.ChangeDirectory(targetDirName)
+			if errCd != nil {
+				dialog.ShowError(errCd, myWindow)
 			}
-			refreshUI()
-			fileListWidget.UnselectAll()
-			selectedItemID = -1 // Reset selection after navigating
+			p.refreshUI()
+			p.fileListWidget.UnselectAll()
+			p.selectedItemID = -1 // Reset selection after navigating
 		} else {
 			dialog.ShowInformation("Cannot Open", "Selected item is not a folder", myWindow)
 		}
 	})
 
+	// --- Tombol GO TO (cd ke path absolut atau relatif) ---
+	goToButton := widget.NewButtonWithIcon("Go To", theme.SearchIcon(), func() {
+		setActivePane(p)
+		entryWidget := widget.NewEntry()
+		entryWidget.SetPlaceHolder("/folder/subfolder atau ../folder")
+		dialog.ShowForm("Go To Folder", "Go", "Cancel",
+			[]*widget.FormItem{
+				widget.NewFormItem("Path", entryWidget),
+			},
+			func(pergi bool) {
+				if !pergi || entryWidget.Text == "" {
+					return
+				}
+				if errCd := p.fs.ChangeDirectory(entryWidget.Text); errCd != nil {
+					dialog.ShowError(errCd, myWindow)
+				}
+				p.fileListWidget.UnselectAll()
+				p.selectedItemID = -1
+				p.refreshUI()
+			}, myWindow)
+	})
+
 	// --- Tombol MKDIR ---
 	mkdirButton := widget.NewButtonWithIcon("New Folder", theme.FolderNewIcon(), func() {
+		setActivePane(p)
 		fmt.Println("Tombol New Folder ditekan.")
 		entryWidget := widget.NewEntry()
 		dialog.ShowForm("Create New Folder", "Create", "Cancel",
@@ -218,18 +543,19 @@ func main() {
 				}
 				dirName := entryWidget.Text
 				fmt.Printf("Mencoba membuat direktori: %s\n", dirName)
-				errMkdir := filesystem_logic.CreateDirectory(fsInstance.CurrentDirectoryBlock, dirName)
+				errMkdir := p.fs.CreateDirectory(p.fs.CurrentDirectoryBlock, dirName)
 				if errMkdir != nil {
 					dialog.ShowError(errMkdir, myWindow)
 				} else {
 					dialog.ShowInformation("Success", "Folder '"+dirName+"' has been created.", myWindow)
 				}
-				refreshUI()
+				p.refreshUI()
 			}, myWindow)
 	})
 
 	// --- Tombol CREATE FILE ---
 	createFileButton := widget.NewButtonWithIcon("New File", theme.DocumentCreateIcon(), func() {
+		setActivePane(p)
 		fmt.Println("Tombol Create File ditekan.")
 		entryWidget := widget.NewEntry()
 		dialog.ShowForm("Create New File", "Create", "Cancel",
@@ -243,27 +569,28 @@ func main() {
 				}
 				fileName := entryWidget.Text
 				fmt.Printf("Mencoba membuat file: %s\n", fileName)
-				errCreateFile := filesystem_logic.CreateFile(fsInstance.CurrentDirectoryBlock, fileName)
+				errCreateFile := p.fs.CreateFile(p.fs.CurrentDirectoryBlock, fileName)
 				if errCreateFile != nil {
 					dialog.ShowError(errCreateFile, myWindow)
 				} else {
 					dialog.ShowInformation("Success", "File '"+fileName+"' has been created.", myWindow)
 				}
-				refreshUI()
+				p.refreshUI()
 			}, myWindow)
 	})
 
 	// --- Tombol DELETE ---
 	deleteButton := widget.NewButtonWithIcon("Delete", theme.DeleteIcon(), func() {
-		if selectedItemID < 0 || selectedItemID >= len(currentEntries) {
+		setActivePane(p)
+		if p.selectedItemID < 0 || p.selectedItemID >= len(p.currentEntries) {
 			dialog.ShowInformation("Info", "Select an item to delete first", myWindow)
 			return
 		}
 
-		selectedEntry := currentEntries[selectedItemID]
-		entryName := string(selectedEntry.Name[:bytes.IndexByte(selectedEntry.Name[:], 0)])
+		selectedEntry := p.currentEntries[p.selectedItemID]
+		name := entryName(selectedEntry)
 
-		if entryName == "." || entryName == ".." {
+		if name == "." || name == ".." {
 			dialog.ShowInformation("Cannot Delete", "Cannot delete '.' or '..' special directories", myWindow)
 			return
 		}
@@ -273,116 +600,347 @@ func main() {
 		if selectedEntry.Type == filesystem_logic.TYPE_DIRECTORY {
 			entryType = "folder"
 		}
+		targetPath, _ := p.selectedPath()
+		size, errSize := p.fs.MeasureTree(targetPath)
+		if errSize != nil {
+			dialog.ShowError(errSize, myWindow)
+			return
+		}
+		message := fmt.Sprintf("Are you sure you want to delete %s '%s'?", entryType, name)
+		if selectedEntry.Type == filesystem_logic.TYPE_DIRECTORY {
+			message = fmt.Sprintf("Delete folder '%s' and everything in it?\n%s", name, describeTree(size))
+		}
 		dialog.ShowConfirm(
 			"Delete "+entryType,
-			fmt.Sprintf("Are you sure you want to delete %s '%s'?", entryType, entryName),
+			message,
 			func(confirmed bool) {
-				if confirmed {
-					err := filesystem_logic.DeleteEntry(fsInstance.CurrentDirectoryBlock, entryName)
+				if !confirmed {
+					return
+				}
+				runWithProgress("Deleting '"+name+"'", func(progress filesystem_logic.ProgressFunc) error {
+					return p.fs.RemoveAllProgress(targetPath, progress)
+				}, func(err error) {
 					if err != nil {
 						dialog.ShowError(err, myWindow)
-					} else {
-						refreshUI()
-						selectedItemID = -1 // Reset selection after deletion
 					}
-				}
+					p.fileListWidget.UnselectAll()
+					p.selectedItemID = -1 // Reset selection after deletion
+					p.refreshUI()
+				})
 			},
 			myWindow,
 		)
 	})
 
+	// --- Tombol RENAME ---
+	renameButton := widget.NewButtonWithIcon("Rename", theme.DocumentCreateIcon(), func() {
+		setActivePane(p)
+		oldPath, ok := p.selectedPath()
+		name := ""
+		if ok {
+			name = path.Base(oldPath)
+		}
+		if !ok || name == "." || name == ".." {
+			dialog.ShowInformation("Info", "Select a file or folder to rename first", myWindow)
+			return
+		}
+		entryWidget := widget.NewEntry()
+		entryWidget.SetText(name)
+		dialog.ShowForm("Rename '"+name+"'", "Rename", "Cancel",
+			[]*widget.FormItem{
+				widget.NewFormItem("New Name", entryWidget),
+			},
+			func(ganti bool) {
+				if !ganti || entryWidget.Text == "" {
+					return
+				}
+				if errRename := p.fs.Rename(oldPath, path.Join(path.Dir(oldPath), entryWidget.Text)); errRename != nil {
+					dialog.ShowError(errRename, myWindow)
+				}
+				p.fileListWidget.UnselectAll()
+				p.selectedItemID = -1
+				p.refreshUI()
+			}, myWindow)
+	})
+
+	// --- Tombol MOVE TO ---
+	moveButton := widget.NewButtonWithIcon("Move To...", theme.MailForwardIcon(), func() {
+		setActivePane(p)
+		oldPath, ok := p.selectedPath()
+		name := ""
+		if ok {
+			name = path.Base(oldPath)
+		}
+		if !ok || name == "." || name == ".." {
+			dialog.ShowInformation("Info", "Select a file or folder to move first", myWindow)
+			return
+		}
+		targetSelect := widget.NewSelect(p.directoryPaths(), nil)
+		targetSelect.SetSelected(path.Dir(oldPath))
+		dialog.ShowForm("Move '"+name+"'", "Move", "Cancel",
+			[]*widget.FormItem{
+				widget.NewFormItem("Destination Folder", targetSelect),
+			},
+			func(pindah bool) {
+				if !pindah || targetSelect.Selected == "" {
+					return
+				}
+				if errMove := p.fs.Rename(oldPath, path.Join(targetSelect.Selected, name)); errMove != nil {
+					dialog.ShowError(errMove, myWindow)
+				}
+				p.fileListWidget.UnselectAll()
+				p.selectedItemID = -1
+				p.refreshUI()
+			}, myWindow)
+	})
+
+	// --- Tombol LINK (symlink atau hard link ke entri terpilih) ---
+	linkButton := widget.NewButtonWithIcon("Link", theme.MailForwardIcon(), func() {
+		setActivePane(p)
+		targetPath, ok := p.selectedPath()
+		name := ""
+		if ok {
+			name = path.Base(targetPath)
+		}
+		if !ok || name == "." || name == ".." {
+			dialog.ShowInformation("Info", "Select a file or folder to link to first", myWindow)
+			return
+		}
+		nameWidget := widget.NewEntry()
+		nameWidget.SetText(name + "-link")
+		kindRadio := widget.NewRadioGroup([]string{"Symbolic", "Hard"}, nil)
+		kindRadio.SetSelected("Symbolic")
+		dialog.ShowForm("Link to '"+name+"'", "Create", "Cancel",
+			[]*widget.FormItem{
+				widget.NewFormItem("Link Name", nameWidget),
+				widget.NewFormItem("Kind", kindRadio),
+			},
+			func(buat bool) {
+				if !buat || nameWidget.Text == "" {
+					return
+				}
+				linkPath := path.Join(path.Dir(targetPath), nameWidget.Text)
+				var errLink error
+				if kindRadio.Selected == "Hard" {
+					errLink = p.fs.Link(targetPath, linkPath)
+				} else {
+					errLink = p.fs.Symlink(name, linkPath) // Tujuan relatif: entri di direktori yang sama
+				}
+				if errLink != nil {
+					dialog.ShowError(errLink, myWindow)
+				}
+				p.refreshUI()
+			}, myWindow)
+	})
+
+	// --- Tombol COPY TO OTHER DISK ---
+	copyButton := widget.NewButtonWithIcon("Copy to Other Disk", theme.ContentCopyIcon(), func() {
+		setActivePane(p)
+		srcPath, ok := p.selectedPath()
+		name := ""
+		if ok {
+			name = path.Base(srcPath)
+		}
+		if !ok || name == "." || name == ".." {
+			dialog.ShowInformation("Info", "Select a file or folder to copy first", myWindow)
+			return
+		}
+		size, errSize := p.fs.MeasureTree(srcPath)
+		if errSize != nil {
+			dialog.ShowError(errSize, myWindow)
+			return
+		}
+		otherCwd, _ := p.other.fs.Getwd()
+		dialog.ShowConfirm("Copy '"+name+"'",
+			fmt.Sprintf("Copy '%s' to %s (%s)?\n%s", name, p.other.title, otherCwd, describeTree(size)),
+			func(confirmed bool) {
+				if !confirmed {
+					return
+				}
+				runWithProgress("Copying '"+name+"'", func(progress filesystem_logic.ProgressFunc) error {
+					return p.fs.CopyTo(p.other.fs, srcPath, otherCwd, true, progress)
+				}, func(err error) {
+					p.other.refreshUI()
+					if err != nil {
+						dialog.ShowError(err, myWindow)
+						return
+					}
+					dialog.ShowInformation("Success", fmt.Sprintf("'%s' copied to %s (%s).", name, p.other.title, otherCwd), myWindow)
+				})
+			}, myWindow)
+	})
+
 	// --- List Widget ---
-	fileListWidget = widget.NewList(
-		func() int { return len(currentEntries) },
+	p.fileListWidget = widget.NewList(
+		func() int { return len(p.currentEntries) },
 		func() fyne.CanvasObject {
 			return container.NewHBox(
 				widget.NewIcon(nil),
 				widget.NewLabel("Template"),
+				widget.NewLabel(""), // For permissions and owner
 				widget.NewLabel(""), // For file size
-				widget.NewLabel(""), // For modification time
+				widget.NewLabel(""), // For the selected timestamp
 			)
 		},
 		func(id widget.ListItemID, item fyne.CanvasObject) {
-			if id < 0 || id >= len(currentEntries) {
+			if id < 0 || id >= len(p.currentEntries) {
 				return
 			}
-			entry := currentEntries[id]
-			idx := bytes.IndexByte(entry.Name[:], 0)
-			var name string
-			if idx == -1 {
-				name = string(entry.Name[:])
-			} else {
-				name = string(entry.Name[:idx])
-			}
+			entry := p.currentEntries[id]
+			name := entryName(entry)
 
 			hbox := item.(*fyne.Container)
 			icon := hbox.Objects[0].(*widget.Icon)
 			nameLabel := hbox.Objects[1].(*widget.Label)
-			sizeLabel := hbox.Objects[2].(*widget.Label)
-			timeLabel := hbox.Objects[3].(*widget.Label)
+			ownerLabel := hbox.Objects[2].(*widget.Label)
+			sizeLabel := hbox.Objects[3].(*widget.Label)
+			timeLabel := hbox.Objects[4].(*widget.Label)
+			ownerLabel.SetText(p.ownerText(entry))
 
-			if entry.Type == filesystem_logic.TYPE_DIRECTORY {
+			switch entry.Type {
+			case filesystem_logic.TYPE_DIRECTORY:
 				icon.SetResource(theme.FolderIcon())
 				nameLabel.SetText(name + "/")
 				sizeLabel.SetText("Directory")
-			} else {
+			case filesystem_logic.TYPE_SYMLINK:
+				icon.SetResource(theme.MailForwardIcon())
+				nameLabel.SetText(name)
+				target, _ := p.fs.Readlink(name)
+				sizeLabel.SetText("→ " + target)
+			default:
 				icon.SetResource(theme.FileIcon())
 				nameLabel.SetText(name)
 				sizeLabel.SetText(fmt.Sprintf("%d bytes", entry.Size))
 			}
 
-			// Format modification time
-			unixTime := entry.ModTime / 1e9 // Convert nanoseconds to seconds
-			timeStr := fmt.Sprintf("Modified: %s", time.Unix(unixTime, 0).Format("2006-01-02 15:04:05"))
-			timeLabel.SetText(timeStr)
+			timeLabel.SetText(p.timeText(entry))
 		},
 	)
 
-	fileListWidget.OnSelected = func(id widget.ListItemID) {
-		if id < 0 || id >= len(currentEntries) {
+	p.fileListWidget.OnSelected = func(id widget.ListItemID) {
+		if id < 0 || id >= len(p.currentEntries) {
 			return
 		}
+		setActivePane(p)
 
-		selectedItemID = id // Store the selected ID globally
-		selectedEntry := currentEntries[id]
-		targetDirName := string(selectedEntry.Name[:bytes.IndexByte(selectedEntry.Name[:], 0)])
-		fmt.Printf("Item dipilih: %s, Tipe: %d\n", targetDirName, selectedEntry.Type)
+		p.selectedItemID = id // Store the selected ID
+		selectedEntry := p.currentEntries[id]
+		if p.refreshBlockMap != nil {
+			p.refreshBlockMap() // Sorot blok entri yang baru dipilih
+		}
+		fmt.Printf("Item dipilih: %s, Tipe: %d\n", entryName(selectedEntry), selectedEntry.Type)
 
 		// Don't immediately navigate for directories - just select
-		if selectedEntry.Type == filesystem_logic.TYPE_FILE {
-			// Open file content dialog
-			fileContentDialog(selectedEntry)
-			fileListWidget.UnselectAll()
-			selectedItemID = -1
+		isFile := selectedEntry.Type == filesystem_logic.TYPE_FILE
+		if selectedEntry.Type == filesystem_logic.TYPE_SYMLINK {
+			// Symlink ke file dibuka seperti file; symlink ke direktori dibuka lewat tombol Open
+			target, errTarget := p.fs.Lookup(entryName(selectedEntry))
+			if errTarget != nil {
+				dialog.ShowError(errTarget, myWindow)
+				return
+			}
+			isFile = target.Type != filesystem_logic.TYPE_DIRECTORY
+		}
+		if isFile {
+			// Open file content dialog. Pilihan tetap diingat agar file bisa dihapus/disalin setelahnya.
+			p.fileContentDialog(selectedEntry)
+			p.fileListWidget.UnselectAll()
 		}
 	}
 	// Navigate into directories when selected (since we don't have double-click)
 	// We'll use a separate button for this now
 
+	// Pilihan timestamp yang ditampilkan di kolom waktu
+	timeSelect := widget.NewSelect(timeColumns, func(column string) {
+		p.timeColumn = column
+		p.fileListWidget.Refresh()
+	})
+	timeSelect.Selected = p.timeColumn // Tanpa memanggil OnChanged, list belum dibuat di sini
+
 	// Toolbar with buttons
 	toolbar := container.New(layout.NewHBoxLayout(),
 		upButton,
 		openButton,
+		goToButton,
 		widget.NewSeparator(),
 		mkdirButton,
 		createFileButton,
 		widget.NewSeparator(),
 		deleteButton,
+		renameButton,
+		moveButton,
+		linkButton,
+		copyButton,
+		widget.NewButtonWithIcon("Properties", theme.InfoIcon(), p.showPropertiesDialog),
+		widget.NewButtonWithIcon("Blocks", theme.GridIcon(), p.showAllocationDialog),
+		widget.NewSeparator(),
+		widget.NewIcon(theme.HistoryIcon()),
+		timeSelect,
 	)
 
 	// Susun Layout
-	content := container.NewBorder(
-		container.NewVBox(header, toolbar),  // top
-		nil,                                 // bottom
-		nil,                                 // left
-		nil,                                 // right
-		container.NewPadded(fileListWidget), // center
+	return container.NewBorder(
+		container.NewVBox(header, toolbar),    // top
+		p.diskInfoLabel,                       // bottom
+		nil,                                   // left
+		nil,                                   // right
+		container.NewPadded(p.fileListWidget), // center
 	)
+}
+
+func main() {
+	// Mount image dari sesi sebelumnya jika ada, jika tidak disk baru diformat.
+	// Dua disk dibuka berdampingan agar file bisa disalin di antara keduanya.
+	diskA, err := newDiskPane("Disk A", "disk.img")
+	if err != nil {
+		log.Fatalf("FATAL: Gagal inisialisasi File System: %v", err)
+	}
+	diskB, err := newDiskPane("Disk B", "disk2.img")
+	if err != nil {
+		log.Fatalf("FATAL: Gagal inisialisasi File System kedua: %v", err)
+	}
+	diskA.other, diskB.other = diskB, diskA
+	panes = []*diskPane{diskA, diskB}
+	fmt.Println("File System Berhasil Diinisialisasi.")
+
+	myApp := app.New()
+	// Set our custom Mac-like theme
+	myApp.Settings().SetTheme(&MacTheme{})
+
+	myWindow = myApp.NewWindow("Go File System Explorer")
+	myWindow.Resize(fyne.NewSize(1400, 600))
+	myWindow.SetPadded(true)
+
+	// Menu File untuk memformat dan membuka/menyimpan image disk di panel aktif
+	myWindow.SetMainMenu(fyne.NewMainMenu(
+		fyne.NewMenu("File",
+			fyne.NewMenuItem("Format New Disk...", formatDiskDialog),
+			fyne.NewMenuItem("Mount Options...", mountOptionsDialog),
+			fyne.NewMenuItem("Check Disk...", checkDiskDialog),
+			fyne.NewMenuItemSeparator(),
+			fyne.NewMenuItem("Open Image...", openImageDialog),
+			fyne.NewMenuItem("Save Image...", saveImageDialog),
+		),
+		fyne.NewMenu("View",
+			fyne.NewMenuItem("Block Map...", func() { activePane.showBlockMapWindow() }),
+			fyne.NewMenuItem("Block Inspector...", func() { activePane.showBlockInspectorWindow(activePane.defaultInspectedBlock()) }),
+		),
+		fyne.NewMenu("Processes",
+			fyne.NewMenuItem("Open Handles...", func() { activePane.showOpenHandlesWindow() }),
+		),
+	))
+
+	// Susun Layout: dua panel berdampingan
+	split := container.NewHSplit(diskA.buildUI(), diskB.buildUI())
+	split.SetOffset(0.5)
 
 	// Panggil refreshUI pertama kali
-	refreshUI()
+	for _, pane := range panes {
+		pane.refreshUI()
+	}
+	setActivePane(diskA)
 
-	myWindow.SetContent(content)
+	myWindow.SetContent(split)
 	myWindow.SetMaster()
 	myWindow.ShowAndRun()
 }
@@ -0,0 +1,235 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+
+	"filesystemsimulator/filesystem_logic"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// Pilihan mode akses di dialog Open File, dipetakan ke flag filesystem_logic.Open
+var openModeFlags = map[string]int{
+	"Read (r)":               filesystem_logic.O_RDONLY,
+	"Write (w)":              filesystem_logic.O_WRONLY,
+	"Read/Write (rw)":        filesystem_logic.O_RDWR,
+	"Append (w+a)":           filesystem_logic.O_WRONLY | filesystem_logic.O_APPEND,
+	"Create/Truncate (rw)":   filesystem_logic.O_RDWR | filesystem_logic.O_CREATE | filesystem_logic.O_TRUNC,
+	"Create if missing (rw)": filesystem_logic.O_RDWR | filesystem_logic.O_CREATE,
+}
+
+var openModeOrder = []string{"Read (r)", "Write (w)", "Read/Write (rw)", "Append (w+a)", "Create if missing (rw)", "Create/Truncate (rw)"}
+
+// Jendela Processes > Open Handles: menampilkan tabel file terbuka dari disk di panel aktif dan
+// memungkinkan membuat proses simulasi, membuka file, Dup/Dup2, dan menutup file descriptor.
+func (p *diskPane) showOpenHandlesWindow() {
+	if p.handlesWindow != nil {
+		p.handlesWindow.RequestFocus()
+		return
+	}
+
+	w := fyne.CurrentApp().NewWindow("Open Handles - " + p.title)
+	w.Resize(fyne.NewSize(760, 420))
+	p.handlesWindow = w
+
+	var handles []filesystem_logic.OpenHandle
+	selectedHandle := -1
+
+	processSelect := widget.NewSelect(nil, nil)
+	processSelect.PlaceHolder = "(no process)"
+	currentProcess := func() *filesystem_logic.Process {
+		for _, proc := range p.fs.FileTable.Processes() {
+			if processLabel(proc) == processSelect.Selected {
+				return proc
+			}
+		}
+		return nil
+	}
+
+	handleList := widget.NewList(
+		func() int { return len(handles) },
+		func() fyne.CanvasObject { return widget.NewLabel("Template") },
+		func(id widget.ListItemID, item fyne.CanvasObject) {
+			if id < 0 || id >= len(handles) {
+				return
+			}
+			h := handles[id]
+			owner := "(no process)"
+			fd := "-"
+			if h.PID != 0 {
+				owner = fmt.Sprintf("PID %d %s", h.PID, h.Process)
+				fd = strconv.Itoa(h.FD)
+			}
+			text := fmt.Sprintf("%-18s fd %-3s → file #%d  %-4s %s  offset %d / %d bytes  refs %d",
+				owner, fd, h.FileID, h.Mode, h.Path, h.Offset, h.Size, h.Refs)
+			if h.Unlinked {
+				text += "  (deleted)"
+			}
+			item.(*widget.Label).SetText(text)
+		},
+	)
+	handleList.OnSelected = func(id widget.ListItemID) { selectedHandle = id }
+
+	refresh := func() {
+		var names []string
+		for _, proc := range p.fs.FileTable.Processes() {
+			names = append(names, processLabel(proc))
+		}
+		processSelect.Options = names
+		if currentProcess() == nil {
+			processSelect.ClearSelected()
+			if len(names) > 0 {
+				processSelect.SetSelected(names[len(names)-1])
+			}
+		}
+		processSelect.Refresh()
+
+		handles = p.fs.FileTable.Handles()
+		selectedHandle = -1
+		handleList.UnselectAll()
+		handleList.Refresh()
+	}
+	p.refreshHandles = refresh
+
+	// Mengambil fd yang dipilih di daftar, hanya untuk handle milik proses
+	selectedFD := func() (*filesystem_logic.Process, int, bool) {
+		if selectedHandle < 0 || selectedHandle >= len(handles) || handles[selectedHandle].PID == 0 {
+			dialog.ShowInformation("Info", "Select a file descriptor owned by a process first", w)
+			return nil, -1, false
+		}
+		for _, proc := range p.fs.FileTable.Processes() {
+			if proc.PID == handles[selectedHandle].PID {
+				return proc, handles[selectedHandle].FD, true
+			}
+		}
+		return nil, -1, false
+	}
+
+	newProcessButton := widget.NewButtonWithIcon("New Process", theme.ContentAddIcon(), func() {
+		nameEntry := widget.NewEntry()
+		nameEntry.SetText("proc")
+		dialog.ShowForm("New Process", "Create", "Cancel",
+			[]*widget.FormItem{widget.NewFormItem("Name", nameEntry)},
+			func(buat bool) {
+				if !buat {
+					return
+				}
+				proc := p.fs.NewProcess(nameEntry.Text)
+				refresh()
+				processSelect.SetSelected(processLabel(proc))
+			}, w)
+	})
+
+	exitProcessButton := widget.NewButtonWithIcon("Exit Process", theme.CancelIcon(), func() {
+		proc := currentProcess()
+		if proc == nil {
+			dialog.ShowInformation("Info", "Select a process first", w)
+			return
+		}
+		proc.Exit() // Menutup semua fd milik proses
+		p.refreshUI()
+	})
+
+	openButton := widget.NewButtonWithIcon("Open File...", theme.FileIcon(), func() {
+		proc := currentProcess()
+		if proc == nil {
+			dialog.ShowInformation("Info", "Create or select a process first", w)
+			return
+		}
+		cwd, _ := p.fs.Getwd()
+		pathEntry := widget.NewEntry()
+		pathEntry.SetText(cwd)
+		modeSelect := widget.NewSelect(openModeOrder, nil)
+		modeSelect.SetSelected(openModeOrder[0])
+		dialog.ShowForm("Open File ("+processLabel(proc)+")", "Open", "Cancel",
+			[]*widget.FormItem{
+				widget.NewFormItem("Path", pathEntry),
+				widget.NewFormItem("Mode", modeSelect),
+			},
+			func(buka bool) {
+				if !buka {
+					return
+				}
+				fd, err := proc.Open(pathEntry.Text, openModeFlags[modeSelect.Selected])
+				if err != nil {
+					dialog.ShowError(err, w)
+					return
+				}
+				fmt.Printf("PID %d membuka '%s' sebagai fd %d.\n", proc.PID, pathEntry.Text, fd)
+				p.refreshUI()
+			}, w)
+	})
+
+	dupButton := widget.NewButton("Dup", func() {
+		proc, fd, ok := selectedFD()
+		if !ok {
+			return
+		}
+		if _, err := proc.Dup(fd); err != nil {
+			dialog.ShowError(err, w)
+		}
+		refresh()
+	})
+
+	dup2Button := widget.NewButton("Dup2...", func() {
+		proc, fd, ok := selectedFD()
+		if !ok {
+			return
+		}
+		targetEntry := widget.NewEntry()
+		dialog.ShowForm(fmt.Sprintf("Dup2 fd %d", fd), "Dup2", "Cancel",
+			[]*widget.FormItem{widget.NewFormItem("New fd", targetEntry)},
+			func(jalankan bool) {
+				if !jalankan {
+					return
+				}
+				newFD, err := strconv.Atoi(targetEntry.Text)
+				if err == nil {
+					_, err = proc.Dup2(fd, newFD)
+				}
+				if err != nil {
+					dialog.ShowError(err, w)
+				}
+				p.refreshUI()
+			}, w)
+	})
+
+	closeButton := widget.NewButtonWithIcon("Close fd", theme.DeleteIcon(), func() {
+		proc, fd, ok := selectedFD()
+		if !ok {
+			return
+		}
+		if err := proc.Close(fd); err != nil {
+			dialog.ShowError(err, w)
+		}
+		p.refreshUI() // Blok file yang sudah dihapus bisa saja baru dibebaskan sekarang
+	})
+
+	refreshButton := widget.NewButtonWithIcon("", theme.ViewRefreshIcon(), refresh)
+
+	toolbar := container.NewHBox(
+		widget.NewLabel("Process:"), processSelect,
+		newProcessButton, exitProcessButton,
+		widget.NewSeparator(),
+		openButton, dupButton, dup2Button, closeButton,
+		layout.NewSpacer(), refreshButton,
+	)
+
+	w.SetContent(container.NewBorder(toolbar, nil, nil, nil, handleList))
+	w.SetOnClosed(func() {
+		p.handlesWindow = nil
+		p.refreshHandles = nil
+	})
+	refresh()
+	w.Show()
+}
+
+func processLabel(proc *filesystem_logic.Process) string {
+	return fmt.Sprintf("%d: %s", proc.PID, proc.Name)
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"fmt"
+	"path"
+
+	"filesystemsimulator/filesystem_logic"
+
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// Pemilih pengguna di header panel: operasi berikutnya di disk ini dijalankan sebagai pengguna terpilih
+func (p *diskPane) newUserSelect() *widget.Select {
+	var names []string
+	for _, u := range p.fs.Users {
+		names = append(names, u.Name)
+	}
+	userSelect := widget.NewSelect(names, func(name string) {
+		if err := p.fs.SetUser(name); err != nil {
+			dialog.ShowError(err, myWindow)
+			return
+		}
+		setActivePane(p)
+		fmt.Printf("%s: sekarang berjalan sebagai '%s' (uid %d).\n", p.title, name, p.fs.CurrentUser.UID)
+		p.refreshUI() // Isi direktori kerja bisa saja tidak boleh dibaca pengguna baru
+	})
+	userSelect.Selected = p.fs.CurrentUser.Name // Tanpa memanggil OnChanged, list belum dibuat di sini
+	return userSelect
+}
+
+// Teks pemilik untuk kolom daftar file, misalnya "rw-r--r-- alice:users"
+func (p *diskPane) ownerText(entry filesystem_logic.DirectoryEntry) string {
+	return fmt.Sprintf("%s %d %s:%s", entry.ModeString(), entry.Nlink, p.fs.UserName(entry.UID), p.fs.GroupName(entry.GID))
+}
+
+// Dialog Properties: menampilkan metadata entri terpilih dan mengubah izin (chmod) serta pemilik/grup (chown)
+func (p *diskPane) showPropertiesDialog() {
+	setActivePane(p)
+	targetPath, ok := p.selectedPath()
+	if !ok {
+		dialog.ShowInformation("Info", "Select a file or folder first", myWindow)
+		return
+	}
+	entry, err := p.fs.Lookup(targetPath) // Untuk symlink: entri tujuannya, seperti chmod/chown di Unix
+	if err != nil {
+		dialog.ShowError(err, myWindow)
+		return
+	}
+	linkTarget := "-"
+	if target, errLink := p.fs.Readlink(targetPath); errLink == nil {
+		linkTarget = target
+	}
+
+	// Kotak centang r/w/x untuk pemilik, grup, dan lainnya; indeks 0 = bit 0400
+	var permChecks [9]*widget.Check
+	for i := range permChecks {
+		permChecks[i] = widget.NewCheck("", nil)
+		permChecks[i].SetChecked(entry.Mode&(1<<uint(8-i)) != 0)
+	}
+	permGrid := container.NewGridWithColumns(4,
+		widget.NewLabel(""), widget.NewLabel("Read"), widget.NewLabel("Write"), widget.NewLabel("Execute"),
+		widget.NewLabel("Owner"), permChecks[0], permChecks[1], permChecks[2],
+		widget.NewLabel("Group"), permChecks[3], permChecks[4], permChecks[5],
+		widget.NewLabel("Other"), permChecks[6], permChecks[7], permChecks[8],
+	)
+
+	var userNames, groupNames []string
+	for _, u := range p.fs.Users {
+		userNames = append(userNames, u.Name)
+	}
+	for _, g := range p.fs.Groups {
+		groupNames = append(groupNames, g.Name)
+	}
+	ownerSelect := widget.NewSelect(userNames, nil)
+	ownerSelect.SetSelected(p.fs.UserName(entry.UID))
+	groupSelect := widget.NewSelect(groupNames, nil)
+	groupSelect.SetSelected(p.fs.GroupName(entry.GID))
+
+	kind := "File"
+	size := fmt.Sprintf("%d bytes", entry.Size)
+	if entry.Type == filesystem_logic.TYPE_DIRECTORY {
+		kind = "Directory"
+		size = "-"
+	}
+	name := path.Base(targetPath)
+	shortName := "-"
+	if entry.LongName != "" {
+		shortName = entry.ShortName() // Alias 8.3 yang tersimpan di entri pendek
+	}
+	inode := "-"
+	if entry.Inode != filesystem_logic.NO_INODE {
+		inode = fmt.Sprint(entry.Inode) // Hanya pada disk dengan tabel inode
+	}
+	dialog.ShowForm("Properties: "+name, "Apply", "Cancel",
+		[]*widget.FormItem{
+			widget.NewFormItem("Path", widget.NewLabel(targetPath)),
+			widget.NewFormItem("Short Name", widget.NewLabel(shortName)),
+			widget.NewFormItem("Type", widget.NewLabel(fmt.Sprintf("%s (start block %d)", kind, entry.StartBlock))),
+			widget.NewFormItem("Size", widget.NewLabel(size)),
+			widget.NewFormItem("Link Target", widget.NewLabel(linkTarget)),
+			widget.NewFormItem("Links", widget.NewLabel(fmt.Sprint(entry.Nlink))),
+			widget.NewFormItem("Inode", widget.NewLabel(inode)),
+			widget.NewFormItem("Owner", ownerSelect),
+			widget.NewFormItem("Group", groupSelect),
+			widget.NewFormItem("Permissions", permGrid),
+		},
+		func(apply bool) {
+			if !apply {
+				return
+			}
+			var mode filesystem_logic.FileMode
+			for i, check := range permChecks {
+				if check.Checked {
+					mode |= 1 << uint(8-i)
+				}
+			}
+			uid, gid := entry.UID, entry.GID
+			for _, u := range p.fs.Users {
+				if u.Name == ownerSelect.Selected {
+					uid = u.UID
+				}
+			}
+			for _, g := range p.fs.Groups {
+				if g.Name == groupSelect.Selected {
+					gid = g.GID
+				}
+			}
+
+			// Pemilik/grup diubah lebih dulu (chown), lalu bit izin (chmod)
+			if uid != entry.UID || gid != entry.GID {
+				if err := p.fs.Chown(targetPath, uid, gid); err != nil {
+					dialog.ShowError(err, myWindow)
+					p.refreshUI()
+					return
+				}
+			}
+			if mode != entry.Mode {
+				if err := p.fs.Chmod(targetPath, mode); err != nil {
+					dialog.ShowError(err, myWindow)
+				}
+			}
+			p.refreshUI()
+		}, myWindow)
+}